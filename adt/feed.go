@@ -0,0 +1,264 @@
+// Package adt receives HL7 v2 ADT (admission, discharge and transfer) messages from patient administration and EMPI
+// feeds, keeping cached patients up-to-date and notifying subscribers of changes to patients.
+package adt
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/hl7"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Cache is a cache of patients that is kept up-to-date by events
+type Cache interface {
+	// Invalidate removes any cached patient with the identifiers specified
+	Invalidate(ids ...*apiv1.Identifier)
+	// Refresh replaces any cached patient with the patient specified
+	Refresh(pt *apiv1.Patient)
+}
+
+// Parser parses a HL7 v2 message as a patient event
+type Parser func(msg *hl7.Message) (*apiv1.PatientEvent, error)
+
+// subscriberBuffer is the number of events buffered for each subscriber; events are dropped for subscribers that
+// do not keep up
+const subscriberBuffer = 64
+
+// Feed receives messages using MLLP, parses each as a patient event, updates caches and notifies subscribers.
+// Cached patients are invalidated on each event or, if the feed is trusted to contain complete demographics,
+// refreshed. The patients merged into another patient are always invalidated.
+// Messages are not authenticated, so connections should be limited to the sources of the feed using AllowSources.
+// Subscribers are only sent events for patients they may access.
+// This is thread-safe.
+type Feed struct {
+	parser  Parser
+	refresh bool
+	srv     *hl7.Server
+
+	mu          sync.RWMutex
+	caches      map[string]Cache
+	subscribers map[chan *apiv1.PatientEvent]struct{}
+	recordMerge MergeRecorder
+	closed      bool
+}
+
+// MergeRecorder records that an identifier has been superseded as a result of a merge
+type MergeRecorder func(ctx context.Context, s *apiv1.Supersession) error
+
+// NewFeed creates a feed that parses messages with the parser specified, refreshing cached patients from events
+// if refresh is true, or otherwise only invalidating them
+func NewFeed(parser Parser, refresh bool) *Feed {
+	f := &Feed{
+		parser:      parser,
+		refresh:     refresh,
+		caches:      make(map[string]Cache),
+		subscribers: make(map[chan *apiv1.PatientEvent]struct{}),
+	}
+	f.srv = &hl7.Server{Handler: f}
+	return f
+}
+
+// RegisterCache registers a cache to be kept up-to-date
+func (f *Feed) RegisterCache(name string, c Cache) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.caches[name] = c
+	log.Printf("adt: registered cache: '%s'", name)
+}
+
+// RegisterMergeRecorder registers a function to record the identifiers superseded by merges
+func (f *Feed) RegisterMergeRecorder(r MergeRecorder) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.recordMerge = r
+	log.Printf("adt: registered merge recorder")
+}
+
+// AllowSources limits the connections accepted to those from the sources specified, each an IP address or a
+// network in CIDR notation (e.g. "10.0.0.0/8"). This must be called before Serve.
+func (f *Feed) AllowSources(sources ...string) error {
+	var allowed []*net.IPNet
+	for _, source := range sources {
+		if !strings.Contains(source, "/") {
+			ip := net.ParseIP(source)
+			if ip == nil {
+				return fmt.Errorf("adt: invalid source address: '%s'", source)
+			}
+			allowed = append(allowed, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(source)
+		if err != nil {
+			return fmt.Errorf("adt: invalid source network: %w", err)
+		}
+		allowed = append(allowed, n)
+	}
+	f.srv.Allowed = allowed
+	log.Printf("adt: accepting messages from: %s", strings.Join(sources, " "))
+	return nil
+}
+
+// Serve receives messages from connections to the listener specified until the feed is closed
+func (f *Feed) Serve(lis net.Listener) error {
+	log.Printf("adt: listening for HL7 v2 messages on %s", lis.Addr())
+	return f.srv.Serve(lis)
+}
+
+// ServeHL7 processes a message, updating caches and notifying subscribers
+func (f *Feed) ServeHL7(ctx context.Context, msg *hl7.Message) error {
+	e, err := f.parser(msg)
+	if err != nil {
+		return err
+	}
+	log.Printf("adt: %s event from %s (%s): identifiers: %s merged: %s", e.GetEventType(), e.GetSource(), e.GetMessageControlId(), formatIdentifiers(e.GetPatient().GetIdentifiers()), formatIdentifiers(e.GetMerged()))
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.recordMerge != nil {
+		for _, s := range supersessions(e) {
+			if err := f.recordMerge(ctx, s); err != nil {
+				return err
+			}
+		}
+	}
+	for _, c := range f.caches {
+		if len(e.GetMerged()) > 0 {
+			c.Invalidate(e.GetMerged()...)
+		}
+		if f.refresh {
+			c.Refresh(e.GetPatient())
+		} else {
+			c.Invalidate(e.GetPatient().GetIdentifiers()...)
+		}
+	}
+	for ch := range f.subscribers {
+		select {
+		case ch <- e:
+		default:
+			log.Printf("adt: dropping %s event (%s) for subscriber that is not keeping up", e.GetEventType(), e.GetMessageControlId())
+		}
+	}
+	return nil
+}
+
+// subscribe returns a channel of events, until removed by unsubscribe or closed when the feed is closed
+func (f *Feed) subscribe() chan *apiv1.PatientEvent {
+	ch := make(chan *apiv1.PatientEvent, subscriberBuffer)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		close(ch)
+		return ch
+	}
+	f.subscribers[ch] = struct{}{}
+	return ch
+}
+
+func (f *Feed) unsubscribe(ch chan *apiv1.PatientEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.subscribers, ch)
+}
+
+// Subscribe streams patient events, of the types requested, until the client cancels or the feed is closed.
+// Events for patients the caller may not access are not sent.
+func (f *Feed) Subscribe(r *apiv1.PatientEventsRequest, stream apiv1.PatientEvents_SubscribeServer) error {
+	types := make(map[string]bool)
+	for _, t := range r.GetEventTypes() {
+		types[t] = true
+	}
+	ch := f.subscribe()
+	defer f.unsubscribe(ch)
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e, ok := <-ch:
+			if !ok {
+				return status.Errorf(codes.Unavailable, "patient event feed closed")
+			}
+			if len(types) > 0 && !types[e.GetEventType()] {
+				continue
+			}
+			err := identifiers.CheckAccess(stream.Context(), e.GetPatient())
+			if status.Code(err) == codes.PermissionDenied {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// RegisterServer registers this server
+func (f *Feed) RegisterServer(s *grpc.Server) {
+	apiv1.RegisterPatientEventsServer(s, f)
+}
+
+// RegisterHTTPProxy registers this as a reverse HTTP proxy
+func (f *Feed) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	return apiv1.RegisterPatientEventsHandlerFromEndpoint(ctx, mux, endpoint, opts)
+}
+
+// Close stops receiving messages and ends all subscriptions
+func (f *Feed) Close() error {
+	f.mu.Lock()
+	f.closed = true
+	for ch := range f.subscribers {
+		close(ch)
+		delete(f.subscribers, ch)
+	}
+	f.mu.Unlock()
+	return f.srv.Close()
+}
+
+// supersessions returns the supersession of each identifier merged by an event by the patient's identifier in the
+// same system; merged identifiers for which the patient has no identifier in the same system are skipped.
+func supersessions(e *apiv1.PatientEvent) []*apiv1.Supersession {
+	var result []*apiv1.Supersession
+	for _, merged := range e.GetMerged() {
+		var surviving *apiv1.Identifier
+		for _, id := range e.GetPatient().GetIdentifiers() {
+			if id.GetSystem() == merged.GetSystem() {
+				surviving = id
+				break
+			}
+		}
+		if surviving == nil {
+			log.Printf("adt: no surviving identifier for '%s|%s' merged by %s", merged.GetSystem(), merged.GetValue(), e.GetMessageControlId())
+			continue
+		}
+		if surviving.GetValue() == merged.GetValue() {
+			continue
+		}
+		result = append(result, &apiv1.Supersession{
+			Superseded: merged,
+			Surviving:  surviving,
+			Recorded:   e.GetReceived(),
+			Source:     e.GetSource(),
+			Reason:     "A40 merge patient (" + e.GetMessageControlId() + ")",
+		})
+	}
+	return result
+}
+
+func formatIdentifiers(ids []*apiv1.Identifier) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = id.GetSystem() + "|" + id.GetValue()
+	}
+	return "[" + strings.Join(s, " ") + "]"
+}
@@ -0,0 +1,216 @@
+package adt
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/hl7"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type testCache struct {
+	invalidated []string
+	refreshed   []*apiv1.Patient
+}
+
+func (c *testCache) Invalidate(ids ...*apiv1.Identifier) {
+	for _, id := range ids {
+		c.invalidated = append(c.invalidated, id.GetValue())
+	}
+}
+
+func (c *testCache) Refresh(pt *apiv1.Patient) {
+	c.refreshed = append(c.refreshed, pt)
+}
+
+// testParser parses the patient identifier from PID-3 and any merged identifier from MRG-1
+func testParser(msg *hl7.Message) (*apiv1.PatientEvent, error) {
+	_, event := msg.Type()
+	if event == "A99" {
+		return nil, status.Errorf(codes.Unimplemented, "unsupported event: %s", event)
+	}
+	e := &apiv1.PatientEvent{EventType: event, MessageControlId: msg.ControlID(), Patient: &apiv1.Patient{
+		Identifiers: []*apiv1.Identifier{{System: "test", Value: msg.Value("PID", 3, 1)}},
+	}}
+	if mrg := msg.Value("MRG", 1, 1); mrg != "" {
+		e.Merged = []*apiv1.Identifier{{System: "test", Value: mrg}}
+	}
+	return e, nil
+}
+
+func send(t *testing.T, addr string, event string, segments ...string) *hl7.Message {
+	msg := "MSH|^~\\&|PAS|RWM|CONCIERGE|CYMRU|20200612143000||ADT^" + event + "|" + event + "|P|2.5\r" + strings.Join(segments, "\r")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ack, err := hl7.Send(ctx, addr, []byte(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ack
+}
+
+func TestFeed(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, refresh := range []bool{false, true} {
+		f := NewFeed(testParser, refresh)
+		c := &testCache{}
+		f.RegisterCache("test", c)
+		if refresh {
+			if lis, err = net.Listen("tcp", "127.0.0.1:0"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		go f.Serve(lis)
+		events := f.subscribe()
+		addr := lis.Addr().String()
+
+		if ack := send(t, addr, "A08", "PID|1||A999998"); ack.Value("MSA", 1, 1) != hl7.AckAccept {
+			t.Fatalf("update not accepted: %q", ack.Encode())
+		}
+		if e := <-events; e.GetEventType() != "A08" || e.GetPatient().GetIdentifiers()[0].GetValue() != "A999998" {
+			t.Fatalf("incorrect event: %v", e)
+		}
+		if ack := send(t, addr, "A40", "PID|1||A999998", "MRG|A999997"); ack.Value("MSA", 1, 1) != hl7.AckAccept {
+			t.Fatalf("merge not accepted: %q", ack.Encode())
+		}
+		if e := <-events; e.GetEventType() != "A40" || e.GetMerged()[0].GetValue() != "A999997" {
+			t.Fatalf("incorrect event: %v", e)
+		}
+		if ack := send(t, addr, "A99", "PID|1||A999998"); ack.Value("MSA", 1, 1) != hl7.AckError {
+			t.Fatalf("unsupported event not rejected: %q", ack.Encode())
+		}
+		if refresh {
+			if len(c.refreshed) != 2 || strings.Join(c.invalidated, ",") != "A999997" {
+				t.Fatalf("incorrect cache updates: invalidated:%v refreshed:%v", c.invalidated, c.refreshed)
+			}
+		} else if len(c.refreshed) != 0 || strings.Join(c.invalidated, ",") != "A999998,A999997,A999998" {
+			t.Fatalf("incorrect cache updates: invalidated:%v refreshed:%v", c.invalidated, c.refreshed)
+		}
+		f.unsubscribe(events)
+		f.Close()
+	}
+}
+
+func TestMergeRecorder(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := NewFeed(testParser, false)
+	var recorded []*apiv1.Supersession
+	f.RegisterMergeRecorder(func(ctx context.Context, s *apiv1.Supersession) error {
+		if s.GetSuperseded().GetValue() == "A999996" {
+			return status.Errorf(codes.Unavailable, "store unavailable")
+		}
+		recorded = append(recorded, s)
+		return nil
+	})
+	go f.Serve(lis)
+	defer f.Close()
+	addr := lis.Addr().String()
+	if ack := send(t, addr, "A40", "PID|1||A999998", "MRG|A999997"); ack.Value("MSA", 1, 1) != hl7.AckAccept {
+		t.Fatalf("merge not accepted: %q", ack.Encode())
+	}
+	if len(recorded) != 1 || recorded[0].GetSuperseded().GetValue() != "A999997" || recorded[0].GetSurviving().GetValue() != "A999998" {
+		t.Fatalf("incorrect supersessions recorded: %v", recorded)
+	}
+	if ack := send(t, addr, "A40", "PID|1||A999998", "MRG|A999996"); ack.Value("MSA", 1, 1) != hl7.AckReject {
+		t.Fatalf("merge that could not be recorded not rejected: %q", ack.Encode())
+	}
+}
+
+func TestAllowSources(t *testing.T) {
+	f := NewFeed(testParser, false)
+	if err := f.AllowSources("10.0.0.1", "wibble"); err == nil {
+		t.Fatal("invalid source allowed")
+	}
+	for _, test := range []struct {
+		sources []string
+		allowed bool
+	}{
+		{[]string{"10.0.0.0/8", "192.168.1.1"}, false},
+		{[]string{"10.0.0.0/8", "127.0.0.1"}, true},
+		{[]string{"127.0.0.0/8"}, true},
+	} {
+		f := NewFeed(testParser, false)
+		if err := f.AllowSources(test.sources...); err != nil {
+			t.Fatal(err)
+		}
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		go f.Serve(lis)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		msg := "MSH|^~\\&|PAS|RWM|CONCIERGE|CYMRU|20200612143000||ADT^A08|A08|P|2.5\rPID|1||A999998"
+		_, err = hl7.Send(ctx, lis.Addr().String(), []byte(msg))
+		cancel()
+		f.Close()
+		if (err == nil) != test.allowed {
+			t.Errorf("sources %v: expected allowed: %t, got error: %v", test.sources, test.allowed, err)
+		}
+	}
+}
+
+// testStream is a stream of patient events sent to a subscriber
+type testStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *apiv1.PatientEvent
+}
+
+func (s *testStream) Context() context.Context { return s.ctx }
+
+func (s *testStream) Send(e *apiv1.PatientEvent) error {
+	s.events <- e
+	return nil
+}
+
+func TestSubscribe(t *testing.T) {
+	identifiers.RegisterAccessPolicy("test-restricted", func(ctx context.Context, id *apiv1.Identifier, o proto.Message) (bool, string) {
+		return id.GetSystem() == "test" && id.GetValue() == "A999997", "restricted record"
+	})
+	t.Cleanup(func() { identifiers.UnregisterAccessPolicy("test-restricted") })
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := NewFeed(testParser, false)
+	go f.Serve(lis)
+	stream := &testStream{ctx: context.Background(), events: make(chan *apiv1.PatientEvent, 10)}
+	done := make(chan error)
+	go func() { done <- f.Subscribe(&apiv1.PatientEventsRequest{}, stream) }()
+	for {
+		f.mu.RLock()
+		subscribed := len(f.subscribers) > 0
+		f.mu.RUnlock()
+		if subscribed {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	addr := lis.Addr().String()
+	send(t, addr, "A08", "PID|1||A999997")
+	send(t, addr, "A08", "PID|1||A999998")
+	if e := <-stream.events; e.GetPatient().GetIdentifiers()[0].GetValue() != "A999998" {
+		t.Fatalf("event for restricted patient sent to subscriber: %v", e)
+	}
+	f.Close()
+	if err := <-done; status.Code(err) != codes.Unavailable {
+		t.Fatalf("subscription not ended when feed closed: %v", err)
+	}
+	if len(stream.events) != 0 {
+		t.Fatalf("unexpected events: %d", len(stream.events))
+	}
+}
@@ -1,5 +1,12 @@
 package apiv1
 
+import (
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/protobuf/proto"
+)
+
 // GetIdentifiersForSystem returns the identifier matching the system specified, it is exists
 func (pt *Patient) GetIdentifiersForSystem(s string) ([]*Identifier, bool) {
 	if pt == nil {
@@ -14,7 +21,34 @@ func (pt *Patient) GetIdentifiersForSystem(s string) ([]*Identifier, bool) {
 	return result, len(result) > 0
 }
 
-// Match determines whether one patient is the same as another
+// CurrentAddress returns the address in use at the time specified, being the most recent address whose period
+// includes that time, or nil if there is none. Addresses without a period are treated as always in use.
+func (pt *Patient) CurrentAddress(t time.Time) *Address {
+	var result *Address
+	var start time.Time
+	for _, a := range pt.GetAddresses() {
+		from, err := ptypes.Timestamp(a.GetPeriod().GetStart())
+		if err != nil {
+			from = time.Time{}
+		}
+		if from.After(t) {
+			continue
+		}
+		if a.GetPeriod().GetEnd() != nil {
+			if to, err := ptypes.Timestamp(a.GetPeriod().GetEnd()); err == nil && !to.After(t) {
+				continue
+			}
+		}
+		if result == nil || from.After(start) {
+			result, start = a, from
+		}
+	}
+	return result
+}
+
+// Match determines whether one patient is the same as another, by exact comparison of last name, date of birth and
+// gender, and of identifiers in the systems specified that both patients have.
+// See package matching for probabilistic matching of patients.
 func (pt *Patient) Match(other *Patient, identifierSystems []string) bool {
 	if matchedIdentifiers(pt, other, identifierSystems) == false {
 		return false
@@ -22,7 +56,7 @@ func (pt *Patient) Match(other *Patient, identifierSystems []string) bool {
 	if pt.GetLastname() != other.GetLastname() {
 		return false
 	}
-	if pt.GetBirthDate() != other.GetBirthDate() {
+	if !proto.Equal(pt.GetBirthDate(), other.GetBirthDate()) {
 		return false
 	}
 	if pt.GetGender() != other.GetGender() {
@@ -40,16 +74,18 @@ func matchedIdentifiers(pt1 *Patient, pt2 *Patient, systems []string) bool {
 	return true
 }
 
-// checks that at least one identifiers for a specified namespace matches
+// checks that at least one identifier for a specified namespace matches, if both patients have identifiers in that
+// namespace
 func matchedIdentifiersForSystem(pt1 *Patient, pt2 *Patient, system string) bool {
-	if ids1, found := pt1.GetIdentifiersForSystem(system); found {
-		if ids2, found := pt2.GetIdentifiersForSystem(system); found {
-			for _, id1 := range ids1 {
-				for _, id2 := range ids2 {
-					if id1.GetValue() != id2.GetValue() {
-						return true
-					}
-				}
+	ids1, found1 := pt1.GetIdentifiersForSystem(system)
+	ids2, found2 := pt2.GetIdentifiersForSystem(system)
+	if !found1 || !found2 {
+		return true
+	}
+	for _, id1 := range ids1 {
+		for _, id2 := range ids2 {
+			if id1.GetValue() == id2.GetValue() {
+				return true
 			}
 		}
 	}
@@ -0,0 +1,31 @@
+package apiv1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+)
+
+func TestMatch(t *testing.T) {
+	const nhs, crn = "https://fhir.nhs.uk/Id/nhs-number", "https://fhir.cardiff.wales.nhs.uk/Id/pas-identifier"
+	birthDate := func() *Patient {
+		ts, _ := ptypes.TimestampProto(time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC))
+		return &Patient{Lastname: "DUMMY", Gender: Gender_MALE, BirthDate: ts}
+	}
+	pt1, pt2 := birthDate(), birthDate()
+	pt1.Identifiers = []*Identifier{{System: nhs, Value: "1111111111"}, {System: crn, Value: "A999998"}}
+	pt2.Identifiers = []*Identifier{{System: nhs, Value: "1111111111"}}
+	if !pt1.Match(pt2, []string{nhs, crn}) {
+		t.Errorf("patients with the same demographics and identifiers do not match")
+	}
+	pt2.Identifiers[0].Value = "2222222222"
+	if pt1.Match(pt2, []string{nhs, crn}) {
+		t.Errorf("patients with different NHS numbers match")
+	}
+	pt2.Identifiers[0].Value = "1111111111"
+	pt2.BirthDate.Seconds += 24 * 60 * 60
+	if pt1.Match(pt2, []string{nhs, crn}) {
+		t.Errorf("patients with different dates of birth match")
+	}
+}
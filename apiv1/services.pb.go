@@ -10,6 +10,7 @@ import (
 	context "context"
 	proto "github.com/golang/protobuf/proto"
 	any "github.com/golang/protobuf/ptypes/any"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
@@ -31,6 +32,177 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
+type Discrepancy_Field int32
+
+const (
+	Discrepancy_UNKNOWN              Discrepancy_Field = 0
+	Discrepancy_NAME                 Discrepancy_Field = 1
+	Discrepancy_BIRTH_DATE           Discrepancy_Field = 2
+	Discrepancy_GENDER               Discrepancy_Field = 3
+	Discrepancy_ADDRESS              Discrepancy_Field = 4 // current address
+	Discrepancy_GENERAL_PRACTITIONER Discrepancy_Field = 5
+	Discrepancy_SURGERY              Discrepancy_Field = 6
+	Discrepancy_DECEASED             Discrepancy_Field = 7 // date of death, or whether deceased
+	Discrepancy_NHS_NUMBER           Discrepancy_Field = 8
+)
+
+// Enum value maps for Discrepancy_Field.
+var (
+	Discrepancy_Field_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "NAME",
+		2: "BIRTH_DATE",
+		3: "GENDER",
+		4: "ADDRESS",
+		5: "GENERAL_PRACTITIONER",
+		6: "SURGERY",
+		7: "DECEASED",
+		8: "NHS_NUMBER",
+	}
+	Discrepancy_Field_value = map[string]int32{
+		"UNKNOWN":              0,
+		"NAME":                 1,
+		"BIRTH_DATE":           2,
+		"GENDER":               3,
+		"ADDRESS":              4,
+		"GENERAL_PRACTITIONER": 5,
+		"SURGERY":              6,
+		"DECEASED":             7,
+		"NHS_NUMBER":           8,
+	}
+)
+
+func (x Discrepancy_Field) Enum() *Discrepancy_Field {
+	p := new(Discrepancy_Field)
+	*p = x
+	return p
+}
+
+func (x Discrepancy_Field) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Discrepancy_Field) Descriptor() protoreflect.EnumDescriptor {
+	return file_services_proto_enumTypes[0].Descriptor()
+}
+
+func (Discrepancy_Field) Type() protoreflect.EnumType {
+	return &file_services_proto_enumTypes[0]
+}
+
+func (x Discrepancy_Field) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Discrepancy_Field.Descriptor instead.
+func (Discrepancy_Field) EnumDescriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{10, 0}
+}
+
+type PatientMatch_Classification int32
+
+const (
+	PatientMatch_NON_MATCH      PatientMatch_Classification = 0
+	PatientMatch_POSSIBLE_MATCH PatientMatch_Classification = 1 // for review
+	PatientMatch_MATCH          PatientMatch_Classification = 2
+)
+
+// Enum value maps for PatientMatch_Classification.
+var (
+	PatientMatch_Classification_name = map[int32]string{
+		0: "NON_MATCH",
+		1: "POSSIBLE_MATCH",
+		2: "MATCH",
+	}
+	PatientMatch_Classification_value = map[string]int32{
+		"NON_MATCH":      0,
+		"POSSIBLE_MATCH": 1,
+		"MATCH":          2,
+	}
+)
+
+func (x PatientMatch_Classification) Enum() *PatientMatch_Classification {
+	p := new(PatientMatch_Classification)
+	*p = x
+	return p
+}
+
+func (x PatientMatch_Classification) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PatientMatch_Classification) Descriptor() protoreflect.EnumDescriptor {
+	return file_services_proto_enumTypes[1].Descriptor()
+}
+
+func (PatientMatch_Classification) Type() protoreflect.EnumType {
+	return &file_services_proto_enumTypes[1]
+}
+
+func (x PatientMatch_Classification) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PatientMatch_Classification.Descriptor instead.
+func (PatientMatch_Classification) EnumDescriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{13, 0}
+}
+
+type MergeHistory struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Current       *Identifier     `protobuf:"bytes,1,opt,name=current,proto3" json:"current,omitempty"`             // the current identifier, after following any supersessions
+	Supersessions []*Supersession `protobuf:"bytes,2,rep,name=supersessions,proto3" json:"supersessions,omitempty"` // supersessions of identifiers that now identify the same record, oldest first
+}
+
+func (x *MergeHistory) Reset() {
+	*x = MergeHistory{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MergeHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeHistory) ProtoMessage() {}
+
+func (x *MergeHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeHistory.ProtoReflect.Descriptor instead.
+func (*MergeHistory) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MergeHistory) GetCurrent() *Identifier {
+	if x != nil {
+		return x.Current
+	}
+	return nil
+}
+
+func (x *MergeHistory) GetSupersessions() []*Supersession {
+	if x != nil {
+		return x.Supersessions
+	}
+	return nil
+}
+
 type IdentifierMapRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -44,7 +216,7 @@ type IdentifierMapRequest struct {
 func (x *IdentifierMapRequest) Reset() {
 	*x = IdentifierMapRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_services_proto_msgTypes[0]
+		mi := &file_services_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -57,7 +229,7 @@ func (x *IdentifierMapRequest) String() string {
 func (*IdentifierMapRequest) ProtoMessage() {}
 
 func (x *IdentifierMapRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_services_proto_msgTypes[0]
+	mi := &file_services_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -70,7 +242,7 @@ func (x *IdentifierMapRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IdentifierMapRequest.ProtoReflect.Descriptor instead.
 func (*IdentifierMapRequest) Descriptor() ([]byte, []int) {
-	return file_services_proto_rawDescGZIP(), []int{0}
+	return file_services_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *IdentifierMapRequest) GetSystem() string {
@@ -109,7 +281,7 @@ type PublishDocumentRequest struct {
 func (x *PublishDocumentRequest) Reset() {
 	*x = PublishDocumentRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_services_proto_msgTypes[1]
+		mi := &file_services_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -122,7 +294,7 @@ func (x *PublishDocumentRequest) String() string {
 func (*PublishDocumentRequest) ProtoMessage() {}
 
 func (x *PublishDocumentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_services_proto_msgTypes[1]
+	mi := &file_services_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -135,7 +307,7 @@ func (x *PublishDocumentRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PublishDocumentRequest.ProtoReflect.Descriptor instead.
 func (*PublishDocumentRequest) Descriptor() ([]byte, []int) {
-	return file_services_proto_rawDescGZIP(), []int{1}
+	return file_services_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *PublishDocumentRequest) GetDocument() *Document {
@@ -157,7 +329,7 @@ type PublishDocumentResponse struct {
 func (x *PublishDocumentResponse) Reset() {
 	*x = PublishDocumentResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_services_proto_msgTypes[2]
+		mi := &file_services_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -170,7 +342,7 @@ func (x *PublishDocumentResponse) String() string {
 func (*PublishDocumentResponse) ProtoMessage() {}
 
 func (x *PublishDocumentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_services_proto_msgTypes[2]
+	mi := &file_services_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -183,7 +355,7 @@ func (x *PublishDocumentResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PublishDocumentResponse.ProtoReflect.Descriptor instead.
 func (*PublishDocumentResponse) Descriptor() ([]byte, []int) {
-	return file_services_proto_rawDescGZIP(), []int{2}
+	return file_services_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *PublishDocumentResponse) GetId() *Identifier {
@@ -205,7 +377,7 @@ type NotificationRequest struct {
 func (x *NotificationRequest) Reset() {
 	*x = NotificationRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_services_proto_msgTypes[3]
+		mi := &file_services_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -218,7 +390,7 @@ func (x *NotificationRequest) String() string {
 func (*NotificationRequest) ProtoMessage() {}
 
 func (x *NotificationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_services_proto_msgTypes[3]
+	mi := &file_services_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -231,7 +403,7 @@ func (x *NotificationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NotificationRequest.ProtoReflect.Descriptor instead.
 func (*NotificationRequest) Descriptor() ([]byte, []int) {
-	return file_services_proto_rawDescGZIP(), []int{3}
+	return file_services_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *NotificationRequest) GetRecipient() *Identifier {
@@ -260,7 +432,7 @@ type NotificationResponse struct {
 func (x *NotificationResponse) Reset() {
 	*x = NotificationResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_services_proto_msgTypes[4]
+		mi := &file_services_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -273,7 +445,7 @@ func (x *NotificationResponse) String() string {
 func (*NotificationResponse) ProtoMessage() {}
 
 func (x *NotificationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_services_proto_msgTypes[4]
+	mi := &file_services_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -286,7 +458,7 @@ func (x *NotificationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NotificationResponse.ProtoReflect.Descriptor instead.
 func (*NotificationResponse) Descriptor() ([]byte, []int) {
-	return file_services_proto_rawDescGZIP(), []int{4}
+	return file_services_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *NotificationResponse) GetId() *Identifier {
@@ -296,34 +468,31 @@ func (x *NotificationResponse) GetId() *Identifier {
 	return nil
 }
 
-type PractitionerSearchRequest struct {
+type PatientsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	System    string `protobuf:"bytes,1,opt,name=system,proto3" json:"system,omitempty"`
-	Username  string `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
-	FirstName string `protobuf:"bytes,3,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
-	LastName  string `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Identifiers []*Identifier `protobuf:"bytes,1,rep,name=identifiers,proto3" json:"identifiers,omitempty"`
 }
 
-func (x *PractitionerSearchRequest) Reset() {
-	*x = PractitionerSearchRequest{}
+func (x *PatientsRequest) Reset() {
+	*x = PatientsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_services_proto_msgTypes[5]
+		mi := &file_services_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *PractitionerSearchRequest) String() string {
+func (x *PatientsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PractitionerSearchRequest) ProtoMessage() {}
+func (*PatientsRequest) ProtoMessage() {}
 
-func (x *PractitionerSearchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_services_proto_msgTypes[5]
+func (x *PatientsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -334,262 +503,1143 @@ func (x *PractitionerSearchRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PractitionerSearchRequest.ProtoReflect.Descriptor instead.
-func (*PractitionerSearchRequest) Descriptor() ([]byte, []int) {
-	return file_services_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use PatientsRequest.ProtoReflect.Descriptor instead.
+func (*PatientsRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *PractitionerSearchRequest) GetSystem() string {
+func (x *PatientsRequest) GetIdentifiers() []*Identifier {
 	if x != nil {
-		return x.System
+		return x.Identifiers
 	}
-	return ""
+	return nil
 }
 
-func (x *PractitionerSearchRequest) GetUsername() string {
+type PatientsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Patients []*Patient    `protobuf:"bytes,1,rep,name=patients,proto3" json:"patients,omitempty"`                 // patients found, in the order of the identifiers requested
+	NotFound []*Identifier `protobuf:"bytes,2,rep,name=not_found,json=notFound,proto3" json:"not_found,omitempty"` // identifiers for which no patient was found
+}
+
+func (x *PatientsResponse) Reset() {
+	*x = PatientsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatientsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatientsResponse) ProtoMessage() {}
+
+func (x *PatientsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatientsResponse.ProtoReflect.Descriptor instead.
+func (*PatientsResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PatientsResponse) GetPatients() []*Patient {
 	if x != nil {
-		return x.Username
+		return x.Patients
+	}
+	return nil
+}
+
+func (x *PatientsResponse) GetNotFound() []*Identifier {
+	if x != nil {
+		return x.NotFound
+	}
+	return nil
+}
+
+type PatientSearchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LastName  string               `protobuf:"bytes,1,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	FirstName string               `protobuf:"bytes,2,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	BirthDate *timestamp.Timestamp `protobuf:"bytes,3,opt,name=birth_date,json=birthDate,proto3" json:"birth_date,omitempty"`
+	Gender    Gender               `protobuf:"varint,4,opt,name=gender,proto3,enum=apiv1.Gender" json:"gender,omitempty"`
+	Postcode  string               `protobuf:"bytes,5,opt,name=postcode,proto3" json:"postcode,omitempty"`
+}
+
+func (x *PatientSearchRequest) Reset() {
+	*x = PatientSearchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatientSearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatientSearchRequest) ProtoMessage() {}
+
+func (x *PatientSearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatientSearchRequest.ProtoReflect.Descriptor instead.
+func (*PatientSearchRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PatientSearchRequest) GetLastName() string {
+	if x != nil {
+		return x.LastName
 	}
 	return ""
 }
 
-func (x *PractitionerSearchRequest) GetFirstName() string {
+func (x *PatientSearchRequest) GetFirstName() string {
 	if x != nil {
 		return x.FirstName
 	}
 	return ""
 }
 
-func (x *PractitionerSearchRequest) GetLastName() string {
+func (x *PatientSearchRequest) GetBirthDate() *timestamp.Timestamp {
 	if x != nil {
-		return x.LastName
+		return x.BirthDate
 	}
-	return ""
+	return nil
 }
 
-var File_services_proto protoreflect.FileDescriptor
+func (x *PatientSearchRequest) GetGender() Gender {
+	if x != nil {
+		return x.Gender
+	}
+	return Gender_UNKNOWN
+}
 
-var file_services_proto_rawDesc = []byte{
-	0x0a, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x12, 0x05, 0x61, 0x70, 0x69, 0x76, 0x31, 0x1a, 0x0b, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x19, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
-	0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f,
-	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x63, 0x0a,
-	0x14, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x4d, 0x61, 0x70, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x14, 0x0a,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
-	0x6c, 0x75, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x75, 0x72,
-	0x69, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x55,
-	0x72, 0x69, 0x22, 0x45, 0x0a, 0x16, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63,
-	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x08,
-	0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
-	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52,
-	0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x3c, 0x0a, 0x17, 0x50, 0x75, 0x62,
-	0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
-	0x69, 0x65, 0x72, 0x52, 0x02, 0x69, 0x64, 0x22, 0x70, 0x0a, 0x13, 0x4e, 0x6f, 0x74, 0x69, 0x66,
-	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2f,
-	0x0a, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69,
-	0x66, 0x69, 0x65, 0x72, 0x52, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x12,
-	0x28, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74,
-	0x52, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x22, 0x39, 0x0a, 0x14, 0x4e, 0x6f, 0x74,
-	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x21, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
-	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
-	0x52, 0x02, 0x69, 0x64, 0x22, 0x8b, 0x01, 0x0a, 0x19, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74,
-	0x69, 0x6f, 0x6e, 0x65, 0x72, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73,
-	0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73,
-	0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f,
-	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x69, 0x72, 0x73,
-	0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x4e, 0x61,
-	0x6d, 0x65, 0x32, 0xab, 0x01, 0x0a, 0x0d, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63,
-	0x61, 0x74, 0x6f, 0x72, 0x12, 0x48, 0x0a, 0x05, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x13, 0x2e,
-	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x14, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0e,
-	0x22, 0x09, 0x2f, 0x76, 0x31, 0x2f, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x3a, 0x01, 0x2a, 0x12, 0x50,
-	0x0a, 0x07, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x12, 0x1a, 0x2e, 0x61, 0x70, 0x69, 0x76,
-	0x31, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x6f,
-	0x67, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x13, 0x82, 0xd3, 0xe4,
-	0x93, 0x02, 0x0d, 0x12, 0x0b, 0x2f, 0x76, 0x31, 0x2f, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68,
-	0x32, 0xbb, 0x01, 0x0a, 0x0b, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73,
-	0x12, 0x58, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
-	0x72, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69,
-	0x66, 0x69, 0x65, 0x72, 0x1a, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x22, 0x1e, 0x82, 0xd3, 0xe4, 0x93,
-	0x02, 0x18, 0x12, 0x16, 0x2f, 0x76, 0x31, 0x2f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69,
-	0x65, 0x72, 0x2f, 0x7b, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x7d, 0x12, 0x52, 0x0a, 0x0d, 0x4d, 0x61,
-	0x70, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x1b, 0x2e, 0x61, 0x70,
-	0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x4d, 0x61,
-	0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
-	0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x22, 0x0f, 0x82, 0xd3, 0xe4,
-	0x93, 0x02, 0x09, 0x12, 0x07, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x61, 0x70, 0x30, 0x01, 0x32, 0x96,
-	0x01, 0x0a, 0x0f, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x12, 0x82, 0x01, 0x0a, 0x0f, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f,
-	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50,
-	0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x75,
-	0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x30, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x2a, 0x22, 0x14, 0x2f,
-	0x76, 0x31, 0x2f, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x70, 0x75, 0x62, 0x6c,
-	0x69, 0x73, 0x68, 0x3a, 0x12, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x64, 0x61,
-	0x74, 0x61, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x32, 0x6f, 0x0a, 0x13, 0x4e, 0x6f, 0x74, 0x69, 0x66,
-	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x58,
-	0x0a, 0x06, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x12, 0x1a, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
-	0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x74,
-	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x15, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0f, 0x22, 0x0a, 0x2f, 0x76, 0x31, 0x2f, 0x6e,
-	0x6f, 0x74, 0x69, 0x66, 0x79, 0x3a, 0x01, 0x2a, 0x32, 0x87, 0x01, 0x0a, 0x15, 0x50, 0x72, 0x61,
-	0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f,
-	0x72, 0x79, 0x12, 0x6e, 0x0a, 0x12, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x50, 0x72, 0x61, 0x63,
-	0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x12, 0x20, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
-	0x2e, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x53, 0x65, 0x61,
-	0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x61, 0x70, 0x69,
-	0x76, 0x31, 0x2e, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x22,
-	0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19, 0x12, 0x17, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x72, 0x61,
-	0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x2f, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68,
-	0x30, 0x01, 0x42, 0x3d, 0x0a, 0x18, 0x63, 0x6f, 0x6d, 0x2e, 0x65, 0x6c, 0x64, 0x72, 0x69, 0x78,
-	0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x65, 0x72, 0x67, 0x65, 0x2e, 0x61, 0x70, 0x69, 0x5a, 0x21,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x61, 0x72, 0x64, 0x6c,
-	0x65, 0x2f, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x65, 0x72, 0x67, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x76,
-	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *PatientSearchRequest) GetPostcode() string {
+	if x != nil {
+		return x.Postcode
+	}
+	return ""
 }
 
-var (
-	file_services_proto_rawDescOnce sync.Once
-	file_services_proto_rawDescData = file_services_proto_rawDesc
-)
+type PatientReconciliation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func file_services_proto_rawDescGZIP() []byte {
-	file_services_proto_rawDescOnce.Do(func() {
-		file_services_proto_rawDescData = protoimpl.X.CompressGZIP(file_services_proto_rawDescData)
-	})
-	return file_services_proto_rawDescData
+	Patient       *Patient       `protobuf:"bytes,1,opt,name=patient,proto3" json:"patient,omitempty"` // merged view, preferring the EMPI, as the master index
+	Empi          *Patient       `protobuf:"bytes,2,opt,name=empi,proto3" json:"empi,omitempty"`       // patient from the EMPI
+	Pas           *Patient       `protobuf:"bytes,3,opt,name=pas,proto3" json:"pas,omitempty"`         // patient from the patient administration system
+	Discrepancies []*Discrepancy `protobuf:"bytes,4,rep,name=discrepancies,proto3" json:"discrepancies,omitempty"`
 }
 
-var file_services_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
-var file_services_proto_goTypes = []interface{}{
-	(*IdentifierMapRequest)(nil),      // 0: apiv1.IdentifierMapRequest
-	(*PublishDocumentRequest)(nil),    // 1: apiv1.PublishDocumentRequest
-	(*PublishDocumentResponse)(nil),   // 2: apiv1.PublishDocumentResponse
-	(*NotificationRequest)(nil),       // 3: apiv1.NotificationRequest
-	(*NotificationResponse)(nil),      // 4: apiv1.NotificationResponse
-	(*PractitionerSearchRequest)(nil), // 5: apiv1.PractitionerSearchRequest
-	(*Document)(nil),                  // 6: apiv1.Document
-	(*Identifier)(nil),                // 7: apiv1.Identifier
-	(*Patient)(nil),                   // 8: apiv1.Patient
-	(*LoginRequest)(nil),              // 9: apiv1.LoginRequest
-	(*TokenRefreshRequest)(nil),       // 10: apiv1.TokenRefreshRequest
-	(*LoginResponse)(nil),             // 11: apiv1.LoginResponse
-	(*any.Any)(nil),                   // 12: google.protobuf.Any
-	(*Practitioner)(nil),              // 13: apiv1.Practitioner
+func (x *PatientReconciliation) Reset() {
+	*x = PatientReconciliation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-var file_services_proto_depIdxs = []int32{
-	6,  // 0: apiv1.PublishDocumentRequest.document:type_name -> apiv1.Document
-	7,  // 1: apiv1.PublishDocumentResponse.id:type_name -> apiv1.Identifier
-	7,  // 2: apiv1.NotificationRequest.recipient:type_name -> apiv1.Identifier
-	8,  // 3: apiv1.NotificationRequest.patient:type_name -> apiv1.Patient
-	7,  // 4: apiv1.NotificationResponse.id:type_name -> apiv1.Identifier
-	9,  // 5: apiv1.Authenticator.Login:input_type -> apiv1.LoginRequest
-	10, // 6: apiv1.Authenticator.Refresh:input_type -> apiv1.TokenRefreshRequest
-	7,  // 7: apiv1.Identifiers.GetIdentifier:input_type -> apiv1.Identifier
-	0,  // 8: apiv1.Identifiers.MapIdentifier:input_type -> apiv1.IdentifierMapRequest
-	1,  // 9: apiv1.DocumentService.PublishDocument:input_type -> apiv1.PublishDocumentRequest
-	3,  // 10: apiv1.NotificationService.Notify:input_type -> apiv1.NotificationRequest
-	5,  // 11: apiv1.PractitionerDirectory.SearchPractitioner:input_type -> apiv1.PractitionerSearchRequest
-	11, // 12: apiv1.Authenticator.Login:output_type -> apiv1.LoginResponse
-	11, // 13: apiv1.Authenticator.Refresh:output_type -> apiv1.LoginResponse
-	12, // 14: apiv1.Identifiers.GetIdentifier:output_type -> google.protobuf.Any
-	7,  // 15: apiv1.Identifiers.MapIdentifier:output_type -> apiv1.Identifier
-	2,  // 16: apiv1.DocumentService.PublishDocument:output_type -> apiv1.PublishDocumentResponse
-	4,  // 17: apiv1.NotificationService.Notify:output_type -> apiv1.NotificationResponse
-	13, // 18: apiv1.PractitionerDirectory.SearchPractitioner:output_type -> apiv1.Practitioner
-	12, // [12:19] is the sub-list for method output_type
-	5,  // [5:12] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+
+func (x *PatientReconciliation) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func init() { file_services_proto_init() }
-func file_services_proto_init() {
-	if File_services_proto != nil {
-		return
+func (*PatientReconciliation) ProtoMessage() {}
+
+func (x *PatientReconciliation) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	file_model_proto_init()
-	if !protoimpl.UnsafeEnabled {
-		file_services_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*IdentifierMapRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatientReconciliation.ProtoReflect.Descriptor instead.
+func (*PatientReconciliation) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PatientReconciliation) GetPatient() *Patient {
+	if x != nil {
+		return x.Patient
+	}
+	return nil
+}
+
+func (x *PatientReconciliation) GetEmpi() *Patient {
+	if x != nil {
+		return x.Empi
+	}
+	return nil
+}
+
+func (x *PatientReconciliation) GetPas() *Patient {
+	if x != nil {
+		return x.Pas
+	}
+	return nil
+}
+
+func (x *PatientReconciliation) GetDiscrepancies() []*Discrepancy {
+	if x != nil {
+		return x.Discrepancies
+	}
+	return nil
+}
+
+// Discrepancy is a difference in the demographics of a patient between the EMPI and a patient administration system
+type Discrepancy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field     Discrepancy_Field `protobuf:"varint,1,opt,name=field,proto3,enum=apiv1.Discrepancy_Field" json:"field,omitempty"`
+	EmpiValue string            `protobuf:"bytes,2,opt,name=empi_value,json=empiValue,proto3" json:"empi_value,omitempty"` // value in the EMPI, or empty if not recorded
+	PasValue  string            `protobuf:"bytes,3,opt,name=pas_value,json=pasValue,proto3" json:"pas_value,omitempty"`    // value in the patient administration system, or empty if not recorded
+}
+
+func (x *Discrepancy) Reset() {
+	*x = Discrepancy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Discrepancy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Discrepancy) ProtoMessage() {}
+
+func (x *Discrepancy) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_services_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PublishDocumentRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Discrepancy.ProtoReflect.Descriptor instead.
+func (*Discrepancy) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Discrepancy) GetField() Discrepancy_Field {
+	if x != nil {
+		return x.Field
+	}
+	return Discrepancy_UNKNOWN
+}
+
+func (x *Discrepancy) GetEmpiValue() string {
+	if x != nil {
+		return x.EmpiValue
+	}
+	return ""
+}
+
+func (x *Discrepancy) GetPasValue() string {
+	if x != nil {
+		return x.PasValue
+	}
+	return ""
+}
+
+type PatientMatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Patient    *Patient   `protobuf:"bytes,1,opt,name=patient,proto3" json:"patient,omitempty"`
+	Candidates []*Patient `protobuf:"bytes,2,rep,name=candidates,proto3" json:"candidates,omitempty"`
+}
+
+func (x *PatientMatchRequest) Reset() {
+	*x = PatientMatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatientMatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatientMatchRequest) ProtoMessage() {}
+
+func (x *PatientMatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_services_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PublishDocumentResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatientMatchRequest.ProtoReflect.Descriptor instead.
+func (*PatientMatchRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PatientMatchRequest) GetPatient() *Patient {
+	if x != nil {
+		return x.Patient
+	}
+	return nil
+}
+
+func (x *PatientMatchRequest) GetCandidates() []*Patient {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+type PatientMatchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Matches []*PatientMatch `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"` // in order of decreasing weight
+}
+
+func (x *PatientMatchResponse) Reset() {
+	*x = PatientMatchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatientMatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatientMatchResponse) ProtoMessage() {}
+
+func (x *PatientMatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_services_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NotificationRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatientMatchResponse.ProtoReflect.Descriptor instead.
+func (*PatientMatchResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PatientMatchResponse) GetMatches() []*PatientMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+// PatientMatch is the result of comparing a patient with a candidate
+type PatientMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Candidate      int32                       `protobuf:"varint,1,opt,name=candidate,proto3" json:"candidate,omitempty"` // index of the candidate in the request
+	Weight         float64                     `protobuf:"fixed64,2,opt,name=weight,proto3" json:"weight,omitempty"`      // total weight of evidence (log2 likelihood ratio) that the patients are the same
+	Classification PatientMatch_Classification `protobuf:"varint,3,opt,name=classification,proto3,enum=apiv1.PatientMatch_Classification" json:"classification,omitempty"`
+	Fields         []*FieldComparison          `protobuf:"bytes,4,rep,name=fields,proto3" json:"fields,omitempty"`
+}
+
+func (x *PatientMatch) Reset() {
+	*x = PatientMatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatientMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatientMatch) ProtoMessage() {}
+
+func (x *PatientMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_services_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NotificationResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatientMatch.ProtoReflect.Descriptor instead.
+func (*PatientMatch) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PatientMatch) GetCandidate() int32 {
+	if x != nil {
+		return x.Candidate
+	}
+	return 0
+}
+
+func (x *PatientMatch) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *PatientMatch) GetClassification() PatientMatch_Classification {
+	if x != nil {
+		return x.Classification
+	}
+	return PatientMatch_NON_MATCH
+}
+
+func (x *PatientMatch) GetFields() []*FieldComparison {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+// FieldComparison is the comparison of a field, such as last name, of two patients
+type FieldComparison struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field      string  `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Similarity float64 `protobuf:"fixed64,2,opt,name=similarity,proto3" json:"similarity,omitempty"` // from 0 (disagree) to 1 (agree)
+	Weight     float64 `protobuf:"fixed64,3,opt,name=weight,proto3" json:"weight,omitempty"`         // zero if the field is missing from either patient
+	Missing    bool    `protobuf:"varint,4,opt,name=missing,proto3" json:"missing,omitempty"`
+}
+
+func (x *FieldComparison) Reset() {
+	*x = FieldComparison{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FieldComparison) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldComparison) ProtoMessage() {}
+
+func (x *FieldComparison) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_services_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PractitionerSearchRequest); i {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldComparison.ProtoReflect.Descriptor instead.
+func (*FieldComparison) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *FieldComparison) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldComparison) GetSimilarity() float64 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
+}
+
+func (x *FieldComparison) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *FieldComparison) GetMissing() bool {
+	if x != nil {
+		return x.Missing
+	}
+	return false
+}
+
+type PatientEventsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EventTypes []string `protobuf:"bytes,1,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"` // HL7 v2 trigger events of interest (e.g. A08, A40), or all events if empty
+}
+
+func (x *PatientEventsRequest) Reset() {
+	*x = PatientEventsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatientEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatientEventsRequest) ProtoMessage() {}
+
+func (x *PatientEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatientEventsRequest.ProtoReflect.Descriptor instead.
+func (*PatientEventsRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PatientEventsRequest) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+type PatientEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EventType        string               `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`                        // HL7 v2 trigger event, e.g. A01 admit, A03 discharge, A08 update, A40 merge
+	Source           string               `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`                                               // sending application and facility, as application|facility
+	MessageControlId string               `protobuf:"bytes,3,opt,name=message_control_id,json=messageControlId,proto3" json:"message_control_id,omitempty"` // control identifier of the HL7 v2 message
+	Received         *timestamp.Timestamp `protobuf:"bytes,4,opt,name=received,proto3" json:"received,omitempty"`
+	Patient          *Patient             `protobuf:"bytes,5,opt,name=patient,proto3" json:"patient,omitempty"` // the patient after the event, or the surviving patient of a merge
+	Merged           []*Identifier        `protobuf:"bytes,6,rep,name=merged,proto3" json:"merged,omitempty"`   // identifiers of the patient merged into the surviving patient, for merges
+}
+
+func (x *PatientEvent) Reset() {
+	*x = PatientEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PatientEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatientEvent) ProtoMessage() {}
+
+func (x *PatientEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatientEvent.ProtoReflect.Descriptor instead.
+func (*PatientEvent) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PatientEvent) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *PatientEvent) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *PatientEvent) GetMessageControlId() string {
+	if x != nil {
+		return x.MessageControlId
+	}
+	return ""
+}
+
+func (x *PatientEvent) GetReceived() *timestamp.Timestamp {
+	if x != nil {
+		return x.Received
+	}
+	return nil
+}
+
+func (x *PatientEvent) GetPatient() *Patient {
+	if x != nil {
+		return x.Patient
+	}
+	return nil
+}
+
+func (x *PatientEvent) GetMerged() []*Identifier {
+	if x != nil {
+		return x.Merged
+	}
+	return nil
+}
+
+type PractitionerSearchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	System    string `protobuf:"bytes,1,opt,name=system,proto3" json:"system,omitempty"`
+	Username  string `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	FirstName string `protobuf:"bytes,3,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName  string `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+}
+
+func (x *PractitionerSearchRequest) Reset() {
+	*x = PractitionerSearchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_services_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PractitionerSearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PractitionerSearchRequest) ProtoMessage() {}
+
+func (x *PractitionerSearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PractitionerSearchRequest.ProtoReflect.Descriptor instead.
+func (*PractitionerSearchRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PractitionerSearchRequest) GetSystem() string {
+	if x != nil {
+		return x.System
+	}
+	return ""
+}
+
+func (x *PractitionerSearchRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *PractitionerSearchRequest) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *PractitionerSearchRequest) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+var File_services_proto protoreflect.FileDescriptor
+
+var file_services_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x05, 0x61, 0x70, 0x69, 0x76, 0x31, 0x1a, 0x0b, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x19, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e,
+	0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x76,
+	0x0a, 0x0c, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x2b,
+	0x0a, 0x07, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69,
+	0x65, 0x72, 0x52, 0x07, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x12, 0x39, 0x0a, 0x0d, 0x73,
+	0x75, 0x70, 0x65, 0x72, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x70, 0x65, 0x72,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x73, 0x75, 0x70, 0x65, 0x72, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x63, 0x0a, 0x14, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x66, 0x69, 0x65, 0x72, 0x4d, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x55, 0x72, 0x69, 0x22, 0x45, 0x0a, 0x16, 0x50,
+	0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65,
+	0x6e, 0x74, 0x22, 0x3c, 0x0a, 0x17, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x02, 0x69, 0x64,
+	0x22, 0x70, 0x0a, 0x13, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2f, 0x0a, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70,
+	0x69, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69,
+	0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x09, 0x72,
+	0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x28, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x69,
+	0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65,
+	0x6e, 0x74, 0x22, 0x39, 0x0a, 0x14, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x02, 0x69, 0x64, 0x22, 0x46, 0x0a,
+	0x0f, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x33, 0x0a, 0x0b, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0b, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x66, 0x69, 0x65, 0x72, 0x73, 0x22, 0x6e, 0x0a, 0x10, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x08, 0x70, 0x61, 0x74,
+	0x69, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x08, 0x70, 0x61, 0x74,
+	0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x2e, 0x0a, 0x09, 0x6e, 0x6f, 0x74, 0x5f, 0x66, 0x6f, 0x75,
+	0x6e, 0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31,
+	0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x08, 0x6e, 0x6f, 0x74,
+	0x46, 0x6f, 0x75, 0x6e, 0x64, 0x22, 0xd0, 0x01, 0x0a, 0x14, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e,
+	0x74, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b,
+	0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x66,
+	0x69, 0x72, 0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x66, 0x69, 0x72, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x39, 0x0a, 0x0a, 0x62, 0x69,
+	0x72, 0x74, 0x68, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x62, 0x69, 0x72, 0x74,
+	0x68, 0x44, 0x61, 0x74, 0x65, 0x12, 0x25, 0x0a, 0x06, 0x67, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x52, 0x06, 0x67, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08,
+	0x70, 0x6f, 0x73, 0x74, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x70, 0x6f, 0x73, 0x74, 0x63, 0x6f, 0x64, 0x65, 0x22, 0xc1, 0x01, 0x0a, 0x15, 0x50, 0x61, 0x74,
+	0x69, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69,
+	0x65, 0x6e, 0x74, 0x52, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x22, 0x0a, 0x04,
+	0x65, 0x6d, 0x70, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69,
+	0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x04, 0x65, 0x6d, 0x70, 0x69,
+	0x12, 0x20, 0x0a, 0x03, 0x70, 0x61, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x03, 0x70,
+	0x61, 0x73, 0x12, 0x38, 0x0a, 0x0d, 0x64, 0x69, 0x73, 0x63, 0x72, 0x65, 0x70, 0x61, 0x6e, 0x63,
+	0x69, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x72, 0x65, 0x70, 0x61, 0x6e, 0x63, 0x79, 0x52, 0x0d, 0x64,
+	0x69, 0x73, 0x63, 0x72, 0x65, 0x70, 0x61, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x22, 0x88, 0x02, 0x0a,
+	0x0b, 0x44, 0x69, 0x73, 0x63, 0x72, 0x65, 0x70, 0x61, 0x6e, 0x63, 0x79, 0x12, 0x2e, 0x0a, 0x05,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x72, 0x65, 0x70, 0x61, 0x6e, 0x63, 0x79, 0x2e,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1d, 0x0a, 0x0a,
+	0x65, 0x6d, 0x70, 0x69, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x65, 0x6d, 0x70, 0x69, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70,
+	0x61, 0x73, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x70, 0x61, 0x73, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x8c, 0x01, 0x0a, 0x05, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12,
+	0x08, 0x0a, 0x04, 0x4e, 0x41, 0x4d, 0x45, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x42, 0x49, 0x52,
+	0x54, 0x48, 0x5f, 0x44, 0x41, 0x54, 0x45, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x47, 0x45, 0x4e,
+	0x44, 0x45, 0x52, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x44, 0x44, 0x52, 0x45, 0x53, 0x53,
+	0x10, 0x04, 0x12, 0x18, 0x0a, 0x14, 0x47, 0x45, 0x4e, 0x45, 0x52, 0x41, 0x4c, 0x5f, 0x50, 0x52,
+	0x41, 0x43, 0x54, 0x49, 0x54, 0x49, 0x4f, 0x4e, 0x45, 0x52, 0x10, 0x05, 0x12, 0x0b, 0x0a, 0x07,
+	0x53, 0x55, 0x52, 0x47, 0x45, 0x52, 0x59, 0x10, 0x06, 0x12, 0x0c, 0x0a, 0x08, 0x44, 0x45, 0x43,
+	0x45, 0x41, 0x53, 0x45, 0x44, 0x10, 0x07, 0x12, 0x0e, 0x0a, 0x0a, 0x4e, 0x48, 0x53, 0x5f, 0x4e,
+	0x55, 0x4d, 0x42, 0x45, 0x52, 0x10, 0x08, 0x22, 0x6f, 0x0a, 0x13, 0x50, 0x61, 0x74, 0x69, 0x65,
+	0x6e, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28,
+	0x0a, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x52,
+	0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x0a, 0x63, 0x61, 0x6e, 0x64,
+	0x69, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61,
+	0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x0a, 0x63, 0x61,
+	0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x73, 0x22, 0x45, 0x0a, 0x14, 0x50, 0x61, 0x74, 0x69,
+	0x65, 0x6e, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2d, 0x0a, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e,
+	0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x22,
+	0x80, 0x02, 0x0a, 0x0c, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68,
+	0x12, 0x1c, 0x0a, 0x09, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x09, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06,
+	0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x4a, 0x0a, 0x0e, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x22,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x2e, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x0e, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64,
+	0x43, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x69, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x73, 0x22, 0x3e, 0x0a, 0x0e, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0d, 0x0a, 0x09, 0x4e, 0x4f, 0x4e, 0x5f, 0x4d, 0x41, 0x54, 0x43,
+	0x48, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x50, 0x4f, 0x53, 0x53, 0x49, 0x42, 0x4c, 0x45, 0x5f,
+	0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x4d, 0x41, 0x54, 0x43, 0x48,
+	0x10, 0x02, 0x22, 0x79, 0x0a, 0x0f, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x43, 0x6f, 0x6d, 0x70, 0x61,
+	0x72, 0x69, 0x73, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x73,
+	0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0a, 0x73, 0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x69, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x77,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x77, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x22, 0x37, 0x0a,
+	0x14, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x73, 0x22, 0x80, 0x02, 0x0a, 0x0c, 0x50, 0x61, 0x74, 0x69, 0x65,
+	0x6e, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x2c,
+	0x0a, 0x12, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
+	0x6c, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x49, 0x64, 0x12, 0x36, 0x0a, 0x08,
+	0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x72, 0x65, 0x63, 0x65,
+	0x69, 0x76, 0x65, 0x64, 0x12, 0x28, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61,
+	0x74, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x29,
+	0x0a, 0x06, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x64, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x52, 0x06, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x64, 0x22, 0x8b, 0x01, 0x0a, 0x19, 0x50, 0x72,
+	0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65,
+	0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12,
+	0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x66,
+	0x69, 0x72, 0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x66, 0x69, 0x72, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61,
+	0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c,
+	0x61, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x32, 0xab, 0x01, 0x0a, 0x0d, 0x41, 0x75, 0x74, 0x68,
+	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x48, 0x0a, 0x05, 0x4c, 0x6f, 0x67,
+	0x69, 0x6e, 0x12, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e,
+	0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x14, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x0e, 0x22, 0x09, 0x2f, 0x76, 0x31, 0x2f, 0x6c, 0x6f, 0x67, 0x69, 0x6e,
+	0x3a, 0x01, 0x2a, 0x12, 0x50, 0x0a, 0x07, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x12, 0x1a,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x66, 0x72,
+	0x65, 0x73, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x61, 0x70, 0x69,
+	0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x13, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0d, 0x12, 0x0b, 0x2f, 0x76, 0x31, 0x2f, 0x72, 0x65,
+	0x66, 0x72, 0x65, 0x73, 0x68, 0x32, 0x9e, 0x02, 0x0a, 0x0b, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x66, 0x69, 0x65, 0x72, 0x73, 0x12, 0x58, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x49, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x22,
+	0x1e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x18, 0x12, 0x16, 0x2f, 0x76, 0x31, 0x2f, 0x69, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x2f, 0x7b, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x7d, 0x12,
+	0x52, 0x0a, 0x0d, 0x4d, 0x61, 0x70, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x12, 0x1b, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
+	0x69, 0x65, 0x72, 0x4d, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x22, 0x0f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x09, 0x12, 0x07, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x61,
+	0x70, 0x30, 0x01, 0x12, 0x61, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x48,
+	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x26,
+	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x20, 0x12, 0x1e, 0x2f, 0x76, 0x31, 0x2f, 0x69, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x2f, 0x7b, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x7d, 0x2f, 0x68,
+	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x32, 0x96, 0x01, 0x0a, 0x0f, 0x44, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x82, 0x01, 0x0a, 0x0f, 0x50,
+	0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1d,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f,
+	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x30, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x2a, 0x22, 0x14, 0x2f, 0x76, 0x31, 0x2f, 0x64, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x2f, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x3a, 0x12, 0x64, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x32,
+	0x6f, 0x0a, 0x13, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x58, 0x0a, 0x06, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79,
+	0x12, 0x1a, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x61,
+	0x70, 0x69, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x15, 0x82, 0xd3, 0xe4, 0x93, 0x02,
+	0x0f, 0x22, 0x0a, 0x2f, 0x76, 0x31, 0x2f, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x3a, 0x01, 0x2a,
+	0x32, 0x87, 0x01, 0x0a, 0x15, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65,
+	0x72, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x6e, 0x0a, 0x12, 0x53, 0x65,
+	0x61, 0x72, 0x63, 0x68, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72,
+	0x12, 0x20, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x65, 0x72, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x61, 0x63, 0x74,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x22, 0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19, 0x12,
+	0x17, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65,
+	0x72, 0x2f, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x30, 0x01, 0x32, 0xab, 0x02, 0x0a, 0x10, 0x50,
+	0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12,
+	0x5a, 0x0a, 0x0d, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74,
+	0x12, 0x1b, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74,
+	0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e,
+	0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x22, 0x1a, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x14, 0x12, 0x12, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x61, 0x74, 0x69, 0x65,
+	0x6e, 0x74, 0x2f, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x30, 0x01, 0x12, 0x57, 0x0a, 0x0a, 0x47,
+	0x65, 0x74, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x1a, 0x0e, 0x2e, 0x61,
+	0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x22, 0x26, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x20, 0x12, 0x1e, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e,
+	0x74, 0x2f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x2f, 0x7b, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x7d, 0x12, 0x62, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x50, 0x61, 0x74, 0x69, 0x65,
+	0x6e, 0x74, 0x73, 0x12, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69,
+	0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x22, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1c, 0x22, 0x17, 0x2f, 0x76,
+	0x31, 0x2f, 0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x2f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x66, 0x69, 0x65, 0x72, 0x73, 0x3a, 0x01, 0x2a, 0x32, 0x7c, 0x0a, 0x0e, 0x52, 0x65, 0x63, 0x6f,
+	0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x6a, 0x0a, 0x10, 0x52, 0x65,
+	0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x11,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x1a, 0x1c, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e,
+	0x74, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22,
+	0x25, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1f, 0x12, 0x1d, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x61, 0x74,
+	0x69, 0x65, 0x6e, 0x74, 0x2f, 0x72, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x2f, 0x7b,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x7d, 0x32, 0x78, 0x0a, 0x0f, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e,
+	0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x12, 0x65, 0x0a, 0x0c, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x1a, 0x2e, 0x61, 0x70, 0x69, 0x76,
+	0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61,
+	0x74, 0x69, 0x65, 0x6e, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x1c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x16, 0x22, 0x11, 0x2f, 0x76, 0x31, 0x2f,
+	0x70, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x2f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x3a, 0x01, 0x2a,
+	0x32, 0x6c, 0x0a, 0x0d, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x12, 0x5b, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x1b,
+	0x2e, 0x61, 0x70, 0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x61, 0x70,
+	0x69, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x69, 0x65, 0x6e, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x22, 0x1a, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x14, 0x12, 0x12, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x61,
+	0x74, 0x69, 0x65, 0x6e, 0x74, 0x2f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x30, 0x01, 0x42, 0x3d,
+	0x0a, 0x18, 0x63, 0x6f, 0x6d, 0x2e, 0x65, 0x6c, 0x64, 0x72, 0x69, 0x78, 0x2e, 0x63, 0x6f, 0x6e,
+	0x63, 0x69, 0x65, 0x72, 0x67, 0x65, 0x2e, 0x61, 0x70, 0x69, 0x5a, 0x21, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x61, 0x72, 0x64, 0x6c, 0x65, 0x2f, 0x63, 0x6f,
+	0x6e, 0x63, 0x69, 0x65, 0x72, 0x67, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x76, 0x31, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_services_proto_rawDescOnce sync.Once
+	file_services_proto_rawDescData = file_services_proto_rawDesc
+)
+
+func file_services_proto_rawDescGZIP() []byte {
+	file_services_proto_rawDescOnce.Do(func() {
+		file_services_proto_rawDescData = protoimpl.X.CompressGZIP(file_services_proto_rawDescData)
+	})
+	return file_services_proto_rawDescData
+}
+
+var file_services_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_services_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_services_proto_goTypes = []interface{}{
+	(Discrepancy_Field)(0),            // 0: apiv1.Discrepancy.Field
+	(PatientMatch_Classification)(0),  // 1: apiv1.PatientMatch.Classification
+	(*MergeHistory)(nil),              // 2: apiv1.MergeHistory
+	(*IdentifierMapRequest)(nil),      // 3: apiv1.IdentifierMapRequest
+	(*PublishDocumentRequest)(nil),    // 4: apiv1.PublishDocumentRequest
+	(*PublishDocumentResponse)(nil),   // 5: apiv1.PublishDocumentResponse
+	(*NotificationRequest)(nil),       // 6: apiv1.NotificationRequest
+	(*NotificationResponse)(nil),      // 7: apiv1.NotificationResponse
+	(*PatientsRequest)(nil),           // 8: apiv1.PatientsRequest
+	(*PatientsResponse)(nil),          // 9: apiv1.PatientsResponse
+	(*PatientSearchRequest)(nil),      // 10: apiv1.PatientSearchRequest
+	(*PatientReconciliation)(nil),     // 11: apiv1.PatientReconciliation
+	(*Discrepancy)(nil),               // 12: apiv1.Discrepancy
+	(*PatientMatchRequest)(nil),       // 13: apiv1.PatientMatchRequest
+	(*PatientMatchResponse)(nil),      // 14: apiv1.PatientMatchResponse
+	(*PatientMatch)(nil),              // 15: apiv1.PatientMatch
+	(*FieldComparison)(nil),           // 16: apiv1.FieldComparison
+	(*PatientEventsRequest)(nil),      // 17: apiv1.PatientEventsRequest
+	(*PatientEvent)(nil),              // 18: apiv1.PatientEvent
+	(*PractitionerSearchRequest)(nil), // 19: apiv1.PractitionerSearchRequest
+	(*Identifier)(nil),                // 20: apiv1.Identifier
+	(*Supersession)(nil),              // 21: apiv1.Supersession
+	(*Document)(nil),                  // 22: apiv1.Document
+	(*Patient)(nil),                   // 23: apiv1.Patient
+	(*timestamp.Timestamp)(nil),       // 24: google.protobuf.Timestamp
+	(Gender)(0),                       // 25: apiv1.Gender
+	(*LoginRequest)(nil),              // 26: apiv1.LoginRequest
+	(*TokenRefreshRequest)(nil),       // 27: apiv1.TokenRefreshRequest
+	(*LoginResponse)(nil),             // 28: apiv1.LoginResponse
+	(*any.Any)(nil),                   // 29: google.protobuf.Any
+	(*Practitioner)(nil),              // 30: apiv1.Practitioner
+}
+var file_services_proto_depIdxs = []int32{
+	20, // 0: apiv1.MergeHistory.current:type_name -> apiv1.Identifier
+	21, // 1: apiv1.MergeHistory.supersessions:type_name -> apiv1.Supersession
+	22, // 2: apiv1.PublishDocumentRequest.document:type_name -> apiv1.Document
+	20, // 3: apiv1.PublishDocumentResponse.id:type_name -> apiv1.Identifier
+	20, // 4: apiv1.NotificationRequest.recipient:type_name -> apiv1.Identifier
+	23, // 5: apiv1.NotificationRequest.patient:type_name -> apiv1.Patient
+	20, // 6: apiv1.NotificationResponse.id:type_name -> apiv1.Identifier
+	20, // 7: apiv1.PatientsRequest.identifiers:type_name -> apiv1.Identifier
+	23, // 8: apiv1.PatientsResponse.patients:type_name -> apiv1.Patient
+	20, // 9: apiv1.PatientsResponse.not_found:type_name -> apiv1.Identifier
+	24, // 10: apiv1.PatientSearchRequest.birth_date:type_name -> google.protobuf.Timestamp
+	25, // 11: apiv1.PatientSearchRequest.gender:type_name -> apiv1.Gender
+	23, // 12: apiv1.PatientReconciliation.patient:type_name -> apiv1.Patient
+	23, // 13: apiv1.PatientReconciliation.empi:type_name -> apiv1.Patient
+	23, // 14: apiv1.PatientReconciliation.pas:type_name -> apiv1.Patient
+	12, // 15: apiv1.PatientReconciliation.discrepancies:type_name -> apiv1.Discrepancy
+	0,  // 16: apiv1.Discrepancy.field:type_name -> apiv1.Discrepancy.Field
+	23, // 17: apiv1.PatientMatchRequest.patient:type_name -> apiv1.Patient
+	23, // 18: apiv1.PatientMatchRequest.candidates:type_name -> apiv1.Patient
+	15, // 19: apiv1.PatientMatchResponse.matches:type_name -> apiv1.PatientMatch
+	1,  // 20: apiv1.PatientMatch.classification:type_name -> apiv1.PatientMatch.Classification
+	16, // 21: apiv1.PatientMatch.fields:type_name -> apiv1.FieldComparison
+	24, // 22: apiv1.PatientEvent.received:type_name -> google.protobuf.Timestamp
+	23, // 23: apiv1.PatientEvent.patient:type_name -> apiv1.Patient
+	20, // 24: apiv1.PatientEvent.merged:type_name -> apiv1.Identifier
+	26, // 25: apiv1.Authenticator.Login:input_type -> apiv1.LoginRequest
+	27, // 26: apiv1.Authenticator.Refresh:input_type -> apiv1.TokenRefreshRequest
+	20, // 27: apiv1.Identifiers.GetIdentifier:input_type -> apiv1.Identifier
+	3,  // 28: apiv1.Identifiers.MapIdentifier:input_type -> apiv1.IdentifierMapRequest
+	20, // 29: apiv1.Identifiers.GetMergeHistory:input_type -> apiv1.Identifier
+	4,  // 30: apiv1.DocumentService.PublishDocument:input_type -> apiv1.PublishDocumentRequest
+	6,  // 31: apiv1.NotificationService.Notify:input_type -> apiv1.NotificationRequest
+	19, // 32: apiv1.PractitionerDirectory.SearchPractitioner:input_type -> apiv1.PractitionerSearchRequest
+	10, // 33: apiv1.PatientDirectory.SearchPatient:input_type -> apiv1.PatientSearchRequest
+	20, // 34: apiv1.PatientDirectory.GetPatient:input_type -> apiv1.Identifier
+	8,  // 35: apiv1.PatientDirectory.GetPatients:input_type -> apiv1.PatientsRequest
+	20, // 36: apiv1.Reconciliation.ReconcilePatient:input_type -> apiv1.Identifier
+	13, // 37: apiv1.PatientMatching.MatchPatient:input_type -> apiv1.PatientMatchRequest
+	17, // 38: apiv1.PatientEvents.Subscribe:input_type -> apiv1.PatientEventsRequest
+	28, // 39: apiv1.Authenticator.Login:output_type -> apiv1.LoginResponse
+	28, // 40: apiv1.Authenticator.Refresh:output_type -> apiv1.LoginResponse
+	29, // 41: apiv1.Identifiers.GetIdentifier:output_type -> google.protobuf.Any
+	20, // 42: apiv1.Identifiers.MapIdentifier:output_type -> apiv1.Identifier
+	2,  // 43: apiv1.Identifiers.GetMergeHistory:output_type -> apiv1.MergeHistory
+	5,  // 44: apiv1.DocumentService.PublishDocument:output_type -> apiv1.PublishDocumentResponse
+	7,  // 45: apiv1.NotificationService.Notify:output_type -> apiv1.NotificationResponse
+	30, // 46: apiv1.PractitionerDirectory.SearchPractitioner:output_type -> apiv1.Practitioner
+	23, // 47: apiv1.PatientDirectory.SearchPatient:output_type -> apiv1.Patient
+	23, // 48: apiv1.PatientDirectory.GetPatient:output_type -> apiv1.Patient
+	9,  // 49: apiv1.PatientDirectory.GetPatients:output_type -> apiv1.PatientsResponse
+	11, // 50: apiv1.Reconciliation.ReconcilePatient:output_type -> apiv1.PatientReconciliation
+	14, // 51: apiv1.PatientMatching.MatchPatient:output_type -> apiv1.PatientMatchResponse
+	18, // 52: apiv1.PatientEvents.Subscribe:output_type -> apiv1.PatientEvent
+	39, // [39:53] is the sub-list for method output_type
+	25, // [25:39] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_services_proto_init() }
+func file_services_proto_init() {
+	if File_services_proto != nil {
+		return
+	}
+	file_model_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_services_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MergeHistory); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IdentifierMapRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublishDocumentRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -600,178 +1650,774 @@ func file_services_proto_init() {
 				return nil
 			}
 		}
+		file_services_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublishDocumentResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NotificationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NotificationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatientsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatientsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatientSearchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatientReconciliation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Discrepancy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatientMatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatientMatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatientMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FieldComparison); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatientEventsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PatientEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_services_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PractitionerSearchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_services_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   9,
+		},
+		GoTypes:           file_services_proto_goTypes,
+		DependencyIndexes: file_services_proto_depIdxs,
+		EnumInfos:         file_services_proto_enumTypes,
+		MessageInfos:      file_services_proto_msgTypes,
+	}.Build()
+	File_services_proto = out.File
+	file_services_proto_rawDesc = nil
+	file_services_proto_goTypes = nil
+	file_services_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// AuthenticatorClient is the client API for Authenticator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AuthenticatorClient interface {
+	// Login authenticates using the credentials specified and returns an authentication token
+	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	// Refresh refreshes a currently valid token
+	Refresh(ctx context.Context, in *TokenRefreshRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+}
+
+type authenticatorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAuthenticatorClient(cc grpc.ClientConnInterface) AuthenticatorClient {
+	return &authenticatorClient{cc}
+}
+
+func (c *authenticatorClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, "/apiv1.Authenticator/Login", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticatorClient) Refresh(ctx context.Context, in *TokenRefreshRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, "/apiv1.Authenticator/Refresh", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuthenticatorServer is the server API for Authenticator service.
+type AuthenticatorServer interface {
+	// Login authenticates using the credentials specified and returns an authentication token
+	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+	// Refresh refreshes a currently valid token
+	Refresh(context.Context, *TokenRefreshRequest) (*LoginResponse, error)
+}
+
+// UnimplementedAuthenticatorServer can be embedded to have forward compatible implementations.
+type UnimplementedAuthenticatorServer struct {
+}
+
+func (*UnimplementedAuthenticatorServer) Login(context.Context, *LoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (*UnimplementedAuthenticatorServer) Refresh(context.Context, *TokenRefreshRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Refresh not implemented")
+}
+
+func RegisterAuthenticatorServer(s *grpc.Server, srv AuthenticatorServer) {
+	s.RegisterService(&_Authenticator_serviceDesc, srv)
+}
+
+func _Authenticator_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticatorServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Authenticator/Login",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticatorServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authenticator_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenRefreshRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticatorServer).Refresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Authenticator/Refresh",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticatorServer).Refresh(ctx, req.(*TokenRefreshRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Authenticator_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.Authenticator",
+	HandlerType: (*AuthenticatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Login",
+			Handler:    _Authenticator_Login_Handler,
+		},
+		{
+			MethodName: "Refresh",
+			Handler:    _Authenticator_Refresh_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "services.proto",
+}
+
+// IdentifiersClient is the client API for Identifiers service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type IdentifiersClient interface {
+	GetIdentifier(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*any.Any, error)
+	MapIdentifier(ctx context.Context, in *IdentifierMapRequest, opts ...grpc.CallOption) (Identifiers_MapIdentifierClient, error)
+	// GetMergeHistory returns the identifiers superseded by, or superseding, the identifier specified, such as when
+	// patient records have been merged
+	GetMergeHistory(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*MergeHistory, error)
+}
+
+type identifiersClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIdentifiersClient(cc grpc.ClientConnInterface) IdentifiersClient {
+	return &identifiersClient{cc}
+}
+
+func (c *identifiersClient) GetIdentifier(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*any.Any, error) {
+	out := new(any.Any)
+	err := c.cc.Invoke(ctx, "/apiv1.Identifiers/GetIdentifier", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *identifiersClient) MapIdentifier(ctx context.Context, in *IdentifierMapRequest, opts ...grpc.CallOption) (Identifiers_MapIdentifierClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Identifiers_serviceDesc.Streams[0], "/apiv1.Identifiers/MapIdentifier", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &identifiersMapIdentifierClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Identifiers_MapIdentifierClient interface {
+	Recv() (*Identifier, error)
+	grpc.ClientStream
+}
+
+type identifiersMapIdentifierClient struct {
+	grpc.ClientStream
+}
+
+func (x *identifiersMapIdentifierClient) Recv() (*Identifier, error) {
+	m := new(Identifier)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *identifiersClient) GetMergeHistory(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*MergeHistory, error) {
+	out := new(MergeHistory)
+	err := c.cc.Invoke(ctx, "/apiv1.Identifiers/GetMergeHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IdentifiersServer is the server API for Identifiers service.
+type IdentifiersServer interface {
+	GetIdentifier(context.Context, *Identifier) (*any.Any, error)
+	MapIdentifier(*IdentifierMapRequest, Identifiers_MapIdentifierServer) error
+	// GetMergeHistory returns the identifiers superseded by, or superseding, the identifier specified, such as when
+	// patient records have been merged
+	GetMergeHistory(context.Context, *Identifier) (*MergeHistory, error)
+}
+
+// UnimplementedIdentifiersServer can be embedded to have forward compatible implementations.
+type UnimplementedIdentifiersServer struct {
+}
+
+func (*UnimplementedIdentifiersServer) GetIdentifier(context.Context, *Identifier) (*any.Any, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIdentifier not implemented")
+}
+func (*UnimplementedIdentifiersServer) MapIdentifier(*IdentifierMapRequest, Identifiers_MapIdentifierServer) error {
+	return status.Errorf(codes.Unimplemented, "method MapIdentifier not implemented")
+}
+func (*UnimplementedIdentifiersServer) GetMergeHistory(context.Context, *Identifier) (*MergeHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMergeHistory not implemented")
+}
+
+func RegisterIdentifiersServer(s *grpc.Server, srv IdentifiersServer) {
+	s.RegisterService(&_Identifiers_serviceDesc, srv)
+}
+
+func _Identifiers_GetIdentifier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IdentifiersServer).GetIdentifier(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Identifiers/GetIdentifier",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IdentifiersServer).GetIdentifier(ctx, req.(*Identifier))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Identifiers_MapIdentifier_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(IdentifierMapRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IdentifiersServer).MapIdentifier(m, &identifiersMapIdentifierServer{stream})
+}
+
+type Identifiers_MapIdentifierServer interface {
+	Send(*Identifier) error
+	grpc.ServerStream
+}
+
+type identifiersMapIdentifierServer struct {
+	grpc.ServerStream
+}
+
+func (x *identifiersMapIdentifierServer) Send(m *Identifier) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Identifiers_GetMergeHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IdentifiersServer).GetMergeHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.Identifiers/GetMergeHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IdentifiersServer).GetMergeHistory(ctx, req.(*Identifier))
 	}
-	type x struct{}
-	out := protoimpl.TypeBuilder{
-		File: protoimpl.DescBuilder{
-			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_services_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   6,
-			NumExtensions: 0,
-			NumServices:   5,
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Identifiers_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.Identifiers",
+	HandlerType: (*IdentifiersServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetIdentifier",
+			Handler:    _Identifiers_GetIdentifier_Handler,
 		},
-		GoTypes:           file_services_proto_goTypes,
-		DependencyIndexes: file_services_proto_depIdxs,
-		MessageInfos:      file_services_proto_msgTypes,
-	}.Build()
-	File_services_proto = out.File
-	file_services_proto_rawDesc = nil
-	file_services_proto_goTypes = nil
-	file_services_proto_depIdxs = nil
+		{
+			MethodName: "GetMergeHistory",
+			Handler:    _Identifiers_GetMergeHistory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "MapIdentifier",
+			Handler:       _Identifiers_MapIdentifier_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "services.proto",
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConnInterface
+// DocumentServiceClient is the client API for DocumentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DocumentServiceClient interface {
+	PublishDocument(ctx context.Context, in *PublishDocumentRequest, opts ...grpc.CallOption) (*PublishDocumentResponse, error)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+type documentServiceClient struct {
+	cc grpc.ClientConnInterface
+}
 
-// AuthenticatorClient is the client API for Authenticator service.
+func NewDocumentServiceClient(cc grpc.ClientConnInterface) DocumentServiceClient {
+	return &documentServiceClient{cc}
+}
+
+func (c *documentServiceClient) PublishDocument(ctx context.Context, in *PublishDocumentRequest, opts ...grpc.CallOption) (*PublishDocumentResponse, error) {
+	out := new(PublishDocumentResponse)
+	err := c.cc.Invoke(ctx, "/apiv1.DocumentService/PublishDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DocumentServiceServer is the server API for DocumentService service.
+type DocumentServiceServer interface {
+	PublishDocument(context.Context, *PublishDocumentRequest) (*PublishDocumentResponse, error)
+}
+
+// UnimplementedDocumentServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedDocumentServiceServer struct {
+}
+
+func (*UnimplementedDocumentServiceServer) PublishDocument(context.Context, *PublishDocumentRequest) (*PublishDocumentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishDocument not implemented")
+}
+
+func RegisterDocumentServiceServer(s *grpc.Server, srv DocumentServiceServer) {
+	s.RegisterService(&_DocumentService_serviceDesc, srv)
+}
+
+func _DocumentService_PublishDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishDocumentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DocumentServiceServer).PublishDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.DocumentService/PublishDocument",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DocumentServiceServer).PublishDocument(ctx, req.(*PublishDocumentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _DocumentService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.DocumentService",
+	HandlerType: (*DocumentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PublishDocument",
+			Handler:    _DocumentService_PublishDocument_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "services.proto",
+}
+
+// NotificationServiceClient is the client API for NotificationService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type AuthenticatorClient interface {
-	// Login authenticates using the credentials specified and returns an authentication token
-	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
-	// Refresh refreshes a currently valid token
-	Refresh(ctx context.Context, in *TokenRefreshRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+type NotificationServiceClient interface {
+	Notify(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*NotificationResponse, error)
 }
 
-type authenticatorClient struct {
+type notificationServiceClient struct {
 	cc grpc.ClientConnInterface
 }
 
-func NewAuthenticatorClient(cc grpc.ClientConnInterface) AuthenticatorClient {
-	return &authenticatorClient{cc}
+func NewNotificationServiceClient(cc grpc.ClientConnInterface) NotificationServiceClient {
+	return &notificationServiceClient{cc}
 }
 
-func (c *authenticatorClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
-	out := new(LoginResponse)
-	err := c.cc.Invoke(ctx, "/apiv1.Authenticator/Login", in, out, opts...)
+func (c *notificationServiceClient) Notify(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*NotificationResponse, error) {
+	out := new(NotificationResponse)
+	err := c.cc.Invoke(ctx, "/apiv1.NotificationService/Notify", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *authenticatorClient) Refresh(ctx context.Context, in *TokenRefreshRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
-	out := new(LoginResponse)
-	err := c.cc.Invoke(ctx, "/apiv1.Authenticator/Refresh", in, out, opts...)
+// NotificationServiceServer is the server API for NotificationService service.
+type NotificationServiceServer interface {
+	Notify(context.Context, *NotificationRequest) (*NotificationResponse, error)
+}
+
+// UnimplementedNotificationServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedNotificationServiceServer struct {
+}
+
+func (*UnimplementedNotificationServiceServer) Notify(context.Context, *NotificationRequest) (*NotificationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Notify not implemented")
+}
+
+func RegisterNotificationServiceServer(s *grpc.Server, srv NotificationServiceServer) {
+	s.RegisterService(&_NotificationService_serviceDesc, srv)
+}
+
+func _NotificationService_Notify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).Notify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.NotificationService/Notify",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).Notify(ctx, req.(*NotificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _NotificationService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.NotificationService",
+	HandlerType: (*NotificationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Notify",
+			Handler:    _NotificationService_Notify_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "services.proto",
+}
+
+// PractitionerDirectoryClient is the client API for PractitionerDirectory service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PractitionerDirectoryClient interface {
+	SearchPractitioner(ctx context.Context, in *PractitionerSearchRequest, opts ...grpc.CallOption) (PractitionerDirectory_SearchPractitionerClient, error)
+}
+
+type practitionerDirectoryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPractitionerDirectoryClient(cc grpc.ClientConnInterface) PractitionerDirectoryClient {
+	return &practitionerDirectoryClient{cc}
+}
+
+func (c *practitionerDirectoryClient) SearchPractitioner(ctx context.Context, in *PractitionerSearchRequest, opts ...grpc.CallOption) (PractitionerDirectory_SearchPractitionerClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_PractitionerDirectory_serviceDesc.Streams[0], "/apiv1.PractitionerDirectory/SearchPractitioner", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	x := &practitionerDirectorySearchPractitionerClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-// AuthenticatorServer is the server API for Authenticator service.
-type AuthenticatorServer interface {
-	// Login authenticates using the credentials specified and returns an authentication token
-	Login(context.Context, *LoginRequest) (*LoginResponse, error)
-	// Refresh refreshes a currently valid token
-	Refresh(context.Context, *TokenRefreshRequest) (*LoginResponse, error)
+type PractitionerDirectory_SearchPractitionerClient interface {
+	Recv() (*Practitioner, error)
+	grpc.ClientStream
 }
 
-// UnimplementedAuthenticatorServer can be embedded to have forward compatible implementations.
-type UnimplementedAuthenticatorServer struct {
+type practitionerDirectorySearchPractitionerClient struct {
+	grpc.ClientStream
 }
 
-func (*UnimplementedAuthenticatorServer) Login(context.Context, *LoginRequest) (*LoginResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+func (x *practitionerDirectorySearchPractitionerClient) Recv() (*Practitioner, error) {
+	m := new(Practitioner)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
-func (*UnimplementedAuthenticatorServer) Refresh(context.Context, *TokenRefreshRequest) (*LoginResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Refresh not implemented")
+
+// PractitionerDirectoryServer is the server API for PractitionerDirectory service.
+type PractitionerDirectoryServer interface {
+	SearchPractitioner(*PractitionerSearchRequest, PractitionerDirectory_SearchPractitionerServer) error
+}
+
+// UnimplementedPractitionerDirectoryServer can be embedded to have forward compatible implementations.
+type UnimplementedPractitionerDirectoryServer struct {
+}
+
+func (*UnimplementedPractitionerDirectoryServer) SearchPractitioner(*PractitionerSearchRequest, PractitionerDirectory_SearchPractitionerServer) error {
+	return status.Errorf(codes.Unimplemented, "method SearchPractitioner not implemented")
+}
+
+func RegisterPractitionerDirectoryServer(s *grpc.Server, srv PractitionerDirectoryServer) {
+	s.RegisterService(&_PractitionerDirectory_serviceDesc, srv)
+}
+
+func _PractitionerDirectory_SearchPractitioner_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PractitionerSearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PractitionerDirectoryServer).SearchPractitioner(m, &practitionerDirectorySearchPractitionerServer{stream})
 }
 
-func RegisterAuthenticatorServer(s *grpc.Server, srv AuthenticatorServer) {
-	s.RegisterService(&_Authenticator_serviceDesc, srv)
+type PractitionerDirectory_SearchPractitionerServer interface {
+	Send(*Practitioner) error
+	grpc.ServerStream
 }
 
-func _Authenticator_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LoginRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(AuthenticatorServer).Login(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/apiv1.Authenticator/Login",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthenticatorServer).Login(ctx, req.(*LoginRequest))
-	}
-	return interceptor(ctx, in, info, handler)
+type practitionerDirectorySearchPractitionerServer struct {
+	grpc.ServerStream
 }
 
-func _Authenticator_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TokenRefreshRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(AuthenticatorServer).Refresh(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/apiv1.Authenticator/Refresh",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthenticatorServer).Refresh(ctx, req.(*TokenRefreshRequest))
-	}
-	return interceptor(ctx, in, info, handler)
+func (x *practitionerDirectorySearchPractitionerServer) Send(m *Practitioner) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-var _Authenticator_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "apiv1.Authenticator",
-	HandlerType: (*AuthenticatorServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Login",
-			Handler:    _Authenticator_Login_Handler,
-		},
+var _PractitionerDirectory_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.PractitionerDirectory",
+	HandlerType: (*PractitionerDirectoryServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
 		{
-			MethodName: "Refresh",
-			Handler:    _Authenticator_Refresh_Handler,
+			StreamName:    "SearchPractitioner",
+			Handler:       _PractitionerDirectory_SearchPractitioner_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "services.proto",
 }
 
-// IdentifiersClient is the client API for Identifiers service.
+// PatientDirectoryClient is the client API for PatientDirectory service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type IdentifiersClient interface {
-	GetIdentifier(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*any.Any, error)
-	MapIdentifier(ctx context.Context, in *IdentifierMapRequest, opts ...grpc.CallOption) (Identifiers_MapIdentifierClient, error)
+type PatientDirectoryClient interface {
+	// SearchPatient searches for patients by demographics, returning candidates in order of decreasing match score
+	SearchPatient(ctx context.Context, in *PatientSearchRequest, opts ...grpc.CallOption) (PatientDirectory_SearchPatientClient, error)
+	// GetPatient returns the patient with the identifier specified, such as a NHS number or case record number,
+	// from the EMPI or patient administration system issuing identifiers in that system
+	GetPatient(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*Patient, error)
+	// GetPatients returns the patients with the identifiers specified, which may be from different systems
+	GetPatients(ctx context.Context, in *PatientsRequest, opts ...grpc.CallOption) (*PatientsResponse, error)
 }
 
-type identifiersClient struct {
+type patientDirectoryClient struct {
 	cc grpc.ClientConnInterface
 }
 
-func NewIdentifiersClient(cc grpc.ClientConnInterface) IdentifiersClient {
-	return &identifiersClient{cc}
-}
-
-func (c *identifiersClient) GetIdentifier(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*any.Any, error) {
-	out := new(any.Any)
-	err := c.cc.Invoke(ctx, "/apiv1.Identifiers/GetIdentifier", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func NewPatientDirectoryClient(cc grpc.ClientConnInterface) PatientDirectoryClient {
+	return &patientDirectoryClient{cc}
 }
 
-func (c *identifiersClient) MapIdentifier(ctx context.Context, in *IdentifierMapRequest, opts ...grpc.CallOption) (Identifiers_MapIdentifierClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Identifiers_serviceDesc.Streams[0], "/apiv1.Identifiers/MapIdentifier", opts...)
+func (c *patientDirectoryClient) SearchPatient(ctx context.Context, in *PatientSearchRequest, opts ...grpc.CallOption) (PatientDirectory_SearchPatientClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_PatientDirectory_serviceDesc.Streams[0], "/apiv1.PatientDirectory/SearchPatient", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &identifiersMapIdentifierClient{stream}
+	x := &patientDirectorySearchPatientClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -781,267 +2427,327 @@ func (c *identifiersClient) MapIdentifier(ctx context.Context, in *IdentifierMap
 	return x, nil
 }
 
-type Identifiers_MapIdentifierClient interface {
-	Recv() (*Identifier, error)
+type PatientDirectory_SearchPatientClient interface {
+	Recv() (*Patient, error)
 	grpc.ClientStream
 }
 
-type identifiersMapIdentifierClient struct {
+type patientDirectorySearchPatientClient struct {
 	grpc.ClientStream
 }
 
-func (x *identifiersMapIdentifierClient) Recv() (*Identifier, error) {
-	m := new(Identifier)
+func (x *patientDirectorySearchPatientClient) Recv() (*Patient, error) {
+	m := new(Patient)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-// IdentifiersServer is the server API for Identifiers service.
-type IdentifiersServer interface {
-	GetIdentifier(context.Context, *Identifier) (*any.Any, error)
-	MapIdentifier(*IdentifierMapRequest, Identifiers_MapIdentifierServer) error
+func (c *patientDirectoryClient) GetPatient(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*Patient, error) {
+	out := new(Patient)
+	err := c.cc.Invoke(ctx, "/apiv1.PatientDirectory/GetPatient", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// UnimplementedIdentifiersServer can be embedded to have forward compatible implementations.
-type UnimplementedIdentifiersServer struct {
+func (c *patientDirectoryClient) GetPatients(ctx context.Context, in *PatientsRequest, opts ...grpc.CallOption) (*PatientsResponse, error) {
+	out := new(PatientsResponse)
+	err := c.cc.Invoke(ctx, "/apiv1.PatientDirectory/GetPatients", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (*UnimplementedIdentifiersServer) GetIdentifier(context.Context, *Identifier) (*any.Any, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetIdentifier not implemented")
+// PatientDirectoryServer is the server API for PatientDirectory service.
+type PatientDirectoryServer interface {
+	// SearchPatient searches for patients by demographics, returning candidates in order of decreasing match score
+	SearchPatient(*PatientSearchRequest, PatientDirectory_SearchPatientServer) error
+	// GetPatient returns the patient with the identifier specified, such as a NHS number or case record number,
+	// from the EMPI or patient administration system issuing identifiers in that system
+	GetPatient(context.Context, *Identifier) (*Patient, error)
+	// GetPatients returns the patients with the identifiers specified, which may be from different systems
+	GetPatients(context.Context, *PatientsRequest) (*PatientsResponse, error)
 }
-func (*UnimplementedIdentifiersServer) MapIdentifier(*IdentifierMapRequest, Identifiers_MapIdentifierServer) error {
-	return status.Errorf(codes.Unimplemented, "method MapIdentifier not implemented")
+
+// UnimplementedPatientDirectoryServer can be embedded to have forward compatible implementations.
+type UnimplementedPatientDirectoryServer struct {
 }
 
-func RegisterIdentifiersServer(s *grpc.Server, srv IdentifiersServer) {
-	s.RegisterService(&_Identifiers_serviceDesc, srv)
+func (*UnimplementedPatientDirectoryServer) SearchPatient(*PatientSearchRequest, PatientDirectory_SearchPatientServer) error {
+	return status.Errorf(codes.Unimplemented, "method SearchPatient not implemented")
+}
+func (*UnimplementedPatientDirectoryServer) GetPatient(context.Context, *Identifier) (*Patient, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPatient not implemented")
+}
+func (*UnimplementedPatientDirectoryServer) GetPatients(context.Context, *PatientsRequest) (*PatientsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPatients not implemented")
 }
 
-func _Identifiers_GetIdentifier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func RegisterPatientDirectoryServer(s *grpc.Server, srv PatientDirectoryServer) {
+	s.RegisterService(&_PatientDirectory_serviceDesc, srv)
+}
+
+func _PatientDirectory_SearchPatient_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PatientSearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PatientDirectoryServer).SearchPatient(m, &patientDirectorySearchPatientServer{stream})
+}
+
+type PatientDirectory_SearchPatientServer interface {
+	Send(*Patient) error
+	grpc.ServerStream
+}
+
+type patientDirectorySearchPatientServer struct {
+	grpc.ServerStream
+}
+
+func (x *patientDirectorySearchPatientServer) Send(m *Patient) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PatientDirectory_GetPatient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Identifier)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(IdentifiersServer).GetIdentifier(ctx, in)
+		return srv.(PatientDirectoryServer).GetPatient(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/apiv1.Identifiers/GetIdentifier",
+		FullMethod: "/apiv1.PatientDirectory/GetPatient",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(IdentifiersServer).GetIdentifier(ctx, req.(*Identifier))
+		return srv.(PatientDirectoryServer).GetPatient(ctx, req.(*Identifier))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Identifiers_MapIdentifier_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(IdentifierMapRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _PatientDirectory_GetPatients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(IdentifiersServer).MapIdentifier(m, &identifiersMapIdentifierServer{stream})
-}
-
-type Identifiers_MapIdentifierServer interface {
-	Send(*Identifier) error
-	grpc.ServerStream
-}
-
-type identifiersMapIdentifierServer struct {
-	grpc.ServerStream
-}
-
-func (x *identifiersMapIdentifierServer) Send(m *Identifier) error {
-	return x.ServerStream.SendMsg(m)
+	if interceptor == nil {
+		return srv.(PatientDirectoryServer).GetPatients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/apiv1.PatientDirectory/GetPatients",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PatientDirectoryServer).GetPatients(ctx, req.(*PatientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-var _Identifiers_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "apiv1.Identifiers",
-	HandlerType: (*IdentifiersServer)(nil),
+var _PatientDirectory_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.PatientDirectory",
+	HandlerType: (*PatientDirectoryServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "GetIdentifier",
-			Handler:    _Identifiers_GetIdentifier_Handler,
+			MethodName: "GetPatient",
+			Handler:    _PatientDirectory_GetPatient_Handler,
+		},
+		{
+			MethodName: "GetPatients",
+			Handler:    _PatientDirectory_GetPatients_Handler,
 		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
-			StreamName:    "MapIdentifier",
-			Handler:       _Identifiers_MapIdentifier_Handler,
+			StreamName:    "SearchPatient",
+			Handler:       _PatientDirectory_SearchPatient_Handler,
 			ServerStreams: true,
 		},
 	},
 	Metadata: "services.proto",
 }
 
-// DocumentServiceClient is the client API for DocumentService service.
+// ReconciliationClient is the client API for Reconciliation service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type DocumentServiceClient interface {
-	PublishDocument(ctx context.Context, in *PublishDocumentRequest, opts ...grpc.CallOption) (*PublishDocumentResponse, error)
+type ReconciliationClient interface {
+	// ReconcilePatient fetches the patient with the identifier specified from both the EMPI and the patient
+	// administration system of the health board, returning a merged view and the discrepancies between them
+	ReconcilePatient(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*PatientReconciliation, error)
 }
 
-type documentServiceClient struct {
+type reconciliationClient struct {
 	cc grpc.ClientConnInterface
 }
 
-func NewDocumentServiceClient(cc grpc.ClientConnInterface) DocumentServiceClient {
-	return &documentServiceClient{cc}
+func NewReconciliationClient(cc grpc.ClientConnInterface) ReconciliationClient {
+	return &reconciliationClient{cc}
 }
 
-func (c *documentServiceClient) PublishDocument(ctx context.Context, in *PublishDocumentRequest, opts ...grpc.CallOption) (*PublishDocumentResponse, error) {
-	out := new(PublishDocumentResponse)
-	err := c.cc.Invoke(ctx, "/apiv1.DocumentService/PublishDocument", in, out, opts...)
+func (c *reconciliationClient) ReconcilePatient(ctx context.Context, in *Identifier, opts ...grpc.CallOption) (*PatientReconciliation, error) {
+	out := new(PatientReconciliation)
+	err := c.cc.Invoke(ctx, "/apiv1.Reconciliation/ReconcilePatient", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// DocumentServiceServer is the server API for DocumentService service.
-type DocumentServiceServer interface {
-	PublishDocument(context.Context, *PublishDocumentRequest) (*PublishDocumentResponse, error)
+// ReconciliationServer is the server API for Reconciliation service.
+type ReconciliationServer interface {
+	// ReconcilePatient fetches the patient with the identifier specified from both the EMPI and the patient
+	// administration system of the health board, returning a merged view and the discrepancies between them
+	ReconcilePatient(context.Context, *Identifier) (*PatientReconciliation, error)
 }
 
-// UnimplementedDocumentServiceServer can be embedded to have forward compatible implementations.
-type UnimplementedDocumentServiceServer struct {
+// UnimplementedReconciliationServer can be embedded to have forward compatible implementations.
+type UnimplementedReconciliationServer struct {
 }
 
-func (*UnimplementedDocumentServiceServer) PublishDocument(context.Context, *PublishDocumentRequest) (*PublishDocumentResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PublishDocument not implemented")
+func (*UnimplementedReconciliationServer) ReconcilePatient(context.Context, *Identifier) (*PatientReconciliation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReconcilePatient not implemented")
 }
 
-func RegisterDocumentServiceServer(s *grpc.Server, srv DocumentServiceServer) {
-	s.RegisterService(&_DocumentService_serviceDesc, srv)
+func RegisterReconciliationServer(s *grpc.Server, srv ReconciliationServer) {
+	s.RegisterService(&_Reconciliation_serviceDesc, srv)
 }
 
-func _DocumentService_PublishDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PublishDocumentRequest)
+func _Reconciliation_ReconcilePatient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Identifier)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DocumentServiceServer).PublishDocument(ctx, in)
+		return srv.(ReconciliationServer).ReconcilePatient(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/apiv1.DocumentService/PublishDocument",
+		FullMethod: "/apiv1.Reconciliation/ReconcilePatient",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DocumentServiceServer).PublishDocument(ctx, req.(*PublishDocumentRequest))
+		return srv.(ReconciliationServer).ReconcilePatient(ctx, req.(*Identifier))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _DocumentService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "apiv1.DocumentService",
-	HandlerType: (*DocumentServiceServer)(nil),
+var _Reconciliation_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.Reconciliation",
+	HandlerType: (*ReconciliationServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "PublishDocument",
-			Handler:    _DocumentService_PublishDocument_Handler,
+			MethodName: "ReconcilePatient",
+			Handler:    _Reconciliation_ReconcilePatient_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "services.proto",
 }
 
-// NotificationServiceClient is the client API for NotificationService service.
+// PatientMatchingClient is the client API for PatientMatching service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type NotificationServiceClient interface {
-	Notify(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*NotificationResponse, error)
+type PatientMatchingClient interface {
+	// MatchPatient compares a patient with one or more candidates, such as two records thought to be for the same
+	// patient, returning the weight of evidence that each candidate is the same patient and its classification as a
+	// match, possible match or non-match, in order of decreasing weight
+	MatchPatient(ctx context.Context, in *PatientMatchRequest, opts ...grpc.CallOption) (*PatientMatchResponse, error)
 }
 
-type notificationServiceClient struct {
+type patientMatchingClient struct {
 	cc grpc.ClientConnInterface
 }
 
-func NewNotificationServiceClient(cc grpc.ClientConnInterface) NotificationServiceClient {
-	return &notificationServiceClient{cc}
+func NewPatientMatchingClient(cc grpc.ClientConnInterface) PatientMatchingClient {
+	return &patientMatchingClient{cc}
 }
 
-func (c *notificationServiceClient) Notify(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*NotificationResponse, error) {
-	out := new(NotificationResponse)
-	err := c.cc.Invoke(ctx, "/apiv1.NotificationService/Notify", in, out, opts...)
+func (c *patientMatchingClient) MatchPatient(ctx context.Context, in *PatientMatchRequest, opts ...grpc.CallOption) (*PatientMatchResponse, error) {
+	out := new(PatientMatchResponse)
+	err := c.cc.Invoke(ctx, "/apiv1.PatientMatching/MatchPatient", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// NotificationServiceServer is the server API for NotificationService service.
-type NotificationServiceServer interface {
-	Notify(context.Context, *NotificationRequest) (*NotificationResponse, error)
+// PatientMatchingServer is the server API for PatientMatching service.
+type PatientMatchingServer interface {
+	// MatchPatient compares a patient with one or more candidates, such as two records thought to be for the same
+	// patient, returning the weight of evidence that each candidate is the same patient and its classification as a
+	// match, possible match or non-match, in order of decreasing weight
+	MatchPatient(context.Context, *PatientMatchRequest) (*PatientMatchResponse, error)
 }
 
-// UnimplementedNotificationServiceServer can be embedded to have forward compatible implementations.
-type UnimplementedNotificationServiceServer struct {
+// UnimplementedPatientMatchingServer can be embedded to have forward compatible implementations.
+type UnimplementedPatientMatchingServer struct {
 }
 
-func (*UnimplementedNotificationServiceServer) Notify(context.Context, *NotificationRequest) (*NotificationResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Notify not implemented")
+func (*UnimplementedPatientMatchingServer) MatchPatient(context.Context, *PatientMatchRequest) (*PatientMatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MatchPatient not implemented")
 }
 
-func RegisterNotificationServiceServer(s *grpc.Server, srv NotificationServiceServer) {
-	s.RegisterService(&_NotificationService_serviceDesc, srv)
+func RegisterPatientMatchingServer(s *grpc.Server, srv PatientMatchingServer) {
+	s.RegisterService(&_PatientMatching_serviceDesc, srv)
 }
 
-func _NotificationService_Notify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NotificationRequest)
+func _PatientMatching_MatchPatient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatientMatchRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(NotificationServiceServer).Notify(ctx, in)
+		return srv.(PatientMatchingServer).MatchPatient(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/apiv1.NotificationService/Notify",
+		FullMethod: "/apiv1.PatientMatching/MatchPatient",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NotificationServiceServer).Notify(ctx, req.(*NotificationRequest))
+		return srv.(PatientMatchingServer).MatchPatient(ctx, req.(*PatientMatchRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _NotificationService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "apiv1.NotificationService",
-	HandlerType: (*NotificationServiceServer)(nil),
+var _PatientMatching_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.PatientMatching",
+	HandlerType: (*PatientMatchingServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "Notify",
-			Handler:    _NotificationService_Notify_Handler,
+			MethodName: "MatchPatient",
+			Handler:    _PatientMatching_MatchPatient_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "services.proto",
 }
 
-// PractitionerDirectoryClient is the client API for PractitionerDirectory service.
+// PatientEventsClient is the client API for PatientEvents service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type PractitionerDirectoryClient interface {
-	SearchPractitioner(ctx context.Context, in *PractitionerSearchRequest, opts ...grpc.CallOption) (PractitionerDirectory_SearchPractitionerClient, error)
+type PatientEventsClient interface {
+	// Subscribe streams notifications of changes to patients, such as admissions, updates and merges, as they
+	// are received from patient administration or EMPI feeds
+	Subscribe(ctx context.Context, in *PatientEventsRequest, opts ...grpc.CallOption) (PatientEvents_SubscribeClient, error)
 }
 
-type practitionerDirectoryClient struct {
+type patientEventsClient struct {
 	cc grpc.ClientConnInterface
 }
 
-func NewPractitionerDirectoryClient(cc grpc.ClientConnInterface) PractitionerDirectoryClient {
-	return &practitionerDirectoryClient{cc}
+func NewPatientEventsClient(cc grpc.ClientConnInterface) PatientEventsClient {
+	return &patientEventsClient{cc}
 }
 
-func (c *practitionerDirectoryClient) SearchPractitioner(ctx context.Context, in *PractitionerSearchRequest, opts ...grpc.CallOption) (PractitionerDirectory_SearchPractitionerClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_PractitionerDirectory_serviceDesc.Streams[0], "/apiv1.PractitionerDirectory/SearchPractitioner", opts...)
+func (c *patientEventsClient) Subscribe(ctx context.Context, in *PatientEventsRequest, opts ...grpc.CallOption) (PatientEvents_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_PatientEvents_serviceDesc.Streams[0], "/apiv1.PatientEvents/Subscribe", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &practitionerDirectorySearchPractitionerClient{stream}
+	x := &patientEventsSubscribeClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -1051,69 +2757,71 @@ func (c *practitionerDirectoryClient) SearchPractitioner(ctx context.Context, in
 	return x, nil
 }
 
-type PractitionerDirectory_SearchPractitionerClient interface {
-	Recv() (*Practitioner, error)
+type PatientEvents_SubscribeClient interface {
+	Recv() (*PatientEvent, error)
 	grpc.ClientStream
 }
 
-type practitionerDirectorySearchPractitionerClient struct {
+type patientEventsSubscribeClient struct {
 	grpc.ClientStream
 }
 
-func (x *practitionerDirectorySearchPractitionerClient) Recv() (*Practitioner, error) {
-	m := new(Practitioner)
+func (x *patientEventsSubscribeClient) Recv() (*PatientEvent, error) {
+	m := new(PatientEvent)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-// PractitionerDirectoryServer is the server API for PractitionerDirectory service.
-type PractitionerDirectoryServer interface {
-	SearchPractitioner(*PractitionerSearchRequest, PractitionerDirectory_SearchPractitionerServer) error
+// PatientEventsServer is the server API for PatientEvents service.
+type PatientEventsServer interface {
+	// Subscribe streams notifications of changes to patients, such as admissions, updates and merges, as they
+	// are received from patient administration or EMPI feeds
+	Subscribe(*PatientEventsRequest, PatientEvents_SubscribeServer) error
 }
 
-// UnimplementedPractitionerDirectoryServer can be embedded to have forward compatible implementations.
-type UnimplementedPractitionerDirectoryServer struct {
+// UnimplementedPatientEventsServer can be embedded to have forward compatible implementations.
+type UnimplementedPatientEventsServer struct {
 }
 
-func (*UnimplementedPractitionerDirectoryServer) SearchPractitioner(*PractitionerSearchRequest, PractitionerDirectory_SearchPractitionerServer) error {
-	return status.Errorf(codes.Unimplemented, "method SearchPractitioner not implemented")
+func (*UnimplementedPatientEventsServer) Subscribe(*PatientEventsRequest, PatientEvents_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
 }
 
-func RegisterPractitionerDirectoryServer(s *grpc.Server, srv PractitionerDirectoryServer) {
-	s.RegisterService(&_PractitionerDirectory_serviceDesc, srv)
+func RegisterPatientEventsServer(s *grpc.Server, srv PatientEventsServer) {
+	s.RegisterService(&_PatientEvents_serviceDesc, srv)
 }
 
-func _PractitionerDirectory_SearchPractitioner_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(PractitionerSearchRequest)
+func _PatientEvents_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PatientEventsRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(PractitionerDirectoryServer).SearchPractitioner(m, &practitionerDirectorySearchPractitionerServer{stream})
+	return srv.(PatientEventsServer).Subscribe(m, &patientEventsSubscribeServer{stream})
 }
 
-type PractitionerDirectory_SearchPractitionerServer interface {
-	Send(*Practitioner) error
+type PatientEvents_SubscribeServer interface {
+	Send(*PatientEvent) error
 	grpc.ServerStream
 }
 
-type practitionerDirectorySearchPractitionerServer struct {
+type patientEventsSubscribeServer struct {
 	grpc.ServerStream
 }
 
-func (x *practitionerDirectorySearchPractitionerServer) Send(m *Practitioner) error {
+func (x *patientEventsSubscribeServer) Send(m *PatientEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-var _PractitionerDirectory_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "apiv1.PractitionerDirectory",
-	HandlerType: (*PractitionerDirectoryServer)(nil),
+var _PatientEvents_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "apiv1.PatientEvents",
+	HandlerType: (*PatientEventsServer)(nil),
 	Methods:     []grpc.MethodDesc{},
 	Streams: []grpc.StreamDesc{
 		{
-			StreamName:    "SearchPractitioner",
-			Handler:       _PractitionerDirectory_SearchPractitioner_Handler,
+			StreamName:    "Subscribe",
+			Handler:       _PatientEvents_Subscribe_Handler,
 			ServerStreams: true,
 		},
 	},
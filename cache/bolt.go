@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// purgeInterval is how often persistent stores remove expired entries
+const purgeInterval = 10 * time.Minute
+
+var boltBucket = []byte("cache")
+
+// boltStore is an embedded on-disk store, which survives restarts, but can only be opened by a single process
+type boltStore struct {
+	db        *bolt.DB
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+var _ Store = (*boltStore)(nil)
+
+// OpenBoltStore opens, or creates, an on-disk store at the specified path
+func OpenBoltStore(path string) (Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+	bs := &boltStore{db: db, done: make(chan struct{})}
+	go purgePeriodically(bs.purge, bs.done)
+	log.Printf("cache: opened on-disk cache: %s", path)
+	return bs, nil
+}
+
+// Values are stored with their expiry time as a prefix, in nanoseconds since the unix epoch, zero if no expiry
+func encodeExpiring(value []byte, ttl time.Duration) []byte {
+	b := make([]byte, 8+len(value))
+	if ttl > 0 {
+		binary.BigEndian.PutUint64(b, uint64(time.Now().Add(ttl).UnixNano()))
+	}
+	copy(b[8:], value)
+	return b
+}
+
+func decodeExpiring(b []byte, now time.Time) ([]byte, bool) {
+	if len(b) < 8 {
+		return nil, false
+	}
+	if expires := binary.BigEndian.Uint64(b); expires != 0 && now.UnixNano() >= int64(expires) {
+		return nil, false
+	}
+	return b[8:], true
+}
+
+func (bs *boltStore) Get(key string) ([]byte, bool, error) {
+	var result []byte
+	var found bool
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		if v := tx.Bucket(boltBucket).Get([]byte(key)); v != nil {
+			var value []byte
+			if value, found = decodeExpiring(v, time.Now()); found {
+				result = append([]byte(nil), value...) // only valid during the transaction
+			}
+		}
+		return nil
+	})
+	return result, found, err
+}
+
+func (bs *boltStore) Set(key string, value []byte, ttl time.Duration) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(key), encodeExpiring(value, ttl))
+	})
+}
+
+func (bs *boltStore) Delete(key string) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete([]byte(key))
+	})
+}
+
+func (bs *boltStore) Flush(prefix string) error {
+	return bs.deleteWhere(prefix, func(v []byte) bool { return true })
+}
+
+func (bs *boltStore) Len(prefix string) (int, error) {
+	n := 0
+	now := time.Now()
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltBucket).Cursor()
+		for k, v := c.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, v = c.Next() {
+			if _, ok := decodeExpiring(v, now); ok {
+				n++
+			}
+		}
+		return nil
+	})
+	return n, err
+}
+
+func (bs *boltStore) Keys(prefix string) ([]string, error) {
+	var keys []string
+	now := time.Now()
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltBucket).Cursor()
+		for k, v := c.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, v = c.Next() {
+			if _, ok := decodeExpiring(v, now); ok {
+				keys = append(keys, string(k))
+			}
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// purge removes expired entries
+func (bs *boltStore) purge() error {
+	now := time.Now()
+	return bs.deleteWhere("", func(v []byte) bool {
+		_, ok := decodeExpiring(v, now)
+		return !ok
+	})
+}
+
+// deleteWhere deletes the entries with keys beginning with the prefix for which f returns true
+func (bs *boltStore) deleteWhere(prefix string, f func(v []byte) bool) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltBucket).Cursor()
+		for k, v := c.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); {
+			if f(v) {
+				key := append([]byte(nil), k...)
+				if err := c.Delete(); err != nil {
+					return err
+				}
+				k, v = c.Seek(key) // deleting moves the cursor, so seek to the following key
+				continue
+			}
+			k, v = c.Next()
+		}
+		return nil
+	})
+}
+
+func (bs *boltStore) Close() error {
+	bs.closeOnce.Do(func() { close(bs.done) })
+	return bs.db.Close()
+}
+
+// purgePeriodically calls purge until done is closed
+func purgePeriodically(purge func() error, done <-chan struct{}) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := purge(); err != nil {
+				log.Printf("cache: failed to remove expired entries: %s", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,253 @@
+// Package cache provides a cache of protocol buffer messages, such as resolved patients, with pluggable
+// storage. Entries are serialized, optionally encrypted at rest, and expire after a fixed duration.
+//
+// A Store holds the encrypted entries. The memory store is private to a single process, while the on-disk
+// store survives restarts and a PostgreSQL store can be shared between replicas. Many caches, such as one for
+// each resolver, can share the same store, as each cache uses its own prefix for keys.
+//
+// With an encryption key, the keys of entries are also hashed (HMAC-SHA256), so that a store does not reveal
+// identifiers, such as NHS numbers, used as keys.
+package cache
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// KeySize is the size, in bytes, of the key used to encrypt cache entries (AES-256)
+const KeySize = 32
+
+// Store is storage for cache entries, which are opaque to the store. Implementations must be thread-safe.
+type Store interface {
+	// Get returns the value for the specified key, if it exists and has not expired
+	Get(key string) ([]byte, bool, error)
+	// Set sets the value for the specified key, expiring after the duration specified
+	Set(key string, value []byte, ttl time.Duration) error
+	// Delete removes the value for the specified key
+	Delete(key string) error
+	// Flush removes all entries with keys beginning with the prefix
+	Flush(prefix string) error
+	// Len returns the number of unexpired entries with keys beginning with the prefix
+	Len(prefix string) (int, error)
+	// Keys returns the keys of unexpired entries beginning with the prefix, in order
+	Keys(prefix string) ([]string, error)
+	// Close closes the store
+	Close() error
+}
+
+// Open opens the store specified:
+//   "memory" or "" for an in-memory store
+//   "file:<path>" for an on-disk store (bbolt)
+//   "postgres://..." for a networked store, using a PostgreSQL database
+func Open(uri string) (Store, error) {
+	switch {
+	case uri == "" || uri == "memory":
+		return NewMemoryStore(), nil
+	case strings.HasPrefix(uri, "file:"):
+		return OpenBoltStore(strings.TrimPrefix(uri, "file:"))
+	case strings.HasPrefix(uri, "postgres://") || strings.HasPrefix(uri, "postgresql://"):
+		return OpenPostgresStore(uri)
+	}
+	return nil, fmt.Errorf("cache: unsupported store: '%s'", uri)
+}
+
+// ParseKey parses a base64 encoded encryption key, such as one generated using 'openssl rand -base64 32'
+func ParseKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("cache: invalid key: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("cache: invalid key: must be %d bytes, got %d", KeySize, len(key))
+	}
+	return key, nil
+}
+
+// Cache is a cache of protocol buffer messages. This is thread-safe.
+type Cache struct {
+	store  Store
+	prefix string
+	ttl    time.Duration
+	aead   cipher.AEAD // nil if entries are not encrypted
+	mac    []byte      // key used to hash the keys of entries, or nil if entries are not encrypted
+}
+
+// macContext distinguishes the key used to hash the keys of entries from the key used to encrypt them
+const macContext = "concierge cache keys"
+
+// New creates a cache using the specified store, with keys prefixed, such as "empi/", to permit the store
+// to be shared. Entries expire after the duration specified, and are encrypted using the key, if not nil, in
+// which case their keys are hashed using a key derived from it.
+func New(store Store, prefix string, ttl time.Duration, key []byte) (*Cache, error) {
+	c := &Cache{store: store, prefix: prefix, ttl: ttl}
+	if key != nil {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("cache: invalid key: %w", err)
+		}
+		if c.aead, err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(macContext))
+		c.mac = h.Sum(nil)
+	}
+	return c, nil
+}
+
+// TTL returns the duration after which entries expire
+func (c *Cache) TTL() time.Duration {
+	return c.ttl
+}
+
+// Get reads the entry for the specified key into the message, returning whether the entry was found.
+// Entries that cannot be decrypted or parsed, such as after changing the encryption key, are treated as missing.
+// They are left in place, as other replicas sharing the store may still be using the previous key, and are
+// replaced when next set, or expire.
+func (c *Cache) Get(key string, m proto.Message) (bool, error) {
+	b, found, err := c.read(key)
+	if err != nil || !found {
+		return false, err
+	}
+	if err := proto.Unmarshal(b, m); err != nil {
+		log.Printf("cache: ignoring invalid entry '%s%s': %s", c.prefix, key, err)
+		return false, nil
+	}
+	return true, nil
+}
+
+// Set sets the entry for the specified key
+func (c *Cache) Set(key string, m proto.Message) error {
+	b, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return c.write(key, b)
+}
+
+// GetTimestamped reads an entry set using SetTimestamped into the message, returning the time recorded with
+// the entry and whether the entry was found. Invalid entries are treated as missing, as for Get.
+func (c *Cache) GetTimestamped(key string, m proto.Message) (time.Time, bool, error) {
+	b, found, err := c.read(key)
+	if err != nil || !found {
+		return time.Time{}, false, err
+	}
+	if len(b) < timestampSize {
+		log.Printf("cache: ignoring invalid entry '%s%s': entry too short", c.prefix, key)
+		return time.Time{}, false, nil
+	}
+	if err := proto.Unmarshal(b[timestampSize:], m); err != nil {
+		log.Printf("cache: ignoring invalid entry '%s%s': %s", c.prefix, key, err)
+		return time.Time{}, false, nil
+	}
+	return time.Unix(0, int64(binary.BigEndian.Uint64(b))), true, nil
+}
+
+// SetTimestamped sets the entry for the specified key, recording a time with the entry, such as when the
+// message was fetched
+func (c *Cache) SetTimestamped(key string, m proto.Message, t time.Time) error {
+	b, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+	value := make([]byte, timestampSize, timestampSize+len(b))
+	binary.BigEndian.PutUint64(value, uint64(t.UnixNano()))
+	return c.write(key, append(value, b...))
+}
+
+// timestampSize is the size of the time, in nanoseconds since the Unix epoch, preceding timestamped entries
+const timestampSize = 8
+
+// read returns the decrypted value of the entry for the specified key. Entries that cannot be decrypted are
+// treated as missing.
+func (c *Cache) read(key string) ([]byte, bool, error) {
+	b, found, err := c.store.Get(c.storeKey(key))
+	if err != nil || !found {
+		return nil, false, err
+	}
+	if b, err = c.open(key, b); err != nil {
+		log.Printf("cache: ignoring invalid entry '%s%s': %s", c.prefix, key, err)
+		return nil, false, nil
+	}
+	return b, true, nil
+}
+
+// write encrypts and stores the value of the entry for the specified key
+func (c *Cache) write(key string, value []byte) error {
+	b, err := c.seal(key, value)
+	if err != nil {
+		return err
+	}
+	return c.store.Set(c.storeKey(key), b, c.ttl)
+}
+
+// Delete removes the entry for the specified key
+func (c *Cache) Delete(key string) error {
+	return c.store.Delete(c.storeKey(key))
+}
+
+// storeKey returns the key in the store for the entry with the specified key: the key itself, with the cache's
+// prefix, or its hash if the cache is encrypted
+func (c *Cache) storeKey(key string) string {
+	if c.mac == nil {
+		return c.prefix + key
+	}
+	h := hmac.New(sha256.New, c.mac)
+	h.Write([]byte(key))
+	return c.prefix + hex.EncodeToString(h.Sum(nil))
+}
+
+// ItemCount returns the number of entries in the cache
+func (c *Cache) ItemCount() int {
+	n, err := c.store.Len(c.prefix)
+	if err != nil {
+		log.Printf("cache: failed to count entries for '%s': %s", c.prefix, err)
+	}
+	return n
+}
+
+// Flush removes all entries from the cache
+func (c *Cache) Flush() {
+	if err := c.store.Flush(c.prefix); err != nil {
+		log.Printf("cache: failed to flush entries for '%s': %s", c.prefix, err)
+	}
+}
+
+// seal encrypts the value, if the cache is encrypted. The key is authenticated, so that entries cannot be
+// swapped between keys.
+func (c *Cache) seal(key string, value []byte) ([]byte, error) {
+	if c.aead == nil {
+		return value, nil
+	}
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(value)+c.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, value, []byte(c.prefix+key)), nil
+}
+
+// open decrypts a value, if the cache is encrypted
+func (c *Cache) open(key string, value []byte) ([]byte, error) {
+	if c.aead == nil {
+		return value, nil
+	}
+	if len(value) < c.aead.NonceSize() {
+		return nil, errors.New("entry too short")
+	}
+	nonce, ciphertext := value[:c.aead.NonceSize()], value[c.aead.NonceSize():]
+	return c.aead.Open(nil, nonce, ciphertext, []byte(c.prefix+key))
+}
@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/protobuf/proto"
+)
+
+var testKey = bytes.Repeat([]byte{42}, KeySize)
+
+func testStore(t *testing.T, store Store) {
+	defer store.Close()
+	c, err := New(store, "empi/", time.Hour, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := New(store, "cav/", time.Hour, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt := &apiv1.Patient{Lastname: "DUMMY", Firstnames: "ALBERT"}
+	if err := c.Set("1111111111", pt); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Set("A999998", pt); err != nil {
+		t.Fatal(err)
+	}
+	got := new(apiv1.Patient)
+	if found, err := c.Get("1111111111", got); err != nil || !found || !proto.Equal(got, pt) {
+		t.Fatalf("cached patient not returned: %v %v %v", got, found, err)
+	}
+	if found, _ := c.Get("A999998", got); found {
+		t.Fatal("cache returned entry from another prefix")
+	}
+	// entries are encrypted at rest, and their keys hashed
+	keys, err := store.Keys("empi/")
+	if err != nil || len(keys) != 1 || strings.Contains(keys[0], "1111111111") {
+		t.Fatalf("entry not stored using hashed key: %v (%v)", keys, err)
+	}
+	b, found, err := store.Get(keys[0])
+	if err != nil || !found {
+		t.Fatalf("entry not stored: %v", err)
+	}
+	if bytes.Contains(b, []byte("DUMMY")) {
+		t.Fatal("entry not encrypted")
+	}
+	// and cannot be read with another key, but are left for those, such as other replicas, using the right key
+	wrongKey, _ := New(store, "empi/", time.Hour, bytes.Repeat([]byte{7}, KeySize))
+	if found, err := wrongKey.Get("1111111111", got); found || err != nil {
+		t.Fatalf("entry read using wrong key: %v %v", found, err)
+	}
+	if c.ItemCount() != 1 || other.ItemCount() != 1 {
+		t.Fatalf("unexpected item count: empi:%d cav:%d", c.ItemCount(), other.ItemCount())
+	}
+	other.Set("A999999", pt)
+	other.Delete("A999999")
+	if found, _ := other.Get("A999999", got); found || other.ItemCount() != 1 {
+		t.Fatalf("entry not deleted")
+	}
+	if found, err := c.Get("1111111111", got); err != nil || !found || !proto.Equal(got, pt) {
+		t.Fatalf("entry not readable after read using wrong key: %v %v %v", got, found, err)
+	}
+	// entries may be timestamped
+	fetched := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	if err := other.SetTimestamped("A999997", pt, fetched); err != nil {
+		t.Fatal(err)
+	}
+	got = new(apiv1.Patient)
+	if ts, found, err := other.GetTimestamped("A999997", got); err != nil || !found || !ts.Equal(fetched) || !proto.Equal(got, pt) {
+		t.Fatalf("timestamped entry not returned: %v %v %v %v", got, ts, found, err)
+	}
+	other.Delete("A999997")
+	// entries expire
+	short, _ := New(store, "short/", 10*time.Millisecond, nil)
+	if err := short.Set("1", pt); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if found, _ := short.Get("1", got); found {
+		t.Fatal("expired entry returned")
+	}
+	// flushing only affects entries with the cache's prefix
+	c.Set("1111111111", pt)
+	c.Flush()
+	if c.ItemCount() != 0 || other.ItemCount() != 1 {
+		t.Fatalf("unexpected item count after flush: empi:%d cav:%d", c.ItemCount(), other.ItemCount())
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestBoltStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "concierge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cache.db")
+	store, err := Open("file:" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, store)
+
+	// entries persist after reopening
+	store, err = OpenBoltStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	c, _ := New(store, "cav/", time.Hour, testKey)
+	if found, err := c.Get("A999998", new(apiv1.Patient)); !found || err != nil {
+		t.Fatalf("entry not persisted: %v", err)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	if key, err := ParseKey(base64.StdEncoding.EncodeToString(testKey)); err != nil || !bytes.Equal(key, testKey) {
+		t.Fatalf("failed to parse key: %v", err)
+	}
+	if _, err := ParseKey(base64.StdEncoding.EncodeToString([]byte("short"))); err == nil {
+		t.Fatal("accepted short key")
+	}
+	if _, err := Open("redis://localhost"); err == nil {
+		t.Fatal("accepted unsupported store")
+	}
+}
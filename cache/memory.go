@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+)
+
+// memoryStore is an in-memory store, private to a single process
+type memoryStore struct {
+	cache *gocache.Cache
+}
+
+var _ Store = (*memoryStore)(nil)
+
+// NewMemoryStore creates an in-memory store
+func NewMemoryStore() Store {
+	return &memoryStore{cache: gocache.New(gocache.NoExpiration, 10*time.Minute)}
+}
+
+func (ms *memoryStore) Get(key string) ([]byte, bool, error) {
+	if o, found := ms.cache.Get(key); found {
+		return o.([]byte), true, nil
+	}
+	return nil, false, nil
+}
+
+func (ms *memoryStore) Set(key string, value []byte, ttl time.Duration) error {
+	ms.cache.Set(key, value, ttl)
+	return nil
+}
+
+func (ms *memoryStore) Delete(key string) error {
+	ms.cache.Delete(key)
+	return nil
+}
+
+func (ms *memoryStore) Flush(prefix string) error {
+	for key := range ms.cache.Items() {
+		if strings.HasPrefix(key, prefix) {
+			ms.cache.Delete(key)
+		}
+	}
+	return nil
+}
+
+func (ms *memoryStore) Len(prefix string) (int, error) {
+	n := 0
+	for key := range ms.cache.Items() { // only unexpired items
+		if strings.HasPrefix(key, prefix) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (ms *memoryStore) Keys(prefix string) ([]string, error) {
+	var keys []string
+	for key := range ms.cache.Items() {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (ms *memoryStore) Close() error {
+	ms.cache.Flush()
+	return nil
+}
@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// postgresStore is a networked store, using a PostgreSQL database, which can be shared between replicas
+type postgresStore struct {
+	db        *sql.DB
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+var _ Store = (*postgresStore)(nil)
+
+// OpenPostgresStore opens a store using the PostgreSQL database specified, creating the cache table if necessary
+func OpenPostgresStore(connStr string) (Store, error) {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS cache (
+		key text PRIMARY KEY,
+		value bytea NOT NULL,
+		expires timestamptz
+	)`); err != nil {
+		db.Close()
+		return nil, err
+	}
+	ps := &postgresStore{db: db, done: make(chan struct{})}
+	go purgePeriodically(ps.purge, ps.done)
+	log.Printf("cache: opened networked cache")
+	return ps, nil
+}
+
+func (ps *postgresStore) Get(key string) ([]byte, bool, error) {
+	var value []byte
+	err := ps.db.QueryRow("SELECT value FROM cache WHERE key=$1 AND (expires IS NULL OR expires > now())", key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (ps *postgresStore) Set(key string, value []byte, ttl time.Duration) error {
+	// expiry uses the database clock, to be consistent between replicas
+	_, err := ps.db.Exec(`INSERT INTO cache (key, value, expires)
+		VALUES ($1, $2, CASE WHEN $3::float8 > 0 THEN now() + make_interval(secs => $3::float8) END)
+		ON CONFLICT (key) DO UPDATE SET value=EXCLUDED.value, expires=EXCLUDED.expires`, key, value, ttl.Seconds())
+	return err
+}
+
+func (ps *postgresStore) Delete(key string) error {
+	_, err := ps.db.Exec("DELETE FROM cache WHERE key=$1", key)
+	return err
+}
+
+func (ps *postgresStore) Flush(prefix string) error {
+	_, err := ps.db.Exec("DELETE FROM cache WHERE key LIKE $1", likePrefix(prefix))
+	return err
+}
+
+func (ps *postgresStore) Len(prefix string) (int, error) {
+	var n int
+	err := ps.db.QueryRow("SELECT count(*) FROM cache WHERE key LIKE $1 AND (expires IS NULL OR expires > now())", likePrefix(prefix)).Scan(&n)
+	return n, err
+}
+
+func (ps *postgresStore) Keys(prefix string) ([]string, error) {
+	rows, err := ps.db.Query("SELECT key FROM cache WHERE key LIKE $1 AND (expires IS NULL OR expires > now()) ORDER BY key", likePrefix(prefix))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// purge removes expired entries
+func (ps *postgresStore) purge() error {
+	_, err := ps.db.Exec("DELETE FROM cache WHERE expires <= now()")
+	return err
+}
+
+func (ps *postgresStore) Close() error {
+	ps.closeOnce.Do(func() { close(ps.done) })
+	return ps.db.Close()
+}
+
+// likePrefix returns a LIKE pattern matching strings beginning with the prefix
+func likePrefix(prefix string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix) + "%"
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// adminCmd represents the admin command
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Inspect and control a running server",
+	Long: `Inspect and control a running server using its administrative API.
+The service user must be registered as an administrator on the server (see 'serve --admin').
+
+For example:
+concierge admin status --addr localhost:9090 --user ops --password secret
+concierge admin flush empi --addr localhost:9090 --user ops --password secret
+concierge admin caches --addr localhost:9090 --user ops --private-key ops.key`,
+}
+
+var adminStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show server version and authentication token status",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		runAdmin(func(ctx context.Context, client apiv1.AdminClient) (proto.Message, error) {
+			return client.GetServerStatus(ctx, &apiv1.ServerStatusRequest{})
+		})
+	},
+}
+
+var adminProvidersCmd = &cobra.Command{
+	Use:   "providers",
+	Short: "List registered providers and their health",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		runAdmin(func(ctx context.Context, client apiv1.AdminClient) (proto.Message, error) {
+			return client.ListProviders(ctx, &apiv1.ListProvidersRequest{})
+		})
+	},
+}
+
+var adminRegistrationsCmd = &cobra.Command{
+	Use:   "registrations",
+	Short: "List registered identifier systems, resolvers and mappers",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		runAdmin(func(ctx context.Context, client apiv1.AdminClient) (proto.Message, error) {
+			return client.ListRegistrations(ctx, &apiv1.ListRegistrationsRequest{})
+		})
+	},
+}
+
+var adminCachesCmd = &cobra.Command{
+	Use:   "caches",
+	Short: "List registered caches",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		runAdmin(func(ctx context.Context, client apiv1.AdminClient) (proto.Message, error) {
+			return client.ListCaches(ctx, &apiv1.ListCachesRequest{})
+		})
+	},
+}
+
+var adminUsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show today's usage by client and by rate-limited backend",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		runAdmin(func(ctx context.Context, client apiv1.AdminClient) (proto.Message, error) {
+			return client.ListUsage(ctx, &apiv1.ListUsageRequest{})
+		})
+	},
+}
+
+var adminFlushCmd = &cobra.Command{
+	Use:   "flush <cache>",
+	Short: "Flush the named cache",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runAdmin(func(ctx context.Context, client apiv1.AdminClient) (proto.Message, error) {
+			return client.FlushCache(ctx, &apiv1.FlushCacheRequest{Name: args[0]})
+		})
+	},
+}
+
+// runAdmin logs in to the configured server as a service user and runs the specified administrative command
+func runAdmin(f func(ctx context.Context, client apiv1.AdminClient) (proto.Message, error)) {
+	conn, ctx, err := dialAuthenticated()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+	result, err := f(ctx, apiv1.NewAdminClient(conn))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(protojson.MarshalOptions{Multiline: true, Indent: "  ", UseProtoNames: true}.Format(result))
+}
+
+// dialAuthenticated connects to the configured server and logs in as a service user, returning a context
+// containing the authentication token for subsequent calls. If a client certificate is configured, this
+// is presented to the server instead of logging in.
+func dialAuthenticated() (*grpc.ClientConn, context.Context, error) {
+	opts := []grpc.DialOption{grpc.WithBlock(), grpc.WithTimeout(10 * time.Second)}
+	clientCert := viper.GetString("client-cert")
+	if ca := viper.GetString("client-ca-cert"); ca != "" {
+		tlsConfig := new(tls.Config)
+		b, err := ioutil.ReadFile(ca)
+		if err != nil {
+			return nil, nil, err
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(b) {
+			return nil, nil, fmt.Errorf("no valid certificates in '%s'", ca)
+		}
+		if clientCert != "" {
+			cert, err := tls.LoadX509KeyPair(clientCert, viper.GetString("client-key"))
+			if err != nil {
+				return nil, nil, err
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else if clientCert != "" {
+		return nil, nil, fmt.Errorf("a client certificate can only be used with TLS (--client-ca-cert)")
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	conn, err := grpc.Dial(viper.GetString("addr"), opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not connect to '%s': %w", viper.GetString("addr"), err)
+	}
+	if clientCert != "" { // authenticated by certificate, so no need to login
+		return conn, context.Background(), nil
+	}
+	password := viper.GetString("password")
+	if keyFile := viper.GetString("private-key"); keyFile != "" {
+		if password, err = signedAssertion(viper.GetString("user"), keyFile); err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+	}
+	response, err := apiv1.NewAuthenticatorClient(conn).Login(context.Background(), &apiv1.LoginRequest{
+		User:     &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: viper.GetString("user")},
+		Password: password,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("could not login: %w", err)
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+response.GetToken())
+	return conn, ctx, nil
+}
+
+// signedAssertion creates a client assertion for the service user, signed using the private key in the file specified
+func signedAssertion(username string, keyFile string) (string, error) {
+	b, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return "", err
+	}
+	key, err := server.ParsePrivateKey(b)
+	if err != nil {
+		return "", fmt.Errorf("could not parse private key %s: %w", keyFile, err)
+	}
+	return server.NewJWTAssertion(username, viper.GetString("audience"), key)
+}
+
+func init() {
+	rootCmd.AddCommand(adminCmd)
+	adminCmd.AddCommand(adminStatusCmd)
+	adminCmd.AddCommand(adminProvidersCmd)
+	adminCmd.AddCommand(adminRegistrationsCmd)
+	adminCmd.AddCommand(adminCachesCmd)
+	adminCmd.AddCommand(adminUsageCmd)
+	adminCmd.AddCommand(adminFlushCmd)
+
+	adminCmd.PersistentFlags().String("addr", "localhost:9090", "gRPC address of server")
+	viper.BindPFlag("addr", adminCmd.PersistentFlags().Lookup("addr"))
+	adminCmd.PersistentFlags().String("user", "", "Service user name")
+	viper.BindPFlag("user", adminCmd.PersistentFlags().Lookup("user"))
+	adminCmd.PersistentFlags().String("password", "", "Service user password")
+	viper.BindPFlag("password", adminCmd.PersistentFlags().Lookup("password"))
+	adminCmd.PersistentFlags().String("private-key", "", "Private key used to sign a JWT assertion, instead of a password")
+	viper.BindPFlag("private-key", adminCmd.PersistentFlags().Lookup("private-key"))
+	adminCmd.PersistentFlags().String("audience", "concierge", "Audience ('aud') for a signed JWT assertion")
+	viper.BindPFlag("audience", adminCmd.PersistentFlags().Lookup("audience"))
+	adminCmd.PersistentFlags().String("client-ca-cert", "", "Certificate used to verify the server, if using TLS")
+	viper.BindPFlag("client-ca-cert", adminCmd.PersistentFlags().Lookup("client-ca-cert"))
+	adminCmd.PersistentFlags().String("client-cert", "", "Client certificate to present to the server, instead of logging in")
+	viper.BindPFlag("client-cert", adminCmd.PersistentFlags().Lookup("client-cert"))
+	adminCmd.PersistentFlags().String("client-key", "", "Key for the client certificate")
+	viper.BindPFlag("client-key", adminCmd.PersistentFlags().Lookup("client-key"))
+}
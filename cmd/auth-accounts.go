@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wardle/concierge/server"
+)
+
+// authCreateAccountCmd creates a new service account in the authentication database
+var authCreateAccountCmd = &cobra.Command{
+	Use:   "create <username>",
+	Short: "Create a service account",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		expires, err := parseExpiry(cmd.Flag("expires").Value.String())
+		if err != nil {
+			log.Fatal(err)
+		}
+		dba := openAuthDatabase()
+		defer dba.Close()
+		password, err := dba.CreateServiceAccount(args[0], cmd.Flag("description").Value.String(), expires)
+		if err != nil {
+			log.Fatalf("could not create service account: %s", err)
+		}
+		fmt.Printf("username : %s\n", args[0])
+		fmt.Printf("password : %s\n", password)
+	},
+}
+
+// authListAccountsCmd lists service accounts in the authentication database
+var authListAccountsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List service accounts",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		dba := openAuthDatabase()
+		defer dba.Close()
+		accounts, err := dba.ServiceAccounts()
+		if err != nil {
+			log.Fatal(err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "USERNAME\tENABLED\tCREATED\tEXPIRES\tLAST LOGIN\tDESCRIPTION")
+		now := time.Now()
+		for _, sa := range accounts {
+			expires := formatTime(sa.Expires, "never")
+			if sa.Expired(now) {
+				expires += " (expired)"
+			}
+			fmt.Fprintf(w, "%s\t%t\t%s\t%s\t%s\t%s\n", sa.Username, sa.Enabled, formatTime(sa.Created, ""), expires, formatTime(sa.LastLogin, "never"), sa.Description)
+		}
+		w.Flush()
+	},
+}
+
+// authEnableAccountCmd enables a service account
+var authEnableAccountCmd = &cobra.Command{
+	Use:   "enable <username>",
+	Short: "Enable a service account",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dba := openAuthDatabase()
+		defer dba.Close()
+		if err := dba.SetEnabled(args[0], true); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// authDisableAccountCmd disables a service account
+var authDisableAccountCmd = &cobra.Command{
+	Use:   "disable <username>",
+	Short: "Disable a service account",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dba := openAuthDatabase()
+		defer dba.Close()
+		if err := dba.SetEnabled(args[0], false); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// authExpireAccountCmd sets the expiry date of a service account
+var authExpireAccountCmd = &cobra.Command{
+	Use:   "expire <username> <YYYY-MM-DD|never>",
+	Short: "Set the expiry date of a service account",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		expires, err := parseExpiry(args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		dba := openAuthDatabase()
+		defer dba.Close()
+		if err := dba.SetExpiry(args[0], expires); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// authRotateAccountCmd generates a new password for a service account
+var authRotateAccountCmd = &cobra.Command{
+	Use:   "rotate <username>",
+	Short: "Generate a new password for a service account",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dba := openAuthDatabase()
+		defer dba.Close()
+		password, err := dba.RotatePassword(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("username : %s\n", args[0])
+		fmt.Printf("password : %s\n", password)
+	},
+}
+
+// authMigrateCmd migrates the authentication database schema to the latest version
+var authMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Create or migrate the authentication database schema",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		dba := openAuthDatabase() // migration is performed on opening
+		dba.Close()
+	},
+}
+
+// openAuthDatabase opens the authentication database specified by --auth-db, migrating if necessary
+func openAuthDatabase() *server.DatabaseAuthProvider {
+	connStr := viper.GetString("auth-db")
+	if connStr == "" {
+		log.Fatal("you must specify an authentication database using --auth-db")
+	}
+	dba, err := server.OpenDatabaseAuthProvider(connStr)
+	if err != nil {
+		log.Fatalf("could not open authentication database: %s", err)
+	}
+	return dba
+}
+
+// parseExpiry parses an expiry date in the form YYYY-MM-DD, with "never" or "" meaning no expiry
+func parseExpiry(s string) (time.Time, error) {
+	if s == "" || s == "never" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expiry date '%s': use YYYY-MM-DD or 'never'", s)
+	}
+	return t, nil
+}
+
+func formatTime(t time.Time, zero string) string {
+	if t.IsZero() {
+		return zero
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func init() {
+	authCmd.AddCommand(authCreateAccountCmd)
+	authCmd.AddCommand(authListAccountsCmd)
+	authCmd.AddCommand(authEnableAccountCmd)
+	authCmd.AddCommand(authDisableAccountCmd)
+	authCmd.AddCommand(authExpireAccountCmd)
+	authCmd.AddCommand(authRotateAccountCmd)
+	authCmd.AddCommand(authMigrateCmd)
+
+	authCreateAccountCmd.Flags().String("expires", "", "Expiry date of the account (YYYY-MM-DD), default never")
+	authCreateAccountCmd.Flags().String("description", "", "Description of the account")
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/wardle/concierge/server"
+)
+
+// authAPIKeyCmd manages API keys
+var authAPIKeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Short: "Manage API keys for restricted read-only access",
+	Long: `Manage API keys for restricted read-only access to identifier resolution and mapping.
+Clients send the key in the 'x-api-key' header (or gRPC metadata), and can only resolve or map
+identifiers in the namespaces for which the key is scoped.
+
+Keys may be stored in the authentication database (--auth-db), or defined in the configuration
+file using the hash from 'apikey generate'.`,
+}
+
+// authGenerateAPIKeyCmd generates a key and hash for use in a configuration file
+var authGenerateAPIKeyCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a random API key and its hash, for use in a configuration file",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, hash, err := server.GenerateAPIKey()
+		if err != nil {
+			log.Fatalf("could not generate api key: %s", err)
+		}
+		fmt.Printf("key  : %s\n", key)
+		fmt.Printf("hash : %s\n", hash)
+	},
+}
+
+// authCreateAPIKeyCmd creates an API key in the authentication database
+var authCreateAPIKeyCmd = &cobra.Command{
+	Use:   "create <name> <namespace-uri>...",
+	Short: "Create an API key permitting access to the specified namespaces",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		dba := openAuthDatabase()
+		defer dba.Close()
+		key, err := dba.CreateAPIKey(args[0], args[1:])
+		if err != nil {
+			log.Fatalf("could not create api key: %s", err)
+		}
+		fmt.Printf("name : %s\n", args[0])
+		fmt.Printf("key  : %s\n", key)
+	},
+}
+
+// authListAPIKeysCmd lists API keys in the authentication database
+var authListAPIKeysCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List API keys",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		dba := openAuthDatabase()
+		defer dba.Close()
+		keys, err := dba.APIKeys()
+		if err != nil {
+			log.Fatal(err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tSCOPES")
+		for _, key := range keys {
+			fmt.Fprintf(w, "%s\t%s\n", key.Name, strings.Join(key.Scopes, " "))
+		}
+		w.Flush()
+	},
+}
+
+// authRevokeAPIKeyCmd deletes an API key from the authentication database
+var authRevokeAPIKeyCmd = &cobra.Command{
+	Use:   "revoke <name>",
+	Short: "Revoke an API key",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dba := openAuthDatabase()
+		defer dba.Close()
+		if err := dba.RevokeAPIKey(args[0]); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authAPIKeyCmd)
+	authAPIKeyCmd.AddCommand(authGenerateAPIKeyCmd)
+	authAPIKeyCmd.AddCommand(authCreateAPIKeyCmd)
+	authAPIKeyCmd.AddCommand(authListAPIKeysCmd)
+	authAPIKeyCmd.AddCommand(authRevokeAPIKeyCmd)
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wardle/concierge/server"
+)
+
+// breakGlassCmd groups commands for reviewing access to restricted records
+var breakGlassCmd = &cobra.Command{
+	Use:   "breakglass",
+	Short: "Review access to restricted records",
+}
+
+// breakGlassReportCmd reports access to restricted records from the break-glass log, for information governance review
+var breakGlassReportCmd = &cobra.Command{
+	Use:   "report <break-glass log>",
+	Short: "Report access to restricted records",
+	Long: `Report access to restricted records, and the justification given, from the break-glass log.
+Dates are in the form YYYY-MM-DD; the report includes access on the 'to' date.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		from, err := parseReportDate(cmd.Flag("from").Value.String(), 0)
+		if err != nil {
+			log.Fatal(err)
+		}
+		to, err := parseReportDate(cmd.Flag("to").Value.String(), 1)
+		if err != nil {
+			log.Fatal(err)
+		}
+		user := cmd.Flag("user").Value.String()
+		f, err := os.Open(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME\tUSER\tRECORD\tREASON\tJUSTIFICATION")
+		count := 0
+		byUser := make(map[string]int)
+		err = server.ReadBreakGlassLog(f, func(entry *server.BreakGlassEntry) error {
+			if (!from.IsZero() && entry.Time.Before(from)) || (!to.IsZero() && !entry.Time.Before(to)) {
+				return nil
+			}
+			if user != "" && entry.User != user && !hasValue(entry.User, user) {
+				return nil
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s|%s\t%s\t%s\n", formatTime(entry.Time, ""), entry.User, entry.System, entry.Value, entry.Reason, entry.Justification)
+			count++
+			byUser[entry.User]++
+			return nil
+		})
+		w.Flush()
+		if err != nil {
+			log.Fatalf("failed to read break-glass log: %s", err)
+		}
+		users := make([]string, 0, len(byUser))
+		for u := range byUser {
+			users = append(users, u)
+		}
+		sort.Slice(users, func(i, j int) bool {
+			if byUser[users[i]] != byUser[users[j]] {
+				return byUser[users[i]] > byUser[users[j]]
+			}
+			return users[i] < users[j]
+		})
+		fmt.Printf("\n%d accesses by %d users\n", count, len(users))
+		for _, u := range users {
+			fmt.Printf("  %6d  %s\n", byUser[u], u)
+		}
+	},
+}
+
+// parseReportDate parses a date in the form YYYY-MM-DD, adding the specified number of days, or returns the
+// zero time if the date is empty
+func parseReportDate(s string, days int) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date '%s': use YYYY-MM-DD", s)
+	}
+	return t.AddDate(0, 0, days), nil
+}
+
+// hasValue returns whether a subject, as system|value, has the specified value
+func hasValue(subject string, value string) bool {
+	return len(subject) > len(value) && subject[len(subject)-len(value)-1:] == "|"+value
+}
+
+func init() {
+	rootCmd.AddCommand(breakGlassCmd)
+	breakGlassCmd.AddCommand(breakGlassReportCmd)
+	breakGlassReportCmd.Flags().String("from", "", "Report access from this date (YYYY-MM-DD)")
+	breakGlassReportCmd.Flags().String("to", "", "Report access up to and including this date (YYYY-MM-DD)")
+	breakGlassReportCmd.Flags().String("user", "", "Report access by this user only (username, or system|value)")
+}
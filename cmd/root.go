@@ -76,6 +76,8 @@ func init() {
 
 	rootCmd.PersistentFlags().Bool("fake", false, "Run with fake results")
 	viper.BindPFlag("fake", rootCmd.PersistentFlags().Lookup("fake"))
+	rootCmd.PersistentFlags().String("fake-data", "", "File of synthetic patients, practitioners, clinics and documents (JSON or YAML) to use with --fake")
+	viper.BindPFlag("fake-data", rootCmd.PersistentFlags().Lookup("fake-data"))
 
 	// empi configuration
 	rootCmd.PersistentFlags().String("empi-url", "", "URL for EMPI endpoint")
@@ -102,6 +104,10 @@ func init() {
 	// SNOMED terminology server integration
 	rootCmd.PersistentFlags().String("terminology-addr", "", "gRPC address of terminology server (e.g. localhost:8081")
 	viper.BindPFlag("terminology-addr", rootCmd.PersistentFlags().Lookup("terminology-addr"))
+
+	// database authentication server options, used by both 'serve' and 'auth' commands
+	rootCmd.PersistentFlags().String("auth-db", "", "Auth database connection string (e.g. 'dbname=concierge sslmode=disable'")
+	viper.BindPFlag("auth-db", rootCmd.PersistentFlags().Lookup("auth-db"))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -1,18 +1,32 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"log"
+	"net"
+	"strings"
 	"time"
 
-	"github.com/patrickmn/go-cache"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/wardle/concierge/adt"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/cache"
+	"github.com/wardle/concierge/fake"
 	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/matching"
+	"github.com/wardle/concierge/patients"
+	"github.com/wardle/concierge/relationship"
+	"github.com/wardle/concierge/resilience"
 	"github.com/wardle/concierge/server"
+	"github.com/wardle/concierge/supersession"
 	"github.com/wardle/concierge/terminology"
 	"github.com/wardle/concierge/wales/cav"
 	"github.com/wardle/concierge/wales/empi"
 	"github.com/wardle/concierge/wales/nadex"
+	"gopkg.in/jcmturner/gokrb5.v7/keytab"
 )
 
 // serveCmd represents the serve command
@@ -23,33 +37,72 @@ var serveCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		log.Printf("========== starting concierge v%s ==========", rootCmd.Version)
 		my := createServers()
+		my.sv.RegisterReloader("config", my.reloadConfig)
+
+		if my.adt != nil {
+			lis, err := net.Listen("tcp", viper.GetString("adt-listen"))
+			if err != nil {
+				log.Fatalf("cmd: failed to listen for ADT messages: %s", err)
+			}
+			go func() {
+				if err := my.adt.Serve(lis); err != nil {
+					log.Fatalf("cmd: failed to receive ADT messages: %s", err)
+				}
+			}()
+		}
+
+		// watch configuration only once it has been read at startup, as reloads re-read the configuration
+		if filename := viper.ConfigFileUsed(); filename != "" && viper.GetBool("watch-config") {
+			if err := watchConfig(filename, func() { my.sv.Reload() }); err != nil {
+				log.Fatalf("cmd: failed to watch configuration file: %s", err)
+			}
+		}
 
 		// start server
-		log.Printf("cmd: starting server: rpc-port:%d http-port:%d", my.sv.Options.RPCPort, my.sv.Options.RESTPort)
+		if my.sv.Options.SinglePort {
+			log.Printf("cmd: starting server: rpc-port:%d (serving gRPC and http)", my.sv.Options.RPCPort)
+		} else {
+			log.Printf("cmd: starting server: rpc-port:%d http-port:%d", my.sv.Options.RPCPort, my.sv.Options.RESTPort)
+		}
 		if err := my.sv.RunServer(); err != nil {
 			log.Fatal(err)
 		}
 		my.sv.Close()
+		if err := my.cacheStore.Close(); err != nil {
+			log.Printf("cmd: failed to close cache: %s", err)
+		}
+		if my.breakGlass != nil {
+			my.breakGlass.Close()
+		}
 	},
 }
 
 type myServer struct {
 	sv *server.Server // the main gRPC/HTTP server
 	// services
-	identifiers *identifiers.Server // an identifier service
-	nadex       *nadex.App
-	empi        *empi.App
-	cav         *cav.PMSService
-	term        *terminology.Terminology
+	identifiers   *identifiers.Server // an identifier service
+	patients      *patients.Directory // a patient directory service
+	nadex         *nadex.App
+	empi          *empi.App
+	cav           *cav.PMSService
+	term          *terminology.Terminology
+	adt           *adt.Feed             // HL7 v2 ADT feed, or nil
+	cacheStore    cache.Store           // storage for cached results, shared by services
+	breakGlass    *server.BreakGlassLog // log of access to restricted records, or nil
+	supersessions *supersession.Store   // identifiers superseded by merges, or nil
 }
 
 // createServers creates a gRPC/HTTP server and plugs-in modular providers based on runtime configuration
 func createServers() *myServer {
 	sv := server.New(server.Options{
-		RESTPort: viper.GetInt("port-http"),
-		RPCPort:  viper.GetInt("port-grpc"),
-		CertFile: viper.GetString("cert"),
-		KeyFile:  viper.GetString("key"),
+		RESTPort:   viper.GetInt("port-http"),
+		RPCPort:    viper.GetInt("port-grpc"),
+		SinglePort: viper.GetBool("single-port"),
+		UnixSocket: viper.GetString("unix-socket"),
+		CertFile:   viper.GetString("cert"),
+		KeyFile:    viper.GetString("key"),
+
+		ClientCAFile: viper.GetString("tls-client-ca"),
 	})
 	my := &myServer{
 		sv: sv,
@@ -57,24 +110,75 @@ func createServers() *myServer {
 	// generic servers: these are high-level and distinct from underlying implementations
 	my.identifiers = &identifiers.Server{}
 	my.sv.Register("identifier", my.identifiers)
+	my.patients = patients.NewDirectory() // patients, from the EMPI or PAS by system of identifier
+	my.sv.Register("patient", my.patients)
 
 	// specific servers: these provide an abstraction over a specific back-end service.
 	// in the future, these endpoints will be deprecated in favour of complete abstraction,
 	// but we will still need to support identifier resolution and mapping using this mechanism
-	my.nadex = nadexServer()
+	fakeData := loadFakeData()
+	my.nadex = nadexServer(fakeData)
 	my.sv.Register("nadex", my.nadex)
 	identifiers.RegisterResolver(identifiers.CymruUserID, my.nadex.ResolvePractitioner)
 
-	my.empi = walesEmpiServer()
-	//my.empi.Register("wales-empi", ep) 		-- temporarily unnecessary as can use identifier lookup instead
-	identifiers.RegisterResolver(identifiers.NHSNumber, my.empi.ResolveIdentifier)
-	identifiers.RegisterResolver(identifiers.AneurinBevanCRN, my.empi.ResolveIdentifier)
-	identifiers.RegisterResolver(identifiers.CwmTafCRN, my.empi.ResolveIdentifier)
-	identifiers.RegisterResolver(identifiers.SwanseaBayCRN, my.empi.ResolveIdentifier)
+	my.cacheStore = openCacheStore()
+	if viper.GetBool("supersessions") {
+		uri := viper.GetString("cache-store")
+		if uri == "" || uri == "memory" {
+			log.Printf("cmd: warning: superseded identifiers are held in memory, and will be lost on restart: use a persistent cache store (--cache-store)")
+		}
+		var err error
+		if my.supersessions, err = supersession.NewStore(my.cacheStore, cacheKey()); err != nil {
+			log.Fatalf("cmd: failed to open supersession store: %s", err)
+		}
+		identifiers.RegisterSupersessionStore(my.supersessions)
+	}
+	if err := configureEmpiAuthorities(); err != nil {
+		log.Fatalf("cmd: %s", err)
+	}
+	my.empi = walesEmpiServer(my.cacheStore, fakeData)
+	my.sv.RegisterCache("empi", my.empi)
+	my.patients.RegisterSearcher(my.empi)
+	my.patients.RegisterProvider("empi", my.empi, empiSystems()...)
 
 	// Cardiff and Vale PMS
 	my.cav = cav.NewPMSService(viper.GetString("cav-pms-username"), viper.GetString("cav-pms-password"), 10*time.Second, viper.GetBool("fake"))
-	identifiers.RegisterResolver(identifiers.CardiffAndValeCRN, my.cav.ResolveIdentifier)
+	my.cav.SetCacheStore(my.cacheStore, cacheKey())
+	my.cav.SetStaleMaxAge(viper.GetDuration("stale-max-age"))
+	my.cav.SetFakeData(fakeData)
+	my.patients.RegisterProvider("cav", my.cav, identifiers.CardiffAndValeCRN)
+
+	// reconciliation of demographics between the EMPI and health board patient administration systems
+	reconciler := patients.NewReconciler(my.empi)
+	reconciler.RegisterPAS(identifiers.CardiffAndValeCRN, my.cav)
+	my.sv.Register("reconcile", reconciler)
+
+	// probabilistic matching of patients
+	matcher := matching.NewMatcher()
+	matcher.MatchThreshold = viper.GetFloat64("match-threshold")
+	matcher.PossibleMatchThreshold = viper.GetFloat64("possible-match-threshold")
+	if matcher.PossibleMatchThreshold > matcher.MatchThreshold {
+		log.Fatalf("cmd: possible match threshold (%v) must not exceed match threshold (%v)", matcher.PossibleMatchThreshold, matcher.MatchThreshold)
+	}
+	my.sv.Register("matching", matcher)
+
+	// HL7 v2 ADT feeds from patient administration systems and the EMPI
+	if viper.GetString("adt-listen") != "" {
+		my.adt = adt.NewFeed(empi.ParseEvent, viper.GetBool("adt-refresh"))
+		sources := viper.GetStringSlice("adt-allow")
+		if len(sources) == 0 {
+			log.Fatalf("cmd: no sources allowed to send ADT messages: specify the addresses of the feeds using --adt-allow")
+		}
+		if err := my.adt.AllowSources(sources...); err != nil {
+			log.Fatalf("cmd: %s", err)
+		}
+		my.adt.RegisterCache("empi", my.empi)
+		my.adt.RegisterCache("cav", my.cav)
+		if my.supersessions != nil {
+			my.adt.RegisterMergeRecorder(identifiers.Supersede)
+		}
+		my.sv.Register("adt", my.adt)
+	}
 
 	// terminology server
 	if addr := viper.GetString("terminology-addr"); addr != "" {
@@ -106,48 +210,437 @@ func createServers() *myServer {
 			log.Fatalf("cmd: failed to start authentication server: %s", err)
 		}
 		my.sv.RegisterAuthenticator(auth)
+		var serviceAuth server.AuthProvider
+		var serviceAuthName string
 		if db := viper.GetString("auth-db"); db != "" {
 			ap, err := server.NewDatabaseAuthProvider(db)
 			if err != nil {
 				log.Fatal(err)
 			}
 			log.Printf("cmd: using postgresql ('%s') for service user authentication", db)
-			auth.RegisterAuthProvider(identifiers.ConciergeServiceUser, "postgresql", ap, true)
+			serviceAuth, serviceAuthName = ap, "postgresql"
+			auth.RegisterAPIKeyStore("postgresql", ap)
 		} else if hash := viper.GetString("auth-secret"); hash != "" {
 			log.Printf("cmd: using explicitly defined single secret for service user authentication")
-			auth.RegisterAuthProvider(identifiers.ConciergeServiceUser, "single", server.NewSingleAuthProvider(hash), true)
+			serviceAuth, serviceAuthName = server.NewSingleAuthProvider(hash), "single"
+		}
+		if dir := viper.GetString("auth-jwt-keys"); dir != "" {
+			ap := server.NewJWTAssertionAuthProvider(viper.GetString("auth-jwt-audience"), serviceAuth)
+			if err := ap.LoadPublicKeys(dir); err != nil {
+				log.Fatal(err)
+			}
+			my.sv.RegisterReloader("auth-jwt-keys", func() error { return ap.LoadPublicKeys(dir) })
+			serviceAuth, serviceAuthName = ap, strings.TrimSuffix("private_key_jwt,"+serviceAuthName, ",")
+		}
+		if serviceAuth == nil {
+			log.Fatalf("cmd: you must specify a authentication provider (--auth-db, --auth-secret or --auth-jwt-keys) or specify --no-auth explicitly")
+		}
+		auth.RegisterAuthProvider(identifiers.ConciergeServiceUser, serviceAuthName, serviceAuth, true)
+		if issuer := viper.GetString("oidc-issuer"); issuer != "" {
+			// user ID tokens from an OpenID Connect provider, with fallback to NADEX passwords unless disabled
+			namespace := viper.GetString("oidc-namespace")
+			var fallback server.AuthProvider
+			name := "oidc"
+			if namespace == identifiers.CymruUserID && !viper.GetBool("oidc-only") {
+				fallback, name = my.nadex, "oidc,nadex"
+			}
+			ap := server.NewOIDCAuthProvider(issuer, viper.GetString("oidc-client-id"), viper.GetString("oidc-claim"), viper.GetStringSlice("oidc-domain"), fallback)
+			auth.RegisterAuthProvider(namespace, name, ap, false)
+			if namespace != identifiers.CymruUserID {
+				auth.RegisterAuthProvider(identifiers.CymruUserID, "nadex", my.nadex, false)
+			}
 		} else {
-			log.Fatalf("cmd: you must specify a authentication provider (--auth-db or --auth-secret) or specify --no-auth explicitly")
+			auth.RegisterAuthProvider(identifiers.CymruUserID, "nadex", my.nadex, false)
+		}
+		if keys := apiKeysFromConfig(); len(keys) > 0 {
+			auth.RegisterAPIKeyStore("configuration", server.NewStaticAPIKeyStore(keys))
+		}
+		if kt := viper.GetString("krb5-keytab"); kt != "" {
+			// kerberos single sign-on for users of the NHS Wales domain
+			keytab, err := keytab.Load(kt)
+			if err != nil {
+				log.Fatalf("cmd: failed to load keytab: %s", err)
+			}
+			auth.RegisterNegotiate(keytab, viper.GetString("krb5-service-principal"), viper.GetString("krb5-realm"), identifiers.CymruUserID)
 		}
-		auth.RegisterAuthProvider(identifiers.CymruUserID, "nadex", my.nadex, false)
 		my.sv.Register("auth", auth)
+		for _, mapping := range viper.GetStringSlice("client-cert-account") {
+			name, account := mapping, mapping
+			if i := strings.Index(mapping, "="); i >= 0 {
+				name, account = mapping[:i], mapping[i+1:]
+			}
+			auth.RegisterClientCertificate(name, parseAccount(account))
+		}
+		for _, admin := range viper.GetStringSlice("admin") {
+			auth.RegisterAdministrator(parseAccount(admin))
+		}
+		my.sv.Register("admin", server.NewAdmin(my.sv, rootCmd.Version))
 	}
+	configureRateLimits(my.sv)
+	configureResilience()
+	my.breakGlass = configureBreakGlass()
+	my.breakGlass = configureRelationships(my.cav, my.breakGlass)
 	return my
 }
 
-func nadexServer() *nadex.App {
+// configureRelationships registers a policy requiring a legitimate relationship with a patient before resolving
+// their identifiers, from a local file of relationships and from clinic bookings in the Cardiff and Vale PMS for
+// the clinics in which staff work, from the configuration file, e.g.
+//   clinic-staff:
+//     - user: ma090906
+//       clinics: [NEUR1, NEUR2]
+// Access without a relationship is recorded in the break-glass log, which is opened if not already open.
+func configureRelationships(pms *cav.PMSService, bgl *server.BreakGlassLog) *server.BreakGlassLog {
+	var mode identifiers.RelationshipMode
+	switch m := viper.GetString("relationship-mode"); m {
+	case "", "off":
+		return bgl
+	case "deny":
+		mode = identifiers.DenyWithoutRelationship
+	case "flag":
+		mode = identifiers.FlagWithoutRelationship
+	default:
+		log.Fatalf("cmd: invalid relationship mode '%s': use off, deny or flag", m)
+	}
+	if bgl == nil {
+		path := viper.GetString("break-glass-log")
+		if path == "" {
+			log.Fatalf("cmd: you must specify a break-glass log (--break-glass-log) to check relationships with patients")
+		}
+		var err error
+		if bgl, err = server.OpenBreakGlassLog(path); err != nil {
+			log.Fatalf("cmd: failed to open break-glass log: %s", err)
+		}
+		identifiers.RegisterBreakGlassRecorder(bgl.Record)
+	}
+	var checkers []relationship.Checker
+	if filename := viper.GetString("relationships"); filename != "" {
+		store := relationship.NewStore()
+		if err := store.LoadFile(filename); err != nil {
+			log.Fatalf("cmd: failed to read relationships: %s", err)
+		}
+		checkers = append(checkers, store)
+	}
+	var staff []struct {
+		User    string
+		Clinics []string
+	}
+	if err := viper.UnmarshalKey("clinic-staff", &staff); err != nil {
+		log.Fatalf("cmd: invalid clinic-staff configuration: %s", err)
+	}
+	if len(staff) > 0 {
+		bookings := relationship.NewClinicBookings(pms.PatientsForClinics, viper.GetInt("relationship-clinic-days"))
+		for _, s := range staff {
+			if s.User == "" {
+				log.Fatalf("cmd: invalid clinic-staff configuration: missing user")
+			}
+			for _, clinic := range s.Clinics {
+				bookings.AddClinic(parseStaffUser(s.User), &apiv1.Identifier{System: identifiers.CardiffAndValeClinicCode, Value: clinic})
+			}
+		}
+		checkers = append(checkers, bookings)
+	}
+	identifiers.RegisterRelationshipPolicy(mode, relationship.Policy(checkers...), identifiers.NHSNumber, identifiers.CardiffAndValeCRN)
+	return bgl
+}
+
+// parseStaffUser parses a member of staff as a NHS Wales username, or as system|value
+func parseStaffUser(user string) *apiv1.Identifier {
+	if i := strings.LastIndex(user, "|"); i > 0 {
+		return &apiv1.Identifier{System: user[:i], Value: user[i+1:]}
+	}
+	return &apiv1.Identifier{System: identifiers.CymruUserID, Value: user}
+}
+
+// configureBreakGlass registers access policies for restricted records, which require callers to give a
+// justification, recorded in the break-glass log.
+func configureBreakGlass() *server.BreakGlassLog {
+	namespaces := viper.GetStringSlice("break-glass-namespace")
+	restricted := viper.GetString("restricted-records")
+	if len(namespaces) == 0 && restricted == "" {
+		return nil
+	}
+	path := viper.GetString("break-glass-log")
+	if path == "" {
+		log.Fatalf("cmd: you must specify a break-glass log (--break-glass-log) to restrict access to records")
+	}
+	bgl, err := server.OpenBreakGlassLog(path)
+	if err != nil {
+		log.Fatalf("cmd: failed to open break-glass log: %s", err)
+	}
+	identifiers.RegisterBreakGlassRecorder(bgl.Record)
+	if len(namespaces) > 0 {
+		identifiers.RegisterAccessPolicy("namespaces", identifiers.NamespacePolicy(namespaces...))
+	}
+	if restricted != "" {
+		ids, err := readIdentifiers(restricted)
+		if err != nil {
+			log.Fatalf("cmd: failed to read restricted records: %s", err)
+		}
+		identifiers.RegisterAccessPolicy("restricted-records", identifiers.RecordPolicy(ids...))
+	}
+	return bgl
+}
+
+// readIdentifiers reads identifiers from a file, one per line as system|value, ignoring blank lines and comments (#)
+func readIdentifiers(filename string) ([]*apiv1.Identifier, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var result []*apiv1.Identifier
+	for i, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		j := strings.LastIndex(line, "|")
+		if j <= 0 || j == len(line)-1 {
+			return nil, fmt.Errorf("%s:%d: invalid identifier '%s': use system|value", filename, i+1, line)
+		}
+		result = append(result, &apiv1.Identifier{System: line[:j], Value: line[j+1:]})
+	}
+	return result, nil
+}
+
+// configureResilience sets the default retry and circuit breaker policy for outbound backends from flags, and
+// policies for specific backends from the configuration file, with unspecified settings taken from the default:
+//   backends:
+//     - name: empi
+//       retry-attempts: 2
+//       breaker-timeout: 1m
+func configureResilience() {
+	policy := resilience.Policy{
+		MaxAttempts:      viper.GetInt("retry-attempts"),
+		InitialBackoff:   viper.GetDuration("retry-backoff"),
+		MaxBackoff:       viper.GetDuration("retry-max-backoff"),
+		FailureThreshold: viper.GetInt("breaker-threshold"),
+		OpenTimeout:      viper.GetDuration("breaker-timeout"),
+		Deadline:         viper.GetDuration("retry-deadline"),
+	}
+	resilience.SetDefaultPolicy(policy)
+	var entries []struct {
+		Name             string
+		RetryAttempts    *int           `mapstructure:"retry-attempts"`
+		RetryBackoff     *time.Duration `mapstructure:"retry-backoff"`
+		RetryMaxBackoff  *time.Duration `mapstructure:"retry-max-backoff"`
+		RetryDeadline    *time.Duration `mapstructure:"retry-deadline"`
+		BreakerThreshold *int           `mapstructure:"breaker-threshold"`
+		BreakerTimeout   *time.Duration `mapstructure:"breaker-timeout"`
+	}
+	if err := viper.UnmarshalKey("backends", &entries); err != nil {
+		log.Fatalf("cmd: invalid backends configuration: %s", err)
+	}
+	for _, entry := range entries {
+		if entry.Name == "" {
+			log.Fatalf("cmd: invalid backends configuration: missing name")
+		}
+		p := policy
+		if entry.RetryAttempts != nil {
+			p.MaxAttempts = *entry.RetryAttempts
+		}
+		if entry.RetryBackoff != nil {
+			p.InitialBackoff = *entry.RetryBackoff
+		}
+		if entry.RetryMaxBackoff != nil {
+			p.MaxBackoff = *entry.RetryMaxBackoff
+		}
+		if entry.RetryDeadline != nil {
+			p.Deadline = *entry.RetryDeadline
+		}
+		if entry.BreakerThreshold != nil {
+			p.FailureThreshold = *entry.BreakerThreshold
+		}
+		if entry.BreakerTimeout != nil {
+			p.OpenTimeout = *entry.BreakerTimeout
+		}
+		resilience.Configure(entry.Name, p)
+	}
+}
+
+// configureRateLimits sets a default rate limit from flags, and limits for specific accounts and backends
+// from the configuration file, e.g.
+//   rate-limits:
+//     - account: patientcare
+//       rate: 50
+//       burst: 100
+//       daily-quota: 100000
+//     - backend: https://fhir.nhs.uk/Id/nhs-number
+//       rate: 20
+func configureRateLimits(sv *server.Server) {
+	if rate, quota := viper.GetFloat64("rate-limit"), viper.GetUint64("daily-quota"); rate > 0 || quota > 0 {
+		sv.SetDefaultRateLimit(server.RateLimit{Rate: rate, Burst: viper.GetInt("rate-burst"), DailyQuota: quota})
+	}
+	var entries []struct {
+		Account    string
+		Backend    string
+		Rate       float64
+		Burst      int
+		DailyQuota uint64 `mapstructure:"daily-quota"`
+	}
+	if err := viper.UnmarshalKey("rate-limits", &entries); err != nil {
+		log.Fatalf("cmd: invalid rate-limits configuration: %s", err)
+	}
+	for _, entry := range entries {
+		limit := server.RateLimit{Rate: entry.Rate, Burst: entry.Burst, DailyQuota: entry.DailyQuota}
+		switch {
+		case entry.Account != "" && entry.Backend == "":
+			sv.SetAccountRateLimit(parseAccount(entry.Account), limit)
+		case entry.Backend != "" && entry.Account == "":
+			sv.SetBackendRateLimit(entry.Backend, limit)
+		default:
+			log.Fatalf("cmd: invalid rate-limits configuration: specify one of account or backend")
+		}
+	}
+}
+
+// reloadConfig re-reads runtime configuration and pushes updated settings into running providers.
+// Settings that define the structure of the server, such as ports and authentication providers, need a restart.
+// As viper is not safe for concurrent use, configuration is only read at startup and here, with reloads
+// serialised by the server.
+func (my *myServer) reloadConfig() error {
+	if err := viper.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if !errors.As(err, &notFound) {
+			return err
+		}
+	}
+	my.nadex.SetCredentials(viper.GetString("nadex-username"), viper.GetString("nadex-password"))
+	if err := configureEmpiAuthorities(); err != nil {
+		return err
+	}
+	my.patients.RegisterProvider("empi", my.empi, empiSystems()...)
+	my.empi.Reconfigure(viper.GetString("empi-url"), viper.GetString("empi-processing-id"), viper.GetInt("empi-timeout-seconds"), empiCacheDuration())
+	my.empi.SetStaleMaxAge(viper.GetDuration("stale-max-age"))
+	my.cav.SetStaleMaxAge(viper.GetDuration("stale-max-age"))
+	return nil
+}
+
+// empiSystems returns the systems of patient identifiers provided by the EMPI, which are those issued by the
+// configured authorities, other than those provided by a patient administration system
+func empiSystems() []string {
+	var result []string
+	for _, system := range empi.Systems() {
+		if system != identifiers.CardiffAndValeCRN {
+			result = append(result, system)
+		}
+	}
+	return result
+}
+
+// parseAccount parses an account in the form system|value, or simply a value for a service user
+func parseAccount(account string) *apiv1.Identifier {
+	if i := strings.LastIndex(account, "|"); i >= 0 {
+		return &apiv1.Identifier{System: account[:i], Value: account[i+1:]}
+	}
+	return &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: account}
+}
+
+// apiKeysFromConfig returns the API keys, by hash, defined in the configuration file, e.g.
+//   api-keys:
+//     - name: terminology-browser
+//       hash: <hash from 'concierge auth apikey generate'>
+//       scopes: [http://snomed.info/sct]
+func apiKeysFromConfig() map[string]*server.APIKey {
+	var entries []struct {
+		Name   string
+		Hash   string
+		Scopes []string
+	}
+	if err := viper.UnmarshalKey("api-keys", &entries); err != nil {
+		log.Fatalf("cmd: invalid api-keys configuration: %s", err)
+	}
+	keys := make(map[string]*server.APIKey, len(entries))
+	for _, entry := range entries {
+		if entry.Name == "" || entry.Hash == "" {
+			log.Fatalf("cmd: invalid api-keys configuration: name and hash are required")
+		}
+		keys[entry.Hash] = &server.APIKey{Name: entry.Name, Scopes: entry.Scopes}
+	}
+	return keys
+}
+
+// loadFakeData loads the synthetic data served by backends in fake mode, or returns nil to use the default data
+func loadFakeData() *fake.Data {
+	filename := viper.GetString("fake-data")
+	if filename == "" {
+		return nil
+	}
+	if !viper.GetBool("fake") {
+		log.Printf("cmd: warning: synthetic data (--fake-data) are used only in fake mode (--fake)")
+	}
+	data, err := fake.Load(filename)
+	if err != nil {
+		log.Fatalf("cmd: failed to load synthetic data: %s", err)
+	}
+	return data
+}
+
+func nadexServer(fakeData *fake.Data) *nadex.App {
 	nadexApp := new(nadex.App)
 	nadexApp.Username = viper.GetString("nadex-username") // this will be fallback username/password to use
 	nadexApp.Password = viper.GetString("nadex-password")
 	nadexApp.Fake = viper.GetBool("fake")
+	nadexApp.FakeData = fakeData
 	return nadexApp
 }
 
-func walesEmpiServer() *empi.App {
-	empiApp := &empi.App{
-		EndpointURL:    viper.GetString("empi-url"),
-		ProcessingID:   viper.GetString("empi-processing-id"),
-		Fake:           viper.GetBool("fake"),
-		TimeoutSeconds: viper.GetInt("empi-timeout-seconds"),
+// openCacheStore opens the store for cached results: an encryption key is required for persistent stores,
+// as these contain patient identifiable data.
+func openCacheStore() cache.Store {
+	uri := viper.GetString("cache-store")
+	if uri != "" && uri != "memory" && viper.GetString("cache-key") == "" {
+		log.Fatalf("cmd: you must specify an encryption key (--cache-key) for a persistent cache store")
+	}
+	store, err := cache.Open(uri)
+	if err != nil {
+		log.Fatalf("cmd: failed to open cache store: %s", err)
 	}
-	cacheMinutes := viper.GetInt("empi-cache-minutes")
-	if cacheMinutes != 0 {
-		empiApp.Cache = cache.New(time.Duration(cacheMinutes)*time.Minute, time.Duration(cacheMinutes*2)*time.Minute)
+	return store
+}
+
+// cacheKey returns the key used to encrypt cached results, or nil if not configured
+func cacheKey() []byte {
+	s := viper.GetString("cache-key")
+	if s == "" {
+		return nil
+	}
+	key, err := cache.ParseKey(s)
+	if err != nil {
+		log.Fatalf("cmd: %s", err)
 	}
-	log.Printf("empi configuration: cache:%dm timeout:%ds endpoint:%s", cacheMinutes, empiApp.TimeoutSeconds, empiApp.EndpointURL)
+	return key
+}
+
+func walesEmpiServer(store cache.Store, fakeData *fake.Data) *empi.App {
+	empiApp := &empi.App{
+		Fake:       viper.GetBool("fake"),
+		FakeData:   fakeData,
+		CacheStore: store,
+		CacheKey:   cacheKey(),
+	}
+	empiApp.Reconfigure(viper.GetString("empi-url"), viper.GetString("empi-processing-id"), viper.GetInt("empi-timeout-seconds"), empiCacheDuration())
+	empiApp.SetStaleMaxAge(viper.GetDuration("stale-max-age"))
 	return empiApp
 }
 
+// configureEmpiAuthorities registers the authorities issuing identifiers known to the EMPI, from the configuration
+// file if defined there (see empi.Authority), or otherwise the default authorities
+func configureEmpiAuthorities() error {
+	if !viper.IsSet("empi-authorities") {
+		return empi.RegisterAuthorities(empi.DefaultAuthorities)
+	}
+	var authorities []empi.Authority
+	if err := viper.UnmarshalKey("empi-authorities", &authorities); err != nil {
+		return fmt.Errorf("invalid empi-authorities configuration: %w", err)
+	}
+	return empi.RegisterAuthorities(authorities)
+}
+
+func empiCacheDuration() time.Duration {
+	return time.Duration(viper.GetInt("empi-cache-minutes")) * time.Minute
+}
+
 func init() {
 	rootCmd.AddCommand(serveCmd)
 
@@ -156,6 +649,10 @@ func init() {
 	viper.BindPFlag("port-http", serveCmd.PersistentFlags().Lookup("port-http"))
 	serveCmd.PersistentFlags().Int("port-grpc", 9090, "Port to run gRPC server")
 	viper.BindPFlag("port-grpc", serveCmd.PersistentFlags().Lookup("port-grpc"))
+	serveCmd.PersistentFlags().Bool("single-port", false, "Serve gRPC and HTTP on a single port (port-grpc)")
+	viper.BindPFlag("single-port", serveCmd.PersistentFlags().Lookup("single-port"))
+	serveCmd.PersistentFlags().String("unix-socket", "", "Path of a unix domain socket on which to also serve gRPC")
+	viper.BindPFlag("unix-socket", serveCmd.PersistentFlags().Lookup("unix-socket"))
 
 	// SSL certificate configuration
 	serveCmd.PersistentFlags().String("cert", "", "SSL certificate file (.cert)")
@@ -163,14 +660,118 @@ func init() {
 	serveCmd.PersistentFlags().String("key", "", "SSL certificate key file (.key)")
 	viper.BindPFlag("key", serveCmd.PersistentFlags().Lookup("key"))
 
+	// mutual TLS: client certificates verified against these CAs authenticate as the mapped account
+	serveCmd.PersistentFlags().String("tls-client-ca", "", "CA certificates (.pem) used to verify client certificates")
+	viper.BindPFlag("tls-client-ca", serveCmd.PersistentFlags().Lookup("tls-client-ca"))
+	serveCmd.PersistentFlags().StringSlice("client-cert-account", nil, "Client certificate name (SAN or subject CN) mapped to an account, as name=account (e.g. 'pas.example.nhs.uk=pas')")
+	viper.BindPFlag("client-cert-account", serveCmd.PersistentFlags().Lookup("client-cert-account"))
+
+	// configuration reload: certificates and configuration are always reloaded on SIGHUP
+	serveCmd.PersistentFlags().Bool("watch-config", false, "Reload configuration and certificates when the configuration file changes")
+	viper.BindPFlag("watch-config", serveCmd.PersistentFlags().Lookup("watch-config"))
+
 	// authentication configuration.
 	serveCmd.PersistentFlags().Bool("no-auth", false, "Turn off API authentication: all API endpoints will be unprotected")
 	viper.BindPFlag("no-auth", serveCmd.PersistentFlags().Lookup("no-auth"))
 	serveCmd.PersistentFlags().String("jwt-key", "", "RSA key to use for signing and validating JWTs")
 	viper.BindPFlag("jwt-key", serveCmd.PersistentFlags().Lookup("jwt-key"))
 
-	// database authentication server options
-	serveCmd.PersistentFlags().String("auth-db", "", "Auth database connection string (e.g. 'dbname=concierge sslmode=disable'")
-	viper.BindPFlag("auth-db", serveCmd.PersistentFlags().Lookup("auth-db"))
+	// client assertion (private_key_jwt) authentication options
+	serveCmd.PersistentFlags().String("auth-jwt-keys", "", "Directory of service user public keys (<username>.pem) for signed JWT assertion login")
+	viper.BindPFlag("auth-jwt-keys", serveCmd.PersistentFlags().Lookup("auth-jwt-keys"))
+	serveCmd.PersistentFlags().String("auth-jwt-audience", "concierge", "Audience ('aud') required in signed JWT assertions")
+	viper.BindPFlag("auth-jwt-audience", serveCmd.PersistentFlags().Lookup("auth-jwt-audience"))
+
+	// OpenID Connect user authentication options
+	serveCmd.PersistentFlags().String("oidc-issuer", "", "OpenID Connect issuer whose ID tokens may be used for user login (e.g. 'https://login.microsoftonline.com/<tenant>/v2.0')")
+	viper.BindPFlag("oidc-issuer", serveCmd.PersistentFlags().Lookup("oidc-issuer"))
+	serveCmd.PersistentFlags().String("oidc-client-id", "", "OpenID Connect client id, which must be in the ID token audience")
+	viper.BindPFlag("oidc-client-id", serveCmd.PersistentFlags().Lookup("oidc-client-id"))
+	serveCmd.PersistentFlags().String("oidc-claim", "preferred_username", "ID token claim containing the username")
+	viper.BindPFlag("oidc-claim", serveCmd.PersistentFlags().Lookup("oidc-claim"))
+	serveCmd.PersistentFlags().StringSlice("oidc-domain", nil, "Domains for which a claim in the form user@domain matches user (e.g. 'cymru.nhs.uk'); otherwise the claim must match exactly")
+	viper.BindPFlag("oidc-domain", serveCmd.PersistentFlags().Lookup("oidc-domain"))
+	serveCmd.PersistentFlags().String("oidc-namespace", identifiers.CymruUserID, "Namespace of users authenticated by ID token")
+	viper.BindPFlag("oidc-namespace", serveCmd.PersistentFlags().Lookup("oidc-namespace"))
+	serveCmd.PersistentFlags().Bool("oidc-only", false, "Only permit login by ID token for the OpenID Connect namespace, disabling password login")
+	viper.BindPFlag("oidc-only", serveCmd.PersistentFlags().Lookup("oidc-only"))
+
+	// kerberos (SPNEGO) single sign-on options
+	serveCmd.PersistentFlags().String("krb5-keytab", "", "Keytab for the HTTP service principal, enabling kerberos login at /v1/auth/negotiate")
+	viper.BindPFlag("krb5-keytab", serveCmd.PersistentFlags().Lookup("krb5-keytab"))
+	serveCmd.PersistentFlags().String("krb5-service-principal", "", "Service principal in the keytab (e.g. 'HTTP/concierge.cymru.nhs.uk'), default from ticket")
+	viper.BindPFlag("krb5-service-principal", serveCmd.PersistentFlags().Lookup("krb5-service-principal"))
+	serveCmd.PersistentFlags().String("krb5-realm", "CYMRU.NHS.UK", "Realm of users permitted to login using kerberos")
+	viper.BindPFlag("krb5-realm", serveCmd.PersistentFlags().Lookup("krb5-realm"))
+
+	// rate limits: limits for specific accounts and backends are set in the configuration file
+	serveCmd.PersistentFlags().Float64("rate-limit", 0, "Default requests per second permitted for each client, 0=no limit")
+	viper.BindPFlag("rate-limit", serveCmd.PersistentFlags().Lookup("rate-limit"))
+	serveCmd.PersistentFlags().Int("rate-burst", 0, "Default maximum burst of requests for each client, default is the rate limit")
+	viper.BindPFlag("rate-burst", serveCmd.PersistentFlags().Lookup("rate-burst"))
+	serveCmd.PersistentFlags().Uint64("daily-quota", 0, "Default maximum requests per day for each client, 0=no quota")
+	viper.BindPFlag("daily-quota", serveCmd.PersistentFlags().Lookup("daily-quota"))
+
+	// retries and circuit breakers for outbound backends: policies for specific backends are set in the configuration file
+	serveCmd.PersistentFlags().Int("retry-attempts", resilience.DefaultPolicy.MaxAttempts, "Maximum attempts for idempotent requests to backend services, including the first")
+	viper.BindPFlag("retry-attempts", serveCmd.PersistentFlags().Lookup("retry-attempts"))
+	serveCmd.PersistentFlags().Duration("retry-backoff", resilience.DefaultPolicy.InitialBackoff, "Maximum delay before the first retry, doubled for each retry, with random jitter")
+	viper.BindPFlag("retry-backoff", serveCmd.PersistentFlags().Lookup("retry-backoff"))
+	serveCmd.PersistentFlags().Duration("retry-max-backoff", resilience.DefaultPolicy.MaxBackoff, "Maximum delay between retries")
+	viper.BindPFlag("retry-max-backoff", serveCmd.PersistentFlags().Lookup("retry-max-backoff"))
+	serveCmd.PersistentFlags().Duration("retry-deadline", resilience.DefaultPolicy.Deadline, "Maximum total time for a request to a backend service, including retries, 0=none")
+	viper.BindPFlag("retry-deadline", serveCmd.PersistentFlags().Lookup("retry-deadline"))
+	serveCmd.PersistentFlags().Int("breaker-threshold", resilience.DefaultPolicy.FailureThreshold, "Consecutive failures of a backend service before failing fast, 0=never")
+	viper.BindPFlag("breaker-threshold", serveCmd.PersistentFlags().Lookup("breaker-threshold"))
+	serveCmd.PersistentFlags().Duration("breaker-timeout", resilience.DefaultPolicy.OpenTimeout, "Time to fail fast before retrying a failed backend service")
+	viper.BindPFlag("breaker-timeout", serveCmd.PersistentFlags().Lookup("breaker-timeout"))
+
+	// cache storage: in-memory, on-disk, or shared between replicas in a PostgreSQL database
+	serveCmd.PersistentFlags().String("cache-store", "memory", "Cache store: 'memory', 'file:<path>' or 'postgres://...'")
+	viper.BindPFlag("cache-store", serveCmd.PersistentFlags().Lookup("cache-store"))
+	serveCmd.PersistentFlags().String("cache-key", "", "Key to encrypt cached data, and hash its keys, at rest, base64 encoded (e.g. from 'openssl rand -base64 32')")
+	viper.BindPFlag("cache-key", serveCmd.PersistentFlags().Lookup("cache-key"))
+
+	// degraded mode: serve the last known result, flagged as stale, when a backend service is unavailable
+	serveCmd.PersistentFlags().Duration("stale-max-age", 0, "Maximum age of last known patient data served when a backend is unavailable (e.g. '24h'), 0=off")
+	viper.BindPFlag("stale-max-age", serveCmd.PersistentFlags().Lookup("stale-max-age"))
+
+	// break-glass: access to restricted records requires a justification, which is recorded
+	serveCmd.PersistentFlags().StringSlice("break-glass-namespace", nil, "Namespaces in which resolving an identifier requires a justification (e.g. another health board's CRN)")
+	viper.BindPFlag("break-glass-namespace", serveCmd.PersistentFlags().Lookup("break-glass-namespace"))
+	serveCmd.PersistentFlags().String("restricted-records", "", "File of restricted records, requiring a justification to access, as system|value per line")
+	viper.BindPFlag("restricted-records", serveCmd.PersistentFlags().Lookup("restricted-records"))
+	serveCmd.PersistentFlags().String("break-glass-log", "", "File in which to record access to restricted records")
+	viper.BindPFlag("break-glass-log", serveCmd.PersistentFlags().Lookup("break-glass-log"))
+
+	// legitimate relationships: access to a patient requires a relationship, such as a clinic booking
+	serveCmd.PersistentFlags().String("relationship-mode", "off", "Access without a legitimate relationship with the patient: 'off', 'deny' (unless justified) or 'flag'")
+	viper.BindPFlag("relationship-mode", serveCmd.PersistentFlags().Lookup("relationship-mode"))
+	serveCmd.PersistentFlags().String("relationships", "", "CSV file of relationships between users and patients: user, patient, basis, optional expiry (YYYY-MM-DD)")
+	viper.BindPFlag("relationships", serveCmd.PersistentFlags().Lookup("relationships"))
+	serveCmd.PersistentFlags().Int("relationship-clinic-days", 7, "Days before and after today in which a clinic booking is a relationship with a patient")
+	viper.BindPFlag("relationship-clinic-days", serveCmd.PersistentFlags().Lookup("relationship-clinic-days"))
+
+	// HL7 v2 ADT feeds, used to keep cached patients up-to-date and to notify subscribers of changes
+	serveCmd.PersistentFlags().String("adt-listen", "", "TCP address on which to receive HL7 v2 ADT messages using MLLP (e.g. ':2575'); messages are not authenticated, so use a trusted network")
+	viper.BindPFlag("adt-listen", serveCmd.PersistentFlags().Lookup("adt-listen"))
+	serveCmd.PersistentFlags().StringSlice("adt-allow", nil, "IP addresses or networks (e.g. '10.1.2.3' or '10.1.2.0/24') from which ADT messages are accepted; required with --adt-listen")
+	viper.BindPFlag("adt-allow", serveCmd.PersistentFlags().Lookup("adt-allow"))
+	serveCmd.PersistentFlags().Bool("adt-refresh", false, "Refresh cached patients from ADT messages, rather than only invalidating them; use only for feeds with complete demographics")
+	viper.BindPFlag("adt-refresh", serveCmd.PersistentFlags().Lookup("adt-refresh"))
+
+	// identifiers superseded by merges, which are followed when resolving identifiers
+	serveCmd.PersistentFlags().Bool("supersessions", false, "Record identifiers superseded by merges (e.g. from ADT A40 messages) in the cache store, which should be shared by replicas")
+	viper.BindPFlag("supersessions", serveCmd.PersistentFlags().Lookup("supersessions"))
+
+	// probabilistic matching of patients: total weights of evidence (log2 likelihood ratio) for classification
+	serveCmd.PersistentFlags().Float64("match-threshold", matching.DefaultMatchThreshold, "Weight at or above which patients are classified as a match")
+	viper.BindPFlag("match-threshold", serveCmd.PersistentFlags().Lookup("match-threshold"))
+	serveCmd.PersistentFlags().Float64("possible-match-threshold", matching.DefaultPossibleMatchThreshold, "Weight at or above which patients are classified as a possible match, for review")
+	viper.BindPFlag("possible-match-threshold", serveCmd.PersistentFlags().Lookup("possible-match-threshold"))
+
+	// administrators, as service user names or system|value
+	serveCmd.PersistentFlags().StringSlice("admin", nil, "Accounts permitted to use administrative services (e.g. 'ops' for a service user)")
+	viper.BindPFlag("admin", serveCmd.PersistentFlags().Lookup("admin"))
 
 }
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wardle/concierge/hl7"
+	"github.com/wardle/concierge/wales/empi"
+)
+
+// simulateCmd groups commands that simulate backend services and feeds for development and testing
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Simulate a backend service or feed for development and testing",
+}
+
+// simulateEmpiCmd runs a local simulation of the NHS Wales' EMPI
+var simulateEmpiCmd = &cobra.Command{
+	Use:   "empi",
+	Short: "Simulate the NHS Wales' EMPI, serving synthetic patients",
+	Long: `Simulate the NHS Wales' EMPI, serving synthetic patients from a directory of fixtures, one patient per
+file (*.json) in the JSON representation of a Patient. Use the simulator by setting the EMPI endpoint URL,
+e.g. 'concierge serve --empi-url http://localhost:8085/'.`,
+	Example: `concierge simulate empi --fixtures wales/empi/testdata/patients
+concierge simulate empi --fixtures patients --latency 2s --fault-rate 0.1 --not-found-rate 0.05`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := cmd.Flags()
+		port, _ := flags.GetInt("port")
+		fixtures, _ := flags.GetString("fixtures")
+		sim := empi.NewSimulator()
+		sim.Latency, _ = flags.GetDuration("latency")
+		sim.FaultRate, _ = flags.GetFloat64("fault-rate")
+		sim.NotFoundRate, _ = flags.GetFloat64("not-found-rate")
+		if fixtures != "" {
+			if err := sim.LoadFixtures(fixtures); err != nil {
+				log.Fatalf("cmd: failed to load fixtures: %s", err)
+			}
+		}
+		log.Printf("cmd: simulating EMPI on port %d: latency:%v fault-rate:%v not-found-rate:%v", port, sim.Latency, sim.FaultRate, sim.NotFoundRate)
+		srv := &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: sim, ReadHeaderTimeout: 10 * time.Second}
+		log.Fatal(srv.ListenAndServe())
+	},
+}
+
+// simulateAdtCmd sends HL7 v2 messages using MLLP, simulating a patient administration or EMPI feed
+var simulateAdtCmd = &cobra.Command{
+	Use:   "adt <file>...",
+	Short: "Simulate a HL7 v2 ADT feed, sending messages from files",
+	Long: `Simulate a HL7 v2 ADT feed, sending each message, one per file, to a MLLP listener such as that started by
+'concierge serve --adt-listen :2575', and printing each acknowledgement.`,
+	Example: `concierge simulate adt --addr localhost:2575 wales/empi/testdata/adt/a08.hl7 wales/empi/testdata/adt/a40.hl7`,
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		addr, _ := cmd.Flags().GetString("addr")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		for _, filename := range args {
+			b, err := ioutil.ReadFile(filename)
+			if err != nil {
+				log.Fatalf("cmd: failed to read message: %s", err)
+			}
+			msg, err := hl7.Parse(b)
+			if err != nil {
+				log.Fatalf("cmd: invalid message in %s: %s", filename, err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			ack, err := hl7.Send(ctx, addr, msg.Encode())
+			cancel()
+			if err != nil {
+				log.Fatalf("cmd: failed to send %s: %s", filename, err)
+			}
+			fmt.Printf("%s: %s %s\n", filename, ack.Value("MSA", 1, 1), ack.Value("MSA", 3, 1))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(simulateCmd)
+	simulateCmd.AddCommand(simulateEmpiCmd)
+	simulateCmd.AddCommand(simulateAdtCmd)
+	simulateAdtCmd.Flags().String("addr", "localhost:2575", "Address of the MLLP listener")
+	simulateAdtCmd.Flags().Duration("timeout", 10*time.Second, "Time to wait for each acknowledgement")
+	simulateEmpiCmd.Flags().Int("port", 8085, "Port on which to serve the simulated EMPI")
+	simulateEmpiCmd.Flags().String("fixtures", "", "Directory of synthetic patients, one per file (*.json)")
+	simulateEmpiCmd.Flags().Duration("latency", 0, "Delay before each response (e.g. '500ms')")
+	simulateEmpiCmd.Flags().Float64("fault-rate", 0, "Proportion of requests (0-1) that fail with a SOAP fault")
+	simulateEmpiCmd.Flags().Float64("not-found-rate", 0, "Proportion of requests (0-1) that find no patient")
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"log"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchConfig calls the function specified whenever the configuration file changes, including when replaced,
+// such as by an editor or when a mounted configuration map is updated.
+// Unlike viper's WatchConfig, this does not re-read the configuration itself, so that reading the configuration can
+// be serialised with other reads, as viper is not safe for concurrent use.
+func watchConfig(filename string, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	filename = filepath.Clean(filename)
+	realFilename, _ := filepath.EvalSymlinks(filename)
+	if err := watcher.Add(filepath.Dir(filename)); err != nil {
+		watcher.Close()
+		return err
+	}
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case e, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				current, _ := filepath.EvalSymlinks(filename)
+				written := filepath.Clean(e.Name) == filename && e.Op&(fsnotify.Write|fsnotify.Create) != 0
+				if written || (current != "" && current != realFilename) {
+					realFilename = current
+					log.Printf("cmd: configuration file changed: %s", e.Name)
+					onChange()
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("cmd: error watching configuration file: %s", err)
+			}
+		}
+	}()
+	return nil
+}
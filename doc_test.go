@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/fake"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/wales/cav"
+	"github.com/wardle/concierge/wales/empi"
+)
+
+const testDocumentData = `
+patients:
+  - lastname: SMITH
+    firstnames: JOHN
+    gender: MALE
+    birthDate: "1970-01-01T00:00:00Z"
+    identifiers:
+      - {system: "https://fhir.nhs.uk/Id/nhs-number", value: "9990000018"}
+      - {system: "https://fhir.cardiff.wales.nhs.uk/Id/pas-identifier", value: X100001}
+`
+
+// TestPublishDocumentFromEMPI publishes documents for patients identified only by NHS number, which are published
+// to Cardiff and Vale using the hospital number from the EMPI if the demographics match those in the EMPI
+func TestPublishDocumentFromEMPI(t *testing.T) {
+	data, err := fake.Parse(strings.NewReader(testDocumentData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pms := cav.NewPMSService("", "", time.Second, true)
+	pms.SetFakeData(data)
+	ds := &DocumentService{cavpms: pms, empi: &empi.App{Fake: true, FakeData: data}}
+	birthDate, _ := ptypes.TimestampProto(time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC))
+	request := func(lastname string) *apiv1.PublishDocumentRequest {
+		return &apiv1.PublishDocumentRequest{Document: &apiv1.Document{
+			Id:    &apiv1.Identifier{System: identifiers.UUID, Value: "7f1b2b5a-4a4d-4b56-8b1f-0f7a3c2a1e11"},
+			Title: "Clinic letter",
+			Patient: &apiv1.Patient{Lastname: lastname, Gender: apiv1.Gender_MALE, BirthDate: birthDate,
+				Identifiers: []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "9990000018"}}},
+			Data: &apiv1.Attachment{ContentType: "application/pdf", Data: []byte("%PDF-1.4")},
+		}}
+	}
+	crn := &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "X100001"}
+
+	// demographics that do not match the EMPI are rejected
+	if _, err := ds.PublishDocument(context.Background(), request("JONES")); err == nil || !strings.Contains(err.Error(), "mismatched demographics") {
+		t.Fatalf("expected document for patient with demographics not matching EMPI to be rejected, got: %v", err)
+	}
+	if docs := data.Documents(crn); len(docs) != 0 {
+		t.Fatalf("document published for mismatched patient: %v", docs)
+	}
+
+	r := request("SMITH")
+	response, err := ds.PublishDocument(context.Background(), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.GetId().GetSystem() != identifiers.CardiffAndValeDocID {
+		t.Fatalf("incorrect document identifier: %v", response.GetId())
+	}
+	docs := data.Documents(crn)
+	if len(docs) != 1 || docs[0].GetTitle() != "Clinic letter" {
+		t.Fatalf("document not published using hospital number from EMPI: %v", docs)
+	}
+	if len(r.GetDocument().GetPatient().GetIdentifiers()) != 1 {
+		t.Fatalf("request modified when publishing: %v", r.GetDocument().GetPatient())
+	}
+}
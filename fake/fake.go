@@ -0,0 +1,371 @@
+// Package fake provides synthetic patients, practitioners, clinics and documents for the fake modes of backend
+// services, so that they answer consistently from the same data: the same patient is returned whether looked up
+// by NHS number or by hospital number, and clinic lists reference patients who can themselves be looked up.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+// Clinic is a clinic with booked patients, on a specific date or, if no date is given, on every day
+type Clinic struct {
+	Clinic   *apiv1.Identifier
+	Date     time.Time           // zero for every day
+	Patients []*apiv1.Identifier // identifiers of booked patients
+}
+
+// Data is a set of synthetic data. This is thread-safe.
+type Data struct {
+	mu            sync.RWMutex
+	patients      []*apiv1.Patient
+	practitioners []*apiv1.Practitioner
+	clinics       []*Clinic
+	documents     []*apiv1.Document
+	byID          map[string]proto.Message // patients and practitioners by identifier, as system|value
+}
+
+func key(id *apiv1.Identifier) string {
+	return id.GetSystem() + "|" + strings.ToUpper(id.GetValue())
+}
+
+// Patient returns a copy of the patient with the identifier specified, in any namespace.
+// Copies are returned so that callers may modify them without changing the data.
+func (d *Data) Patient(id *apiv1.Identifier) (*apiv1.Patient, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	pt, ok := d.byID[key(id)].(*apiv1.Patient)
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(pt).(*apiv1.Patient), true
+}
+
+// Patients returns copies of all patients
+func (d *Data) Patients() []*apiv1.Patient {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	result := make([]*apiv1.Patient, 0, len(d.patients))
+	for _, pt := range d.patients {
+		result = append(result, proto.Clone(pt).(*apiv1.Patient))
+	}
+	return result
+}
+
+// Practitioner returns a copy of the practitioner with the identifier specified, in any namespace
+func (d *Data) Practitioner(id *apiv1.Identifier) (*apiv1.Practitioner, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	p, ok := d.byID[key(id)].(*apiv1.Practitioner)
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(p).(*apiv1.Practitioner), true
+}
+
+// PatientsForClinic returns copies of the patients booked into the clinic on the date specified
+func (d *Data) PatientsForClinic(clinic *apiv1.Identifier, date time.Time) []*apiv1.Patient {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	day := date.Format("2006-01-02")
+	var result []*apiv1.Patient
+	for _, c := range d.clinics {
+		if c.Clinic.GetSystem() != clinic.GetSystem() || !strings.EqualFold(c.Clinic.GetValue(), clinic.GetValue()) {
+			continue
+		}
+		if !c.Date.IsZero() && c.Date.Format("2006-01-02") != day {
+			continue
+		}
+		for _, id := range c.Patients {
+			result = append(result, proto.Clone(d.byID[key(id)]).(*apiv1.Patient))
+		}
+	}
+	return result
+}
+
+// Documents returns copies of the documents for the patient with the identifier specified
+func (d *Data) Documents(patient *apiv1.Identifier) []*apiv1.Document {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	pt := d.byID[key(patient)]
+	var result []*apiv1.Document
+	for _, doc := range d.documents {
+		if pt != nil && d.patientFor(doc.GetPatient()) == pt {
+			result = append(result, proto.Clone(doc).(*apiv1.Document))
+		}
+	}
+	return result
+}
+
+// AddDocument adds a document, such as one published to a fake backend, returning its position in the list of
+// documents, starting at one. The document's patient must be known.
+func (d *Data) AddDocument(doc *apiv1.Document) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.patientFor(doc.GetPatient()) == nil {
+		return 0, fmt.Errorf("fake: no patient for document '%s|%s'", doc.GetId().GetSystem(), doc.GetId().GetValue())
+	}
+	d.documents = append(d.documents, proto.Clone(doc).(*apiv1.Document))
+	return len(d.documents), nil
+}
+
+// patientFor returns the known patient with any of the identifiers of the patient specified, or nil
+func (d *Data) patientFor(pt *apiv1.Patient) proto.Message {
+	for _, id := range pt.GetIdentifiers() {
+		if p, ok := d.byID[key(id)].(*apiv1.Patient); ok {
+			return p
+		}
+	}
+	return nil
+}
+
+// index adds patients and practitioners by identifier, returning an error if an identifier is duplicated
+func (d *Data) index(m proto.Message, ids []*apiv1.Identifier) error {
+	for _, id := range ids {
+		if id.GetSystem() == "" || id.GetValue() == "" {
+			return fmt.Errorf("invalid identifier: '%s|%s'", id.GetSystem(), id.GetValue())
+		}
+		if _, exists := d.byID[key(id)]; exists {
+			return fmt.Errorf("duplicate identifier: '%s|%s'", id.GetSystem(), id.GetValue())
+		}
+		d.byID[key(id)] = m
+	}
+	return nil
+}
+
+// file is the format of a file of synthetic data, in JSON or YAML. Patients, practitioners and documents use the
+// JSON representation of the corresponding protocol buffer messages.
+type file struct {
+	Patients      []interface{} `yaml:"patients"`
+	Practitioners []interface{} `yaml:"practitioners"`
+	Clinics       []struct {
+		System   string   `yaml:"system"` // defaults to Cardiff and Vale clinic codes
+		Code     string   `yaml:"code"`
+		Date     string   `yaml:"date"`     // YYYY-MM-DD, or empty for every day
+		Patients []string `yaml:"patients"` // as system|value, or a NHS number
+	} `yaml:"clinics"`
+	Documents []interface{} `yaml:"documents"`
+}
+
+// Parse parses synthetic data in JSON or YAML, checking that clinics and documents reference known patients
+func Parse(r io.Reader) (*Data, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var f file
+	if err := yaml.UnmarshalStrict(b, &f); err != nil {
+		return nil, err
+	}
+	d := &Data{byID: make(map[string]proto.Message)}
+	for i, v := range f.Patients {
+		pt := new(apiv1.Patient)
+		if err := unmarshal(v, pt); err != nil {
+			return nil, fmt.Errorf("patient %d: %w", i+1, err)
+		}
+		if err := d.index(pt, pt.GetIdentifiers()); err != nil {
+			return nil, fmt.Errorf("patient %d: %w", i+1, err)
+		}
+		d.patients = append(d.patients, pt)
+	}
+	for i, v := range f.Practitioners {
+		p := new(apiv1.Practitioner)
+		if err := unmarshal(v, p); err != nil {
+			return nil, fmt.Errorf("practitioner %d: %w", i+1, err)
+		}
+		if err := d.index(p, p.GetIdentifiers()); err != nil {
+			return nil, fmt.Errorf("practitioner %d: %w", i+1, err)
+		}
+		d.practitioners = append(d.practitioners, p)
+	}
+	for i, c := range f.Clinics {
+		clinic := &Clinic{Clinic: &apiv1.Identifier{System: c.System, Value: c.Code}}
+		if clinic.Clinic.System == "" {
+			clinic.Clinic.System = identifiers.CardiffAndValeClinicCode
+		}
+		if c.Code == "" {
+			return nil, fmt.Errorf("clinic %d: missing code", i+1)
+		}
+		if c.Date != "" {
+			if clinic.Date, err = time.ParseInLocation("2006-01-02", c.Date, time.Local); err != nil {
+				return nil, fmt.Errorf("clinic %d: invalid date: %w", i+1, err)
+			}
+		}
+		for _, s := range c.Patients {
+			id := parsePatientIdentifier(s)
+			if _, ok := d.byID[key(id)].(*apiv1.Patient); !ok {
+				return nil, fmt.Errorf("clinic %d: unknown patient '%s'", i+1, s)
+			}
+			clinic.Patients = append(clinic.Patients, id)
+		}
+		d.clinics = append(d.clinics, clinic)
+	}
+	for i, v := range f.Documents {
+		doc := new(apiv1.Document)
+		if err := unmarshal(v, doc); err != nil {
+			return nil, fmt.Errorf("document %d: %w", i+1, err)
+		}
+		if d.patientFor(doc.GetPatient()) == nil {
+			return nil, fmt.Errorf("document %d: unknown patient", i+1)
+		}
+		d.documents = append(d.documents, doc)
+	}
+	return d, nil
+}
+
+// Load reads synthetic data from the JSON or YAML file specified (see Parse)
+func Load(filename string) (*Data, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	d, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	log.Printf("fake: loaded %d patients, %d practitioners, %d clinics and %d documents from %s", len(d.patients), len(d.practitioners), len(d.clinics), len(d.documents), filename)
+	return d, nil
+}
+
+func parsePatientIdentifier(s string) *apiv1.Identifier {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndex(s, "|"); i >= 0 {
+		return &apiv1.Identifier{System: s[:i], Value: s[i+1:]}
+	}
+	return &apiv1.Identifier{System: identifiers.NHSNumber, Value: s}
+}
+
+// unmarshal converts a value decoded from YAML into a protocol buffer message, via its JSON representation
+func unmarshal(v interface{}, m proto.Message) error {
+	b, err := json.Marshal(jsonValue(v))
+	if err != nil {
+		return err
+	}
+	return protojson.Unmarshal(b, m)
+}
+
+// jsonValue converts a value decoded from YAML, which may contain maps with non-string keys, into a value
+// that can be encoded as JSON
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			m[fmt.Sprint(k)] = jsonValue(value)
+		}
+		return m
+	case []interface{}:
+		for i := range v {
+			v[i] = jsonValue(v[i])
+		}
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	}
+	return v
+}
+
+var defaultData *Data
+
+// Default returns the default synthetic data, used if no other data are configured
+func Default() *Data {
+	return defaultData
+}
+
+// AnyPatient returns a synthetic patient with the identifier specified, based on the first default patient, for
+// identifiers not in the default data, so that fake backends without configured data find a patient for any
+// identifier
+func AnyPatient(id *apiv1.Identifier) *apiv1.Patient {
+	pt := proto.Clone(defaultData.patients[0]).(*apiv1.Patient)
+	ids := []*apiv1.Identifier{{System: id.GetSystem(), Value: id.GetValue()}}
+	for _, other := range pt.GetIdentifiers() {
+		if other.GetSystem() != id.GetSystem() {
+			ids = append(ids, other)
+		}
+	}
+	pt.Identifiers = ids
+	return pt
+}
+
+// AnyPractitioner returns a synthetic practitioner with the identifier specified, based on the first default
+// practitioner, for identifiers not in the default data
+func AnyPractitioner(id *apiv1.Identifier) *apiv1.Practitioner {
+	p := proto.Clone(defaultData.practitioners[0]).(*apiv1.Practitioner)
+	ids := []*apiv1.Identifier{{System: id.GetSystem(), Value: id.GetValue()}}
+	for _, other := range p.GetIdentifiers() {
+		if other.GetSystem() != id.GetSystem() {
+			ids = append(ids, other)
+		}
+	}
+	p.Identifiers = ids
+	return p
+}
+
+func init() {
+	var err error
+	if defaultData, err = Parse(strings.NewReader(defaultYAML)); err != nil {
+		panic(fmt.Sprintf("fake: invalid default data: %s", err))
+	}
+}
+
+// defaultYAML is the default synthetic data, and an example of the file format
+const defaultYAML = `
+patients:
+  - lastname: DUMMY
+    firstnames: ALBERT
+    title: DR
+    gender: MALE
+    birthDate: "1960-01-01T00:00:00Z"
+    surgery: W95010
+    generalPractitioner: G9342400
+    identifiers:
+      - {system: "https://fhir.nhs.uk/Id/nhs-number", value: "1111111111"}
+      - {system: "https://fhir.cardiff.wales.nhs.uk/Id/pas-identifier", value: A999998}
+    addresses:
+      - {address1: 59 Robins Hill, address2: Brackla, address3: Bridgend, postcode: CF31 2PJ, country: WALES}
+    telephones:
+      - {number: 02920 747747, description: Home}
+      - {number: 02920 711711, description: Mobile}
+    emails: [test@test.com, wibble@test.com]
+  - lastname: DUMMY
+    firstnames: BETTY
+    title: MRS
+    gender: FEMALE
+    birthDate: "1962-05-21T00:00:00Z"
+    surgery: W95010
+    generalPractitioner: G9342400
+    identifiers:
+      - {system: "https://fhir.nhs.uk/Id/nhs-number", value: "2222222222"}
+      - {system: "https://fhir.cardiff.wales.nhs.uk/Id/pas-identifier", value: A999997}
+    addresses:
+      - {address1: 59 Robins Hill, address2: Brackla, address3: Bridgend, postcode: CF31 2PJ, country: WALES}
+
+practitioners:
+  - active: true
+    names:
+      - {given: Fred, family: Flintstone, prefixes: [Mr]}
+    roles:
+      - role: {jobTitle: Consultant Neurologist}
+    emails: [wibble@wobble.org]
+    identifiers:
+      - {system: "https://fhir.nhs.uk/Id/cymru-user-id", value: fred}
+      - {system: "https://fhir.hl7.org.uk/Id/gmc-number", value: "4624000"}
+
+clinics:
+  - code: NEUR1
+    patients: ["1111111111", "https://fhir.cardiff.wales.nhs.uk/Id/pas-identifier|A999997"]
+`
@@ -0,0 +1,99 @@
+package fake
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/protobuf/proto"
+)
+
+const testData = `
+patients:
+  - lastname: SMITH
+    firstnames: JOHN
+    birthDate: 1970-01-01T00:00:00Z
+    identifiers:
+      - {system: "https://fhir.nhs.uk/Id/nhs-number", value: "9990000018"}
+      - {system: "https://fhir.cardiff.wales.nhs.uk/Id/pas-identifier", value: x100001}
+practitioners:
+  - names: [{given: Jane, family: Jones}]
+    identifiers: [{system: "https://fhir.nhs.uk/Id/cymru-user-id", value: jj123456}]
+clinics:
+  - code: NEUR1
+    date: 2020-06-17
+    patients: ["9990000018"]
+  - code: NEUR2
+    patients: ["https://fhir.cardiff.wales.nhs.uk/Id/pas-identifier|X100001"]
+documents:
+  - id: {system: "urn:uuid", value: 7f1b2b5a-4a4d-4b56-8b1f-0f7a3c2a1e11}
+    title: Clinic letter
+    patient: {identifiers: [{system: "https://fhir.nhs.uk/Id/nhs-number", value: "9990000018"}]}
+`
+
+func TestParse(t *testing.T) {
+	d, err := Parse(strings.NewReader(testData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	byNHS, found := d.Patient(&apiv1.Identifier{System: identifiers.NHSNumber, Value: "9990000018"})
+	if !found || byNHS.GetLastname() != "SMITH" || byNHS.GetBirthDate() == nil {
+		t.Fatalf("patient not found by NHS number: %v", byNHS)
+	}
+	byCRN, found := d.Patient(&apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "X100001"})
+	if !found || !proto.Equal(byCRN, byNHS) {
+		t.Fatal("same patient not found by hospital number")
+	}
+	byCRN.Lastname = "CHANGED" // callers are given copies
+	if pt, _ := d.Patient(&apiv1.Identifier{System: identifiers.NHSNumber, Value: "9990000018"}); pt.GetLastname() != "SMITH" {
+		t.Fatal("synthetic data changed by modifying a returned patient")
+	}
+	if _, found := d.Practitioner(&apiv1.Identifier{System: identifiers.CymruUserID, Value: "jj123456"}); !found {
+		t.Fatal("practitioner not found")
+	}
+	if _, found := d.Patient(&apiv1.Identifier{System: identifiers.CymruUserID, Value: "jj123456"}); found {
+		t.Fatal("practitioner returned as patient")
+	}
+	clinic := &apiv1.Identifier{System: identifiers.CardiffAndValeClinicCode, Value: "NEUR1"}
+	if pts := d.PatientsForClinic(clinic, time.Date(2020, 6, 17, 9, 0, 0, 0, time.Local)); len(pts) != 1 || !proto.Equal(pts[0], byNHS) {
+		t.Fatalf("incorrect clinic list: %v", pts)
+	}
+	if pts := d.PatientsForClinic(clinic, time.Date(2020, 6, 18, 9, 0, 0, 0, time.Local)); len(pts) != 0 {
+		t.Fatalf("clinic list on another date: %v", pts)
+	}
+	if pts := d.PatientsForClinic(&apiv1.Identifier{System: identifiers.CardiffAndValeClinicCode, Value: "NEUR2"}, time.Now()); len(pts) != 1 {
+		t.Fatalf("clinic on every day not found: %v", pts)
+	}
+	if docs := d.Documents(&apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "X100001"}); len(docs) != 1 || docs[0].GetTitle() != "Clinic letter" {
+		t.Fatalf("incorrect documents: %v", docs)
+	}
+	if _, err := d.AddDocument(&apiv1.Document{Patient: &apiv1.Patient{Identifiers: []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "1111111111"}}}}); err == nil {
+		t.Fatal("expected error adding document for unknown patient")
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown clinic patient": "clinics: [{code: NEUR1, patients: [\"1111111111\"]}]",
+		"duplicate identifier":   "patients: [{identifiers: [{system: a, value: \"1\"}]}, {identifiers: [{system: a, value: \"1\"}]}]",
+		"unknown field":          "patients: [{surname: SMITH}]",
+		"unknown section":        "appointments: []",
+	}
+	for name, data := range tests {
+		if _, err := Parse(strings.NewReader(data)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if len(Default().Patients()) == 0 {
+		t.Fatal("no default patients")
+	}
+	id := &apiv1.Identifier{System: identifiers.NHSNumber, Value: "3333333333"}
+	if pt := AnyPatient(id); pt.GetLastname() == "" || !proto.Equal(pt.GetIdentifiers()[0], id) || len(pt.GetIdentifiers()) != 2 {
+		t.Fatalf("incorrect patient for any identifier: %v", pt)
+	}
+	if _, found := Default().Patient(id); found {
+		t.Fatal("patient for any identifier added to default data")
+	}
+}
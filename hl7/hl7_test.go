@@ -0,0 +1,124 @@
+package hl7
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const adtA08 = "MSH|^~\\&|PAS|RWM|CONCIERGE|CYMRU|20200612143000||ADT^A08^ADT_A01|MSG00001|P|2.5\r" +
+	"EVN|A08|20200612143000\r" +
+	"PID|1||9990000123^^^NHS^NH~X999123^^^140^PI||TESTPATIENT^MARY^^^MRS^^L||19760224|F|||1 SYNTHETIC ST\\S\\FLAT 2^^CARDIFF^^CF99 1AA\r"
+
+func TestParse(t *testing.T) {
+	m, err := Parse([]byte(adtA08))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msgType, event := m.Type(); msgType != "ADT" || event != "A08" {
+		t.Fatalf("incorrect message type: %s^%s", msgType, event)
+	}
+	if m.ControlID() != "MSG00001" || m.Source() != "PAS|RWM" {
+		t.Fatalf("incorrect header: %s %s", m.ControlID(), m.Source())
+	}
+	if m.Value("MSH", 2, 1) != "^~\\&" || m.Value("PID", 5, 5) != "MRS" || m.Value("PID", 3, 1) != "9990000123" {
+		t.Fatalf("incorrect values: %v", m.Segments)
+	}
+	if address := m.Value("PID", 11, 1); address != "1 SYNTHETIC ST^FLAT 2" {
+		t.Fatalf("escape sequences not decoded: %s", address)
+	}
+	if !bytes.Equal(m.Encode(), []byte(adtA08)) {
+		t.Fatalf("message not encoded as parsed: %q", m.Encode())
+	}
+	if _, err := Parse([]byte("PID|1||12345")); err == nil {
+		t.Fatal("expected error for message without header")
+	}
+	if s := DefaultDelimiters.Encode("A|B^C\\D"); DefaultDelimiters.Decode(s) != "A|B^C\\D" {
+		t.Fatalf("escaped value does not round-trip: %s", s)
+	}
+}
+
+func TestAck(t *testing.T) {
+	m, _ := Parse([]byte(adtA08))
+	ack := NewAck(m, AckError, "unknown patient|identifier")
+	if msgType, event := ack.Type(); msgType != "ACK" || event != "A08" {
+		t.Fatalf("incorrect acknowledgement type: %s^%s", msgType, event)
+	}
+	if ack.Source() != "CONCIERGE|CYMRU" || ack.Value("MSH", 5, 1) != "PAS" || ack.Value("MSH", 12, 1) != "2.5" {
+		t.Fatalf("acknowledgement not addressed to sender: %q", ack.Encode())
+	}
+	parsed, err := Parse(ack.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Value("MSA", 1, 1) != AckError || parsed.Value("MSA", 2, 1) != "MSG00001" || parsed.Value("MSA", 3, 1) != "unknown patient|identifier" {
+		t.Fatalf("incorrect acknowledgement: %q", ack.Encode())
+	}
+	if ack := NewAck(nil, AckError, "invalid"); ack.Value("MSA", 1, 1) != AckError {
+		t.Fatalf("incorrect acknowledgement of invalid message: %q", ack.Encode())
+	}
+}
+
+func TestReadMessage(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("noise")
+	WriteMessage(&buf, []byte("MSH|^~\\&|A\r"))
+	WriteMessage(&buf, []byte("MSH|^~\\&|B\r"))
+	r := bufio.NewReader(&buf)
+	for _, expected := range []string{"A", "B"} {
+		b, err := ReadMessage(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(string(b), expected+"\r") {
+			t.Fatalf("expected message %s, got: %q", expected, b)
+		}
+	}
+	if _, err := ReadMessage(r); err == nil {
+		t.Fatal("expected error at end of stream")
+	}
+}
+
+func TestServer(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := make(chan string, 1)
+	srv := &Server{Handler: HandlerFunc(func(ctx context.Context, msg *Message) error {
+		if _, event := msg.Type(); event != "A08" {
+			return status.Errorf(codes.Unimplemented, "unsupported event: %s", event)
+		}
+		received <- msg.ControlID()
+		return nil
+	})}
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(lis) }()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ack, err := Send(ctx, lis.Addr().String(), []byte(adtA08))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ack.Value("MSA", 1, 1) != AckAccept || <-received != "MSG00001" {
+		t.Fatalf("message not accepted: %q", ack.Encode())
+	}
+	ack, err = Send(ctx, lis.Addr().String(), []byte(strings.Replace(adtA08, "ADT^A08", "ADT^A99", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ack.Value("MSA", 1, 1) != AckError || !strings.Contains(ack.Value("MSA", 3, 1), "A99") {
+		t.Fatalf("unsupported message not rejected: %q", ack.Encode())
+	}
+	srv.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error on close: %s", err)
+	}
+}
@@ -0,0 +1,226 @@
+// Package hl7 provides parsing of HL7 v2 messages in the traditional pipe-delimited encoding (ER7), acknowledgement
+// of messages, and the minimal lower layer protocol (MLLP) used to exchange messages over TCP.
+package hl7
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Acknowledgement codes (HL7 table 0008), in original acknowledgement mode
+const (
+	AckAccept = "AA" // message accepted
+	AckError  = "AE" // message rejected because of an error in its content; it should not be sent again unchanged
+	AckReject = "AR" // message rejected for reasons unrelated to its content, such as a system being unavailable
+)
+
+// Delimiters are the characters that delimit the parts of a message, defined in the message header
+type Delimiters struct {
+	Field        byte
+	Component    byte
+	Repetition   byte
+	Escape       byte
+	Subcomponent byte
+}
+
+// DefaultDelimiters are the delimiters recommended by the standard, '|^~\&'
+var DefaultDelimiters = Delimiters{Field: '|', Component: '^', Repetition: '~', Escape: '\\', Subcomponent: '&'}
+
+// Segment is a segment of a message, as its name followed by its fields, so that field n is at index n.
+// For the message header (MSH), field 1 is the field separator itself, as in the standard.
+type Segment []string
+
+// Name returns the name of the segment, such as "PID"
+func (s Segment) Name() string {
+	return s[0]
+}
+
+// Field returns the field specified, still encoded, or an empty string if the field is not present
+func (s Segment) Field(n int) string {
+	if n <= 0 || n >= len(s) {
+		return ""
+	}
+	return s[n]
+}
+
+// Message is a HL7 v2 message
+type Message struct {
+	Delimiters Delimiters
+	Segments   []Segment
+}
+
+// Parse parses a message in ER7 encoding, in which segments are separated by carriage returns.
+// The message must start with a message header (MSH) segment, which defines the delimiters used.
+func Parse(b []byte) (*Message, error) {
+	b = bytes.TrimSpace(b)
+	if len(b) < 8 || !bytes.HasPrefix(b, []byte("MSH")) {
+		return nil, fmt.Errorf("hl7: message does not start with a message header (MSH)")
+	}
+	d := Delimiters{Field: b[3], Component: b[4], Repetition: b[5], Escape: b[6], Subcomponent: b[7]}
+	m := &Message{Delimiters: d}
+	lines := strings.FieldsFunc(string(b), func(r rune) bool { return r == '\r' || r == '\n' })
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, string(d.Field))
+		if len(fields[0]) != 3 {
+			return nil, fmt.Errorf("hl7: invalid segment: '%s'", line)
+		}
+		if fields[0] == "MSH" {
+			fields = append([]string{"MSH", string(d.Field)}, fields[1:]...)
+		}
+		m.Segments = append(m.Segments, Segment(fields))
+	}
+	return m, nil
+}
+
+// Segment returns the first segment with the name specified, or nil
+func (m *Message) Segment(name string) Segment {
+	for _, s := range m.Segments {
+		if s.Name() == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// Value returns the decoded value of the component specified of the first repetition of a field of the first
+// segment with the name specified, or an empty string. Components are numbered from one.
+// The field separator and encoding characters (MSH-1 and MSH-2) are returned unchanged.
+func (m *Message) Value(segment string, field int, component int) string {
+	f := m.Segment(segment).Field(field)
+	if segment == "MSH" && field <= 2 { // the delimiters themselves
+		return f
+	}
+	if i := strings.IndexByte(f, m.Delimiters.Repetition); i >= 0 {
+		f = f[:i]
+	}
+	components := strings.Split(f, string(m.Delimiters.Component))
+	if component <= 0 || component > len(components) {
+		return ""
+	}
+	return m.Delimiters.Decode(components[component-1])
+}
+
+// Type returns the message type and trigger event of the message, such as "ADT" and "A08"
+func (m *Message) Type() (string, string) {
+	return m.Value("MSH", 9, 1), m.Value("MSH", 9, 2)
+}
+
+// ControlID returns the message control identifier, which is returned in any acknowledgement
+func (m *Message) ControlID() string {
+	return m.Value("MSH", 10, 1)
+}
+
+// Source returns the sending application and facility, as application|facility
+func (m *Message) Source() string {
+	return m.Value("MSH", 3, 1) + "|" + m.Value("MSH", 4, 1)
+}
+
+// Encode returns the message in ER7 encoding
+func (m *Message) Encode() []byte {
+	var buf bytes.Buffer
+	for _, s := range m.Segments {
+		fields := []string(s)
+		if s.Name() == "MSH" && len(fields) > 1 {
+			fields = append([]string{"MSH"}, fields[2:]...)
+		}
+		buf.WriteString(strings.Join(fields, string(m.Delimiters.Field)))
+		buf.WriteByte('\r')
+	}
+	return buf.Bytes()
+}
+
+// Decode decodes the escape sequences for the delimiters in a value
+func (d Delimiters) Decode(s string) string {
+	if strings.IndexByte(s, d.Escape) < 0 {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != d.Escape {
+			sb.WriteByte(s[i])
+			continue
+		}
+		end := strings.IndexByte(s[i+1:], d.Escape)
+		if end < 0 {
+			sb.WriteString(s[i:])
+			break
+		}
+		switch seq := s[i+1 : i+1+end]; seq {
+		case "F":
+			sb.WriteByte(d.Field)
+		case "S":
+			sb.WriteByte(d.Component)
+		case "R":
+			sb.WriteByte(d.Repetition)
+		case "T":
+			sb.WriteByte(d.Subcomponent)
+		case "E":
+			sb.WriteByte(d.Escape)
+		case ".br":
+			sb.WriteByte('\n')
+		default: // other escape sequences, such as highlighting and hexadecimal data, are dropped
+		}
+		i += end + 1
+	}
+	return sb.String()
+}
+
+// Encode encodes any delimiters in a value
+func (d Delimiters) Encode(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case d.Field:
+			sb.WriteString(string(d.Escape) + "F" + string(d.Escape))
+		case d.Component:
+			sb.WriteString(string(d.Escape) + "S" + string(d.Escape))
+		case d.Repetition:
+			sb.WriteString(string(d.Escape) + "R" + string(d.Escape))
+		case d.Subcomponent:
+			sb.WriteString(string(d.Escape) + "T" + string(d.Escape))
+		case d.Escape:
+			sb.WriteString(string(d.Escape) + "E" + string(d.Escape))
+		case '\r', '\n':
+			sb.WriteString(string(d.Escape) + ".br" + string(d.Escape))
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// NewAck creates an acknowledgement (ACK) of the message specified, which may be nil if the message could not be
+// parsed, with the acknowledgement code specified and optional text describing any error.
+func NewAck(m *Message, code string, text string) *Message {
+	d := DefaultDelimiters
+	msh := Segment{"MSH", string(d.Field), string([]byte{d.Component, d.Repetition, d.Escape, d.Subcomponent})}
+	var controlID, event, processingID, version string
+	if m != nil {
+		d = m.Delimiters
+		msh[1], msh[2] = string(d.Field), m.Segment("MSH").Field(2)
+		hdr := m.Segment("MSH")
+		msh = append(msh, hdr.Field(5), hdr.Field(6), hdr.Field(3), hdr.Field(4)) // reply to the sender
+		controlID, processingID, version = hdr.Field(10), hdr.Field(11), hdr.Field(12)
+		_, event = m.Type()
+	} else {
+		msh = append(msh, "", "", "", "")
+	}
+	if processingID == "" {
+		processingID = "P"
+	}
+	if version == "" {
+		version = "2.5"
+	}
+	now := time.Now()
+	msh = append(msh, now.Format("20060102150405"), "",
+		strings.Join([]string{"ACK", d.Encode(event), "ACK"}, string(d.Component)),
+		strconv.FormatInt(now.UnixNano(), 10), processingID, version)
+	msa := Segment{"MSA", code, controlID, d.Encode(text)}
+	return &Message{Delimiters: d, Segments: []Segment{msh, msa}}
+}
@@ -0,0 +1,238 @@
+package hl7
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MLLP frames each message with a start block character, and an end block character followed by a carriage return
+const (
+	startBlock = 0x0b
+	endBlock   = 0x1c
+	cr         = 0x0d
+)
+
+// MaxMessageSize is the largest message that will be read
+const MaxMessageSize = 1 << 20
+
+// ReadMessage reads the next framed message, discarding any data before the start of the message
+func ReadMessage(r *bufio.Reader) ([]byte, error) {
+	if _, err := r.ReadBytes(startBlock); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		if b == endBlock {
+			if next, err := r.Peek(1); err == nil && next[0] == cr {
+				r.ReadByte()
+			}
+			return buf.Bytes(), nil
+		}
+		if buf.Len() >= MaxMessageSize {
+			return nil, fmt.Errorf("hl7: message exceeds maximum size of %d bytes", MaxMessageSize)
+		}
+		buf.WriteByte(b)
+	}
+}
+
+// WriteMessage writes a framed message
+func WriteMessage(w io.Writer, msg []byte) error {
+	b := make([]byte, 0, len(msg)+3)
+	b = append(b, startBlock)
+	b = append(b, msg...)
+	b = append(b, endBlock, cr)
+	_, err := w.Write(b)
+	return err
+}
+
+// Handler processes messages received by a server. A message is acknowledged as accepted (AA) unless the handler
+// returns an error: an error with the code InvalidArgument or Unimplemented is acknowledged as an error in the
+// content of the message (AE), and any other error as a rejection (AR), so that the message can be sent again.
+type Handler interface {
+	ServeHL7(ctx context.Context, msg *Message) error
+}
+
+// HandlerFunc is a function that can be used as a handler
+type HandlerFunc func(ctx context.Context, msg *Message) error
+
+// ServeHL7 calls f(ctx, msg)
+func (f HandlerFunc) ServeHL7(ctx context.Context, msg *Message) error {
+	return f(ctx, msg)
+}
+
+// Server receives messages over TCP using MLLP, passing each to its handler and replying with an acknowledgement.
+// Messages on a connection are processed in order.
+type Server struct {
+	Handler     Handler
+	IdleTimeout time.Duration // time after which an idle connection is closed, or zero for no timeout
+	Allowed     []*net.IPNet  // networks from which connections are accepted, or any network if empty
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closed   bool
+}
+
+// ListenAndServe listens on the TCP address specified and serves connections until closed
+func (srv *Server) ListenAndServe(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return srv.Serve(lis)
+}
+
+// Serve serves connections from the listener until closed, returning nil once closed
+func (srv *Server) Serve(lis net.Listener) error {
+	srv.mu.Lock()
+	if srv.closed {
+		srv.mu.Unlock()
+		lis.Close()
+		return nil
+	}
+	srv.listener = lis
+	srv.conns = make(map[net.Conn]struct{})
+	srv.mu.Unlock()
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			srv.mu.Lock()
+			closed := srv.closed
+			srv.mu.Unlock()
+			if closed {
+				return nil
+			}
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+		if !srv.allowed(conn.RemoteAddr()) {
+			log.Printf("hl7: rejecting connection from %s: source not allowed", conn.RemoteAddr())
+			conn.Close()
+			continue
+		}
+		srv.mu.Lock()
+		srv.conns[conn] = struct{}{}
+		srv.mu.Unlock()
+		go srv.serveConn(conn)
+	}
+}
+
+// allowed returns whether connections are accepted from the address specified
+func (srv *Server) allowed(addr net.Addr) bool {
+	if len(srv.Allowed) == 0 {
+		return true
+	}
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	for _, n := range srv.Allowed {
+		if n.Contains(tcp.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// Close stops listening and closes all connections
+func (srv *Server) Close() error {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.closed = true
+	for conn := range srv.conns {
+		conn.Close()
+	}
+	if srv.listener != nil {
+		return srv.listener.Close()
+	}
+	return nil
+}
+
+func (srv *Server) serveConn(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		srv.mu.Lock()
+		delete(srv.conns, conn)
+		srv.mu.Unlock()
+	}()
+	r := bufio.NewReader(conn)
+	for {
+		if srv.IdleTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(srv.IdleTimeout))
+		}
+		b, err := ReadMessage(r)
+		if err != nil {
+			if err != io.EOF {
+				log.Printf("hl7: closing connection from %s: %s", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		ack := srv.handle(b)
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		if err := WriteMessage(conn, ack.Encode()); err != nil {
+			log.Printf("hl7: failed to acknowledge message from %s: %s", conn.RemoteAddr(), err)
+			return
+		}
+	}
+}
+
+// handle processes a message, returning its acknowledgement
+func (srv *Server) handle(b []byte) *Message {
+	msg, err := Parse(b)
+	if err != nil {
+		log.Printf("hl7: invalid message: %s", err)
+		return NewAck(nil, AckError, err.Error())
+	}
+	if err := srv.Handler.ServeHL7(context.Background(), msg); err != nil {
+		msgType, event := msg.Type()
+		log.Printf("hl7: failed to process message %s (%s^%s) from %s: %s", msg.ControlID(), msgType, event, msg.Source(), err)
+		switch status.Code(err) {
+		case codes.InvalidArgument, codes.Unimplemented:
+			return NewAck(msg, AckError, status.Convert(err).Message())
+		default:
+			return NewAck(msg, AckReject, status.Convert(err).Message())
+		}
+	}
+	return NewAck(msg, AckAccept, "")
+}
+
+// Send sends a message to the TCP address specified using MLLP, returning its acknowledgement
+func Send(ctx context.Context, addr string, msg []byte) (*Message, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if err := WriteMessage(conn, msg); err != nil {
+		return nil, err
+	}
+	b, err := ReadMessage(bufio.NewReader(conn))
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
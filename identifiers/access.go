@@ -0,0 +1,180 @@
+package identifiers
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// JustificationHeader is the request metadata (or HTTP header) in which a caller gives the reason for accessing
+// a record that requires a justification ('break-glass' access)
+const JustificationHeader = "x-access-justification"
+
+// AccessPolicy decides whether access to a resolved identifier requires an explicit justification, returning
+// the reason if it does. For example, a policy may restrict access to patients registered with another health board.
+type AccessPolicy func(ctx context.Context, id *apiv1.Identifier, o proto.Message) (restricted bool, reason string)
+
+// BreakGlass records an access to a restricted record, for which the caller gave a justification
+type BreakGlass struct {
+	Identifier    *apiv1.Identifier
+	Policy        string // name of the policy restricting access
+	Reason        string // why the policy restricts access
+	Justification string // the caller's justification
+}
+
+type namedPolicy struct {
+	name   string
+	policy AccessPolicy
+}
+
+var (
+	policiesMu sync.RWMutex
+	policies   []namedPolicy
+	recorder   func(ctx context.Context, bg *BreakGlass) error
+)
+
+// RegisterAccessPolicy registers a policy checked after each identifier is resolved.
+// Access restricted by a policy is denied unless the caller gives a justification.
+func RegisterAccessPolicy(name string, policy AccessPolicy) {
+	policiesMu.Lock()
+	defer policiesMu.Unlock()
+	policies = append(policies, namedPolicy{name: name, policy: policy})
+	log.Printf("identifiers: registered access policy: %s", name)
+}
+
+// UnregisterAccessPolicy removes the access policy registered with the name specified, such as at the end of a test
+func UnregisterAccessPolicy(name string) {
+	policiesMu.Lock()
+	defer policiesMu.Unlock()
+	result := make([]namedPolicy, 0, len(policies))
+	for _, p := range policies {
+		if p.name != name {
+			result = append(result, p)
+		}
+	}
+	policies = result // replaced rather than modified, as readers may hold the previous policies
+	log.Printf("identifiers: unregistered access policy: %s", name)
+}
+
+// RegisterBreakGlassRecorder registers the function used to record access to restricted records.
+// If recording fails, access is denied.
+func RegisterBreakGlassRecorder(f func(ctx context.Context, bg *BreakGlass) error) {
+	policiesMu.Lock()
+	defer policiesMu.Unlock()
+	recorder = f
+}
+
+// Justification returns the justification given by the caller for access to restricted records, if any
+func Justification(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return strings.TrimSpace(strings.Join(md.Get(JustificationHeader), " "))
+}
+
+// NamespacePolicy returns an access policy that restricts access to all identifiers in the specified namespaces
+func NamespacePolicy(uris ...string) AccessPolicy {
+	restricted := make(map[string]struct{}, len(uris))
+	for _, uri := range uris {
+		restricted[uri] = struct{}{}
+	}
+	return func(ctx context.Context, id *apiv1.Identifier, o proto.Message) (bool, string) {
+		if _, found := restricted[id.GetSystem()]; found {
+			return true, "restricted namespace: " + id.GetSystem()
+		}
+		return false, ""
+	}
+}
+
+// checkAccess checks the registered access policies for a resolved identifier, denying access to a restricted
+// record unless the caller has given a justification, which is recorded
+func checkAccess(ctx context.Context, id *apiv1.Identifier, o proto.Message) error {
+	policiesMu.RLock()
+	ps := policies
+	policiesMu.RUnlock()
+	for _, p := range ps {
+		if restricted, reason := p.policy(ctx, id, o); restricted {
+			return breakGlass(ctx, id, p.name, reason, true)
+		}
+	}
+	return nil
+}
+
+// CheckAccess checks that the caller may access a record found other than by resolving one of its identifiers,
+// such as a patient found by a demographic search. The caller must have a relationship with the patient, using any
+// of the record's identifiers in a namespace with a relationship policy, and access to a record restricted by an
+// access policy requires a justification, as for Resolve.
+func CheckAccess(ctx context.Context, o proto.Message) error {
+	r, ok := o.(interface{ GetIdentifiers() []*apiv1.Identifier })
+	if !ok {
+		return nil
+	}
+	if err := checkRelationships(ctx, r.GetIdentifiers()); err != nil {
+		return err
+	}
+	for _, id := range r.GetIdentifiers() {
+		if err := checkAccess(ctx, id, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// breakGlass records access to a restricted record. If a justification is required, access is denied unless
+// the caller has given one. Access is also denied if it cannot be recorded.
+func breakGlass(ctx context.Context, id *apiv1.Identifier, policy string, reason string, required bool) error {
+	policiesMu.RLock()
+	rec := recorder
+	policiesMu.RUnlock()
+	justification := Justification(ctx)
+	if justification == "" && required {
+		log.Printf("identifiers: access to '%s|%s' denied: %s: no justification", id.GetSystem(), id.GetValue(), reason)
+		return status.Errorf(codes.PermissionDenied, "access to '%s|%s' requires a justification (%s): give a reason using '%s'", id.GetSystem(), id.GetValue(), reason, JustificationHeader)
+	}
+	if rec == nil {
+		if !required {
+			log.Printf("identifiers: flagged access to '%s|%s' (%s): justification: '%s'", id.GetSystem(), id.GetValue(), reason, justification)
+			return nil
+		}
+		log.Printf("identifiers: access to '%s|%s' denied: no break-glass recorder registered", id.GetSystem(), id.GetValue())
+		return status.Errorf(codes.PermissionDenied, "access to '%s|%s' requires a justification, but access cannot be recorded", id.GetSystem(), id.GetValue())
+	}
+	bg := &BreakGlass{Identifier: id, Policy: policy, Reason: reason, Justification: justification}
+	if err := rec(ctx, bg); err != nil {
+		log.Printf("identifiers: access to '%s|%s' denied: failed to record break-glass access: %s", id.GetSystem(), id.GetValue(), err)
+		return status.Errorf(codes.Unavailable, "access to '%s|%s' cannot be recorded", id.GetSystem(), id.GetValue())
+	}
+	log.Printf("identifiers: break-glass access to '%s|%s' (%s): justification: '%s'", id.GetSystem(), id.GetValue(), reason, justification)
+	return nil
+}
+
+// RecordPolicy returns an access policy that restricts access to specific records, such as those of patients
+// with restricted records. A record is restricted if it is resolved from, or has, any of the identifiers specified.
+func RecordPolicy(restricted ...*apiv1.Identifier) AccessPolicy {
+	records := make(map[string]struct{}, len(restricted))
+	for _, id := range restricted {
+		records[id.GetSystem()+"|"+id.GetValue()] = struct{}{}
+	}
+	isRestricted := func(id *apiv1.Identifier) bool {
+		_, found := records[id.GetSystem()+"|"+id.GetValue()]
+		return found
+	}
+	return func(ctx context.Context, id *apiv1.Identifier, o proto.Message) (bool, string) {
+		if isRestricted(id) {
+			return true, "restricted record"
+		}
+		if r, ok := o.(interface{ GetIdentifiers() []*apiv1.Identifier }); ok {
+			for _, other := range r.GetIdentifiers() {
+				if isRestricted(other) {
+					return true, "restricted record"
+				}
+			}
+		}
+		return false, ""
+	}
+}
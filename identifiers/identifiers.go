@@ -20,7 +20,7 @@ import (
 
 var (
 	systemsMu   sync.RWMutex
-	systems     = make(map[string]apiv1.System)
+	systems     = make(map[string]*apiv1.System)
 	resolversMu sync.RWMutex
 	resolvers   = make(map[string]func(ctx context.Context, id *apiv1.Identifier) (proto.Message, error))
 	mappersMu   sync.RWMutex
@@ -40,7 +40,7 @@ var ErrNotFound = errors.New("identifier not found")
 func Register(name string, uri string) {
 	systemsMu.Lock()
 	defer systemsMu.Unlock()
-	systems[uri] = apiv1.System{Name: name, Uri: uri}
+	systems[uri] = &apiv1.System{Name: name, Uri: uri}
 }
 
 // RegisterResolver registers a handler to resolve the value for the system/identifier tuple
@@ -53,15 +53,46 @@ func RegisterResolver(uri string, f func(ctx context.Context, id *apiv1.Identifi
 	resolvers[uri] = f
 }
 
-// Resolve attempts to resolve the specified system/value tuple
+// UnregisterResolver removes the handler registered for the specified URI, such as at the end of a test
+func UnregisterResolver(uri string) {
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+	delete(resolvers, uri)
+}
+
+// Resolve attempts to resolve the specified system/value tuple.
+// An identifier that has been superseded, such as when patient records are merged, is resolved as the surviving
+// identifier, and the response flagged as superseded.
+// The caller must have a relationship with the patient identified, if the namespace has a relationship policy,
+// and access to a record restricted by an access policy is denied unless the caller gives a justification.
 func Resolve(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
+	current, chain, err := Current(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) > 0 {
+		log.Printf("identifiers: resolving '%s|%s' as '%s|%s': superseded", id.GetSystem(), id.GetValue(), current.GetSystem(), current.GetValue())
+	}
 	resolversMu.RLock()
-	resolver, ok := resolvers[id.GetSystem()]
+	resolver, ok := resolvers[current.GetSystem()]
 	resolversMu.RUnlock()
 	if !ok {
-		return nil, status.Errorf(codes.NotFound, "unable to resolve '%s|%s': %w", id.GetSystem(), id.GetValue(), ErrNoResolver)
+		return nil, status.Errorf(codes.NotFound, "unable to resolve '%s|%s': %s", current.GetSystem(), current.GetValue(), ErrNoResolver)
 	}
-	return resolver(ctx, id)
+	o, err := resolveWithRelationship(ctx, current, resolver)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAccess(ctx, current, o); err != nil {
+		return nil, err
+	}
+	if len(chain) > 0 {
+		if err := checkAccess(ctx, id, o); err != nil {
+			return nil, err
+		}
+		setSuperseded(ctx, current)
+	}
+	return o, nil
 }
 
 type mapKey struct {
@@ -138,6 +169,19 @@ func (svc *Server) MapIdentifier(r *apiv1.IdentifierMapRequest, stream apiv1.Ide
 	})
 }
 
+// GetMergeHistory returns the history of supersessions of identifiers for the record with the identifier specified.
+// The record is resolved, so that the history is only returned to callers who may access the record, as for
+// GetIdentifier.
+func (svc *Server) GetMergeHistory(ctx context.Context, id *apiv1.Identifier) (*apiv1.MergeHistory, error) {
+	if id.GetSystem() == "" || id.GetValue() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "identifier: missing parameter: system or value")
+	}
+	if _, err := Resolve(ctx, id); err != nil {
+		return nil, err
+	}
+	return MergeHistory(ctx, id)
+}
+
 // Map attempts to map an identifier from one code system to another
 func Map(ctx context.Context, id *apiv1.Identifier, uri string, f func(*apiv1.Identifier) error) error {
 	if id.System == uri {
@@ -193,7 +237,7 @@ func Lookup(uri string) (*apiv1.System, bool) {
 	systemsMu.RLock()
 	defer systemsMu.RUnlock()
 	val, ok := systems[uri]
-	return &val, ok
+	return val, ok
 }
 
 func init() {
@@ -51,8 +51,15 @@ const (
 	// Specific FHIR value sets
 	CompositionStatus = "http://hl7.org/fhir/composition-status" // see https://www.hl7.org/fhir/valueset-composition-status.html
 
+	// HL7 v2 tables
+	HL7v2MaritalStatus = "http://terminology.hl7.org/CodeSystem/v2-0002"
+	HL7v2Relationship  = "http://terminology.hl7.org/CodeSystem/v2-0063"
+	HL7v2ContactRole   = "http://terminology.hl7.org/CodeSystem/v2-0131"
+	HL7v2Language      = "http://terminology.hl7.org/CodeSystem/v2-0296"
+
 	// Concierge service user
 	ConciergeServiceUser    = "https://concierge.eldrix.com/Id/service-user"
+	ConciergeAPIKey         = "https://concierge.eldrix.com/Id/api-key"
 	ConciergeDocumentStatus = "https://concierge.eldrix.com/Id/document-status"
 	PatientCare             = "https://patientcare.eldrix.com/Id/patientcare-application"
 )
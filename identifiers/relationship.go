@@ -0,0 +1,156 @@
+package identifiers
+
+import (
+	"context"
+	"log"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// RelationshipPolicy checks, before an identifier is resolved, that the caller has a legitimate relationship with
+// the patient identified, such as a clinic booking, referral or team membership. It returns the basis for the
+// relationship, or an empty string if there is none.
+type RelationshipPolicy func(ctx context.Context, id *apiv1.Identifier) (basis string, err error)
+
+// RelationshipMode determines what happens when a caller has no relationship with a patient
+type RelationshipMode int
+
+// Relationship modes
+const (
+	DenyWithoutRelationship RelationshipMode = iota // deny access unless the caller gives a justification
+	FlagWithoutRelationship                         // permit access, but record it for review
+)
+
+func (m RelationshipMode) String() string {
+	if m == FlagWithoutRelationship {
+		return "flag"
+	}
+	return "deny"
+}
+
+type modalPolicy struct {
+	mode   RelationshipMode
+	policy RelationshipPolicy
+}
+
+var relationships = make(map[string]modalPolicy) // relationship policies by namespace, protected by policiesMu
+
+// RegisterRelationshipPolicy registers a policy checked before resolving identifiers in the namespaces specified,
+// such as identifiers.NHSNumber. Access without a relationship is denied unless a justification is given, or is
+// permitted but flagged, depending on the mode, and recorded as a break-glass access.
+func RegisterRelationshipPolicy(mode RelationshipMode, policy RelationshipPolicy, uris ...string) {
+	policiesMu.Lock()
+	defer policiesMu.Unlock()
+	for _, uri := range uris {
+		relationships[uri] = modalPolicy{mode: mode, policy: policy}
+		log.Printf("identifiers: registered relationship policy for '%s' (%s)", uri, mode)
+	}
+}
+
+// resolveWithRelationship resolves the identifier, checking the relationship policy, if any, for its namespace.
+// A relationship may be held using any of the patient's identifiers, such as a NHS number rather than a case
+// record number, so if the caller has no relationship using the identifier itself, the record is resolved and its
+// other identifiers checked before access is denied or flagged. Access is denied, rather than the resolver's error
+// returned, so that callers without a relationship cannot find out whether a patient exists.
+// Identifiers in a namespace without a policy, such as a case record number of another organisation, are resolved
+// and the policies for the record's other identifiers checked, so that these cannot be used to bypass the policies.
+func resolveWithRelationship(ctx context.Context, id *apiv1.Identifier, resolver func(context.Context, *apiv1.Identifier) (proto.Message, error)) (proto.Message, error) {
+	policiesMu.RLock()
+	p, found := relationships[id.GetSystem()]
+	policiesMu.RUnlock()
+	if !found {
+		o, err := resolver(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if r, ok := o.(interface{ GetIdentifiers() []*apiv1.Identifier }); ok {
+			if err := checkRelationships(ctx, r.GetIdentifiers()); err != nil {
+				return nil, err
+			}
+		}
+		return o, nil
+	}
+	basis, err := p.check(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if basis != "" {
+		log.Printf("identifiers: access to '%s|%s': relationship: %s", id.GetSystem(), id.GetValue(), basis)
+		return resolver(ctx, id)
+	}
+	o, resolveErr := resolver(ctx, id)
+	if r, ok := o.(interface{ GetIdentifiers() []*apiv1.Identifier }); ok && resolveErr == nil {
+		for _, other := range r.GetIdentifiers() {
+			if sameIdentifier(other, id) {
+				continue
+			}
+			basis, err := p.check(ctx, other)
+			if err != nil {
+				return nil, err
+			}
+			if basis != "" {
+				log.Printf("identifiers: access to '%s|%s': relationship using '%s|%s': %s", id.GetSystem(), id.GetValue(), other.GetSystem(), other.GetValue(), basis)
+				return o, nil
+			}
+		}
+	}
+	if err := breakGlass(ctx, id, "relationship", "no legitimate relationship", p.mode == DenyWithoutRelationship); err != nil {
+		return nil, err
+	}
+	return o, resolveErr
+}
+
+// checkRelationships checks the relationship policies for a record with the identifiers specified. Access is
+// permitted if the caller has a relationship using any of the identifiers, or if none are in a namespace with a
+// relationship policy.
+func checkRelationships(ctx context.Context, ids []*apiv1.Identifier) error {
+	var restricted *apiv1.Identifier
+	var mode RelationshipMode
+	for _, id := range ids {
+		policiesMu.RLock()
+		p, found := relationships[id.GetSystem()]
+		policiesMu.RUnlock()
+		if !found {
+			continue
+		}
+		basis, err := p.check(ctx, id)
+		if err != nil {
+			return err
+		}
+		if basis != "" {
+			log.Printf("identifiers: access to '%s|%s': relationship: %s", id.GetSystem(), id.GetValue(), basis)
+			return nil
+		}
+		if restricted == nil {
+			restricted, mode = id, p.mode
+		}
+	}
+	if restricted == nil {
+		return nil
+	}
+	return breakGlass(ctx, restricted, "relationship", "no legitimate relationship", mode == DenyWithoutRelationship)
+}
+
+// check returns the basis for the caller's relationship with the patient identified, or an empty string if there
+// is none. If the relationship cannot be checked, such as when a backend service is unavailable, access is permitted
+// but recorded for review in flag mode, and is otherwise denied.
+func (p modalPolicy) check(ctx context.Context, id *apiv1.Identifier) (string, error) {
+	basis, err := p.policy(ctx, id)
+	if err == nil {
+		return basis, nil
+	}
+	if status.Code(err) == codes.Unauthenticated {
+		return "", err
+	}
+	log.Printf("identifiers: failed to check relationship with '%s|%s': %s", id.GetSystem(), id.GetValue(), err)
+	if p.mode == FlagWithoutRelationship {
+		if err := breakGlass(ctx, id, "relationship", "relationship could not be checked: "+err.Error(), false); err != nil {
+			return "", err
+		}
+		return "not checked: flagged for review", nil
+	}
+	return "", status.Errorf(codes.Unavailable, "unable to check relationship with '%s|%s': %s", id.GetSystem(), id.GetValue(), err)
+}
@@ -0,0 +1,158 @@
+package identifiers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	testNHS = "https://concierge.test/Id/nhs-number"
+	testCRN = "https://concierge.test/Id/crn"
+	testPAS = "https://concierge.test/Id/pas-identifier" // a secondary namespace without a relationship policy
+)
+
+// registerTestRelationships registers resolvers for test patients, each with a NHS number, a CRN and a secondary
+// identifier, a relationship policy for the NHS number and CRN namespaces and a break-glass recorder, returning
+// the accesses recorded
+func registerTestRelationships(t *testing.T, mode RelationshipMode, policy RelationshipPolicy) *[]*BreakGlass {
+	patients := map[string]*apiv1.Patient{
+		"1111111111": {Lastname: "DUMMY", Identifiers: []*apiv1.Identifier{{System: testNHS, Value: "1111111111"}, {System: testCRN, Value: "A999998"}, {System: testPAS, Value: "P100001"}}},
+		"2222222222": {Lastname: "TEST", Identifiers: []*apiv1.Identifier{{System: testNHS, Value: "2222222222"}, {System: testCRN, Value: "A999997"}, {System: testPAS, Value: "P100002"}}},
+	}
+	resolve := func(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
+		for _, pt := range patients {
+			for _, other := range pt.GetIdentifiers() {
+				if sameIdentifier(id, other) {
+					return pt, nil
+				}
+			}
+		}
+		return nil, status.Errorf(codes.NotFound, "patient not found")
+	}
+	RegisterResolver(testNHS, resolve)
+	RegisterResolver(testCRN, resolve)
+	RegisterResolver(testPAS, resolve)
+	RegisterRelationshipPolicy(mode, policy, testNHS, testCRN)
+	var recorded []*BreakGlass
+	RegisterBreakGlassRecorder(func(ctx context.Context, bg *BreakGlass) error {
+		recorded = append(recorded, bg)
+		return nil
+	})
+	t.Cleanup(func() {
+		UnregisterResolver(testNHS)
+		UnregisterResolver(testCRN)
+		UnregisterResolver(testPAS)
+		policiesMu.Lock()
+		delete(relationships, testNHS)
+		delete(relationships, testCRN)
+		policiesMu.Unlock()
+		RegisterBreakGlassRecorder(nil)
+	})
+	return &recorded
+}
+
+// referral is a relationship policy for a user referred only the patient with NHS number 1111111111
+func referral(ctx context.Context, id *apiv1.Identifier) (string, error) {
+	if id.GetSystem() == testNHS && id.GetValue() == "1111111111" {
+		return "referral", nil
+	}
+	return "", nil
+}
+
+func TestRelationshipDeny(t *testing.T) {
+	recorded := registerTestRelationships(t, DenyWithoutRelationship, referral)
+	ctx := context.Background()
+	justified := metadata.NewIncomingContext(ctx, metadata.Pairs(JustificationHeader, "patient in emergency department"))
+
+	// a relationship using one identifier permits access using the patient's other identifiers
+	for _, id := range []*apiv1.Identifier{{System: testNHS, Value: "1111111111"}, {System: testCRN, Value: "A999998"}} {
+		if _, err := Resolve(ctx, id); err != nil {
+			t.Fatalf("access to %v with relationship denied: %s", id, err)
+		}
+	}
+	if len(*recorded) != 0 {
+		t.Fatalf("access with relationship recorded: %v", *recorded)
+	}
+	// access without a relationship is denied unless justified
+	for _, id := range []*apiv1.Identifier{{System: testNHS, Value: "2222222222"}, {System: testCRN, Value: "A999997"}} {
+		if _, err := Resolve(ctx, id); status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("access to %v without relationship: expected permission denied, got: %v", id, err)
+		}
+		if _, err := Resolve(justified, id); err != nil {
+			t.Fatalf("justified access to %v denied: %s", id, err)
+		}
+	}
+	if len(*recorded) != 2 || (*recorded)[0].Policy != "relationship" {
+		t.Fatalf("expected two recorded accesses, got: %v", *recorded)
+	}
+	// and callers without a relationship cannot tell whether a patient exists
+	if _, err := Resolve(ctx, &apiv1.Identifier{System: testNHS, Value: "3333333333"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("access to unknown patient without relationship: expected permission denied, got: %v", err)
+	}
+}
+
+// TestRelationshipSecondaryNamespace checks that identifiers in a namespace without a relationship policy
+// cannot be used to resolve patients without a relationship
+func TestRelationshipSecondaryNamespace(t *testing.T) {
+	recorded := registerTestRelationships(t, DenyWithoutRelationship, referral)
+	ctx := context.Background()
+	if _, err := Resolve(ctx, &apiv1.Identifier{System: testPAS, Value: "P100001"}); err != nil {
+		t.Fatalf("access using secondary identifier with relationship denied: %s", err)
+	}
+	id := &apiv1.Identifier{System: testPAS, Value: "P100002"}
+	if _, err := Resolve(ctx, id); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("access using secondary identifier without relationship: expected permission denied, got: %v", err)
+	}
+	justified := metadata.NewIncomingContext(ctx, metadata.Pairs(JustificationHeader, "patient in emergency department"))
+	if _, err := Resolve(justified, id); err != nil {
+		t.Fatalf("justified access using secondary identifier denied: %s", err)
+	}
+	if len(*recorded) != 1 || (*recorded)[0].Policy != "relationship" {
+		t.Fatalf("expected one recorded access, got: %v", *recorded)
+	}
+}
+
+func TestRelationshipFlag(t *testing.T) {
+	recorded := registerTestRelationships(t, FlagWithoutRelationship, referral)
+	if _, err := Resolve(context.Background(), &apiv1.Identifier{System: testCRN, Value: "A999997"}); err != nil {
+		t.Fatalf("access without relationship in flag mode denied: %s", err)
+	}
+	if len(*recorded) != 1 || (*recorded)[0].Identifier.GetValue() != "A999997" || (*recorded)[0].Justification != "" {
+		t.Fatalf("expected flagged access to be recorded, got: %v", *recorded)
+	}
+	if _, err := Resolve(context.Background(), &apiv1.Identifier{System: testCRN, Value: "A999998"}); err != nil || len(*recorded) != 1 {
+		t.Fatalf("access with relationship: %v (%d recorded)", err, len(*recorded))
+	}
+}
+
+func TestRelationshipPolicyError(t *testing.T) {
+	unavailable := func(ctx context.Context, id *apiv1.Identifier) (string, error) {
+		return "", errors.New("connection refused")
+	}
+	id := &apiv1.Identifier{System: testNHS, Value: "1111111111"}
+
+	recorded := registerTestRelationships(t, DenyWithoutRelationship, unavailable)
+	if _, err := Resolve(context.Background(), id); status.Code(err) != codes.Unavailable {
+		t.Fatalf("failed relationship check in deny mode: expected unavailable, got: %v", err)
+	}
+	if len(*recorded) != 0 {
+		t.Fatalf("unexpected recorded access: %v", *recorded)
+	}
+
+	policiesMu.Lock()
+	relationships[testNHS] = modalPolicy{mode: FlagWithoutRelationship, policy: unavailable}
+	policiesMu.Unlock()
+	if _, err := Resolve(context.Background(), id); err != nil {
+		t.Fatalf("failed relationship check in flag mode: access denied: %s", err)
+	}
+	if len(*recorded) != 1 || (*recorded)[0].Policy != "relationship" {
+		t.Fatalf("expected access without relationship check to be recorded, got: %v", *recorded)
+	}
+}
@@ -0,0 +1,157 @@
+package identifiers
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// SupersededHeader is the response metadata (or HTTP header) flagging that the identifier resolved has been
+// superseded, such as when patient records have been merged, giving the surviving identifier as system|value
+const SupersededHeader = "x-superseded-by"
+
+// maxSupersessions is the longest chain of superseded identifiers that is followed
+const maxSupersessions = 16
+
+// SupersessionStore records identifiers that have been superseded by other identifiers
+type SupersessionStore interface {
+	// Supersede records that an identifier has been superseded, replacing any earlier supersession of that identifier
+	Supersede(ctx context.Context, s *apiv1.Supersession) error
+	// SupersededBy returns the supersession of the identifier specified, if it has been superseded
+	SupersededBy(ctx context.Context, id *apiv1.Identifier) (*apiv1.Supersession, bool, error)
+	// Superseding returns the supersessions of identifiers that have been superseded by the identifier specified
+	Superseding(ctx context.Context, id *apiv1.Identifier) ([]*apiv1.Supersession, error)
+}
+
+var (
+	supersessionsMu sync.RWMutex
+	supersessions   SupersessionStore
+	supersedeMu     sync.Mutex // serialises supersessions, so that concurrent merges cannot create a cycle
+)
+
+// RegisterSupersessionStore registers the store of superseded identifiers, which are followed when resolving
+// identifiers
+func RegisterSupersessionStore(store SupersessionStore) {
+	supersessionsMu.Lock()
+	defer supersessionsMu.Unlock()
+	supersessions = store
+	log.Printf("identifiers: registered supersession store")
+}
+
+func supersessionStore() SupersessionStore {
+	supersessionsMu.RLock()
+	defer supersessionsMu.RUnlock()
+	return supersessions
+}
+
+// Supersede records that an identifier has been superseded by another, such as when patient records are merged.
+// The surviving identifier must not itself have been superseded, directly or indirectly, by the identifier
+// superseded, as identifiers would then supersede each other.
+func Supersede(ctx context.Context, s *apiv1.Supersession) error {
+	supersedeMu.Lock()
+	defer supersedeMu.Unlock()
+	store := supersessionStore()
+	if store == nil {
+		return status.Errorf(codes.FailedPrecondition, "no supersession store registered")
+	}
+	old, surviving := s.GetSuperseded(), s.GetSurviving()
+	if old.GetSystem() == "" || old.GetValue() == "" || surviving.GetSystem() == "" || surviving.GetValue() == "" {
+		return status.Errorf(codes.InvalidArgument, "invalid supersession of '%s|%s' by '%s|%s'", old.GetSystem(), old.GetValue(), surviving.GetSystem(), surviving.GetValue())
+	}
+	current, _, err := followSupersessions(ctx, store, surviving)
+	if err != nil {
+		return err
+	}
+	if sameIdentifier(current, old) {
+		return status.Errorf(codes.InvalidArgument, "'%s|%s' cannot be superseded by '%s|%s', which it supersedes", old.GetSystem(), old.GetValue(), surviving.GetSystem(), surviving.GetValue())
+	}
+	if s.GetRecorded() == nil {
+		s.Recorded = ptypes.TimestampNow()
+	}
+	if err := store.Supersede(ctx, s); err != nil {
+		return err
+	}
+	log.Printf("identifiers: '%s|%s' superseded by '%s|%s': %s (%s)", old.GetSystem(), old.GetValue(), surviving.GetSystem(), surviving.GetValue(), s.GetReason(), s.GetSource())
+	return nil
+}
+
+// Current returns the identifier that currently identifies the record identified by the identifier specified,
+// following any supersessions, together with the supersessions followed
+func Current(ctx context.Context, id *apiv1.Identifier) (*apiv1.Identifier, []*apiv1.Supersession, error) {
+	store := supersessionStore()
+	if store == nil {
+		return id, nil, nil
+	}
+	return followSupersessions(ctx, store, id)
+}
+
+func followSupersessions(ctx context.Context, store SupersessionStore, id *apiv1.Identifier) (*apiv1.Identifier, []*apiv1.Supersession, error) {
+	var chain []*apiv1.Supersession
+	current := id
+	for {
+		s, found, err := store.SupersededBy(ctx, current)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !found {
+			return current, chain, nil
+		}
+		if len(chain) == maxSupersessions {
+			return nil, nil, status.Errorf(codes.Internal, "too many supersessions of '%s|%s'", id.GetSystem(), id.GetValue())
+		}
+		chain = append(chain, s)
+		current = s.GetSurviving()
+	}
+}
+
+// MergeHistory returns the current identifier for the record identified by the identifier specified, and the
+// supersessions of all identifiers that now identify that record, oldest first
+func MergeHistory(ctx context.Context, id *apiv1.Identifier) (*apiv1.MergeHistory, error) {
+	store := supersessionStore()
+	if store == nil {
+		return &apiv1.MergeHistory{Current: id}, nil
+	}
+	current, _, err := followSupersessions(ctx, store, id)
+	if err != nil {
+		return nil, err
+	}
+	result := &apiv1.MergeHistory{Current: current}
+	pending := []*apiv1.Identifier{current}
+	for len(pending) > 0 && len(result.Supersessions) < 1000 {
+		ss, err := store.Superseding(ctx, pending[0])
+		if err != nil {
+			return nil, err
+		}
+		pending = pending[1:]
+		for _, s := range ss {
+			result.Supersessions = append(result.Supersessions, s)
+			pending = append(pending, s.GetSuperseded())
+		}
+	}
+	sort.SliceStable(result.Supersessions, func(i, j int) bool {
+		ti, _ := ptypes.Timestamp(result.Supersessions[i].GetRecorded())
+		tj, _ := ptypes.Timestamp(result.Supersessions[j].GetRecorded())
+		return ti.Before(tj)
+	})
+	return result, nil
+}
+
+// setSuperseded flags the response as being for an identifier that has been superseded
+func setSuperseded(ctx context.Context, surviving *apiv1.Identifier) {
+	md := metadata.Pairs(SupersededHeader, surviving.GetSystem()+"|"+surviving.GetValue())
+	if err := grpc.SetHeader(ctx, md); err != nil {
+		log.Printf("identifiers: failed to flag response as superseded: %s", err)
+	}
+}
+
+func sameIdentifier(id1 *apiv1.Identifier, id2 *apiv1.Identifier) bool {
+	return id1.GetSystem() == id2.GetSystem() && id1.GetValue() == id2.GetValue()
+}
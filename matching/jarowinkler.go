@@ -0,0 +1,72 @@
+package matching
+
+// Jaro returns the Jaro similarity of two strings, from 0 (no similarity) to 1 (identical), based on the number
+// of characters in common within a window and the number of those that are transposed.
+func Jaro(s1 string, s2 string) float64 {
+	r1, r2 := []rune(s1), []rune(s2)
+	if len(r1) == 0 && len(r2) == 0 {
+		return 1
+	}
+	if len(r1) == 0 || len(r2) == 0 {
+		return 0
+	}
+	window := max(len(r1), len(r2))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	matched1, matched2 := make([]bool, len(r1)), make([]bool, len(r2))
+	matches := 0
+	for i := range r1 {
+		for j := max(0, i-window); j < min(len(r2), i+window+1); j++ {
+			if !matched2[j] && r1[i] == r2[j] {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+	transpositions, j := 0, 0
+	for i := range r1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[j] {
+			j++
+		}
+		if r1[i] != r2[j] {
+			transpositions++
+		}
+		j++
+	}
+	m := float64(matches)
+	return (m/float64(len(r1)) + m/float64(len(r2)) + (m-float64(transpositions)/2)/m) / 3
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity of two strings, from 0 to 1, which increases the Jaro similarity
+// of strings with a common prefix of up to four characters, as names with typographical errors usually have.
+func JaroWinkler(s1 string, s2 string) float64 {
+	j := Jaro(s1, s2)
+	r1, r2 := []rune(s1), []rune(s2)
+	prefix := 0
+	for prefix < min(4, min(len(r1), len(r2))) && r1[prefix] == r2[prefix] {
+		prefix++
+	}
+	return j + float64(prefix)*0.1*(1-j)
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
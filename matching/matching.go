@@ -0,0 +1,288 @@
+// Package matching provides probabilistic matching of patients, using the Fellegi-Sunter model of record linkage.
+// Each field, such as last name or date of birth, is compared using a comparator that allows for partial agreement,
+// such as from typographical errors, and weighted by how likely it is to agree for records of the same patient (m)
+// and for records of different patients (u). The total weight classifies a pair of patients as a match, a possible
+// match for review, or a non-match.
+package matching
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxCandidates is the maximum number of candidates in a single request to match a patient
+const maxCandidates = 1000
+
+// Comparator compares a field of two patients, returning their similarity, from 0 (disagree) to 1 (agree), or
+// whether the field is missing from either patient
+type Comparator func(pt1 *apiv1.Patient, pt2 *apiv1.Patient) (similarity float64, missing bool)
+
+// Field is a field compared when matching patients
+type Field struct {
+	Name    string
+	M       float64 // probability that the field agrees for records of the same patient
+	U       float64 // probability that the field agrees by chance for records of different patients
+	Compare Comparator
+}
+
+// AgreementWeight returns the weight given to the field when it agrees
+func (f Field) AgreementWeight() float64 {
+	return math.Log2(f.M / f.U)
+}
+
+// DisagreementWeight returns the (negative) weight given to the field when it disagrees
+func (f Field) DisagreementWeight() float64 {
+	return math.Log2((1 - f.M) / (1 - f.U))
+}
+
+// weight returns the weight for partial agreement, interpolated between disagreement and agreement
+func (f Field) weight(similarity float64) float64 {
+	return f.DisagreementWeight() + similarity*(f.AgreementWeight()-f.DisagreementWeight())
+}
+
+// DefaultFields are the fields compared by default. The probabilities are estimates and should be tuned using
+// local data.
+var DefaultFields = []Field{
+	{Name: "nhs_number", M: 0.98, U: 0.0001, Compare: CompareIdentifiers(identifiers.NHSNumber)},
+	{Name: "local_identifier", M: 0.95, U: 0.0001, Compare: CompareIdentifiers(identifiers.CardiffAndValeCRN,
+		identifiers.SwanseaBayCRN, identifiers.CwmTafCRN, identifiers.AneurinBevanCRN, identifiers.HywelDdaCRN,
+		identifiers.BetsiCentralCRN, identifiers.BetsiMaelorCRN, identifiers.BetsiWestCRN)},
+	{Name: "last_name", M: 0.95, U: 0.01, Compare: CompareNames(lastName)},
+	{Name: "first_name", M: 0.9, U: 0.02, Compare: CompareNames(firstName)},
+	{Name: "birth_date", M: 0.97, U: 0.0003, Compare: CompareBirthDates},
+	{Name: "gender", M: 0.98, U: 0.5, Compare: CompareGenders},
+	{Name: "postcode", M: 0.8, U: 0.001, Compare: ComparePostcodes},
+}
+
+// Default thresholds for the classification of matches
+const (
+	DefaultMatchThreshold         = 20
+	DefaultPossibleMatchThreshold = 10
+)
+
+// Matcher matches patients, classifying pairs of patients with a total weight of at least the match threshold as
+// matches, and those with at least the possible match threshold as possible matches.
+type Matcher struct {
+	Fields                 []Field
+	MatchThreshold         float64
+	PossibleMatchThreshold float64
+}
+
+var _ apiv1.PatientMatchingServer = (*Matcher)(nil)
+
+// NewMatcher creates a matcher using the default fields and thresholds
+func NewMatcher() *Matcher {
+	return &Matcher{
+		Fields:                 DefaultFields,
+		MatchThreshold:         DefaultMatchThreshold,
+		PossibleMatchThreshold: DefaultPossibleMatchThreshold,
+	}
+}
+
+// Compare compares two patients
+func (m *Matcher) Compare(pt1 *apiv1.Patient, pt2 *apiv1.Patient) *apiv1.PatientMatch {
+	result := new(apiv1.PatientMatch)
+	for _, f := range m.Fields {
+		similarity, missing := f.Compare(pt1, pt2)
+		fc := &apiv1.FieldComparison{Field: f.Name, Missing: missing}
+		if !missing {
+			fc.Similarity = math.Max(0, math.Min(1, similarity))
+			fc.Weight = f.weight(fc.Similarity)
+			result.Weight += fc.Weight
+		}
+		result.Fields = append(result.Fields, fc)
+	}
+	switch {
+	case result.Weight >= m.MatchThreshold:
+		result.Classification = apiv1.PatientMatch_MATCH
+	case result.Weight >= m.PossibleMatchThreshold:
+		result.Classification = apiv1.PatientMatch_POSSIBLE_MATCH
+	}
+	return result
+}
+
+// MatchPatient compares a patient with candidates, returning matches in order of decreasing weight
+func (m *Matcher) MatchPatient(ctx context.Context, r *apiv1.PatientMatchRequest) (*apiv1.PatientMatchResponse, error) {
+	if r.GetPatient() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "no patient specified")
+	}
+	if len(r.GetCandidates()) > maxCandidates {
+		return nil, status.Errorf(codes.InvalidArgument, "too many candidates: %d (maximum %d)", len(r.GetCandidates()), maxCandidates)
+	}
+	result := new(apiv1.PatientMatchResponse)
+	for i, candidate := range r.GetCandidates() {
+		match := m.Compare(r.GetPatient(), candidate)
+		match.Candidate = int32(i)
+		result.Matches = append(result.Matches, match)
+	}
+	sort.SliceStable(result.Matches, func(i, j int) bool { return result.Matches[i].Weight > result.Matches[j].Weight })
+	return result, nil
+}
+
+// RegisterServer registers this server
+func (m *Matcher) RegisterServer(s *grpc.Server) {
+	apiv1.RegisterPatientMatchingServer(s, m)
+}
+
+// RegisterHTTPProxy registers this as a reverse HTTP proxy
+func (m *Matcher) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	return apiv1.RegisterPatientMatchingHandlerFromEndpoint(ctx, mux, endpoint, opts)
+}
+
+// Close closes any linked resources
+func (m *Matcher) Close() error { return nil }
+
+// CompareIdentifiers returns a comparator of the identifiers of patients in the systems specified, which agree if
+// any identifier in a system held by both patients agrees
+func CompareIdentifiers(systems ...string) Comparator {
+	return func(pt1 *apiv1.Patient, pt2 *apiv1.Patient) (float64, bool) {
+		missing := true
+		for _, system := range systems {
+			ids1, found1 := pt1.GetIdentifiersForSystem(system)
+			ids2, found2 := pt2.GetIdentifiersForSystem(system)
+			if !found1 || !found2 {
+				continue
+			}
+			missing = false
+			for _, id1 := range ids1 {
+				for _, id2 := range ids2 {
+					if normaliseIdentifier(id1.GetValue()) == normaliseIdentifier(id2.GetValue()) {
+						return 1, false
+					}
+				}
+			}
+		}
+		return 0, missing
+	}
+}
+
+func normaliseIdentifier(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+}
+
+// nameThreshold is the Jaro-Winkler similarity of names below which names are treated as disagreeing
+const nameThreshold = 0.8
+
+// CompareNames returns a comparator of the names of patients, using the Jaro-Winkler similarity of the names,
+// ignoring case, spacing and punctuation, scaled so that names less similar than unrelated names usually are
+// disagree
+func CompareNames(name func(*apiv1.Patient) string) Comparator {
+	return func(pt1 *apiv1.Patient, pt2 *apiv1.Patient) (float64, bool) {
+		n1, n2 := normaliseName(name(pt1)), normaliseName(name(pt2))
+		if n1 == "" || n2 == "" {
+			return 0, true
+		}
+		return math.Max(0, (JaroWinkler(n1, n2)-nameThreshold)/(1-nameThreshold)), false
+	}
+}
+
+func normaliseName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, s)
+}
+
+func lastName(pt *apiv1.Patient) string {
+	return pt.GetLastname()
+}
+
+// firstName returns the first of the given names of a patient
+func firstName(pt *apiv1.Patient) string {
+	if names := strings.Fields(pt.GetFirstnames()); len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
+
+// CompareBirthDates compares the dates of birth of patients, which partially agree if the day and month are
+// transposed, or if only one of the day, month or year differs, as from a typographical error
+func CompareBirthDates(pt1 *apiv1.Patient, pt2 *apiv1.Patient) (float64, bool) {
+	if pt1.GetBirthDate() == nil || pt2.GetBirthDate() == nil {
+		return 0, true
+	}
+	t1, err1 := ptypes.Timestamp(pt1.GetBirthDate())
+	t2, err2 := ptypes.Timestamp(pt2.GetBirthDate())
+	if err1 != nil || err2 != nil {
+		return 0, true
+	}
+	y1, m1, d1 := t1.Date()
+	y2, m2, d2 := t2.Date()
+	differences := 0
+	for _, same := range []bool{y1 == y2, m1 == m2, d1 == d2} {
+		if !same {
+			differences++
+		}
+	}
+	switch {
+	case differences == 0:
+		return 1, false
+	case y1 == y2 && int(m1) == d2 && d1 == int(m2):
+		return 0.7, false
+	case differences == 1:
+		return 0.5, false
+	}
+	return 0, false
+}
+
+// CompareGenders compares the genders of patients, if known
+func CompareGenders(pt1 *apiv1.Patient, pt2 *apiv1.Patient) (float64, bool) {
+	if pt1.GetGender() == apiv1.Gender_UNKNOWN || pt2.GetGender() == apiv1.Gender_UNKNOWN {
+		return 0, true
+	}
+	if pt1.GetGender() == pt2.GetGender() {
+		return 1, false
+	}
+	return 0, false
+}
+
+// ComparePostcodes compares the postcodes of the current addresses of patients, which partially agree if in the
+// same postcode sector (e.g. CF14 4), district (e.g. CF14) or area (e.g. CF), being nearby
+func ComparePostcodes(pt1 *apiv1.Patient, pt2 *apiv1.Patient) (float64, bool) {
+	now := time.Now()
+	p1, p2 := normalisePostcode(pt1.CurrentAddress(now).GetPostcode()), normalisePostcode(pt2.CurrentAddress(now).GetPostcode())
+	if len(p1) < 5 || len(p2) < 5 {
+		return 0, true
+	}
+	outward1, inward1 := p1[:len(p1)-3], p1[len(p1)-3:]
+	outward2, inward2 := p2[:len(p2)-3], p2[len(p2)-3:]
+	switch {
+	case p1 == p2:
+		return 1, false
+	case outward1 == outward2 && inward1[0] == inward2[0]:
+		return 0.7, false
+	case outward1 == outward2:
+		return 0.4, false
+	case postcodeArea(outward1) == postcodeArea(outward2):
+		return 0.1, false
+	}
+	return 0, false
+}
+
+func normalisePostcode(s string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(s), ""))
+}
+
+// postcodeArea returns the area of an outward code, being its leading letters
+func postcodeArea(outward string) string {
+	for i, r := range outward {
+		if !unicode.IsLetter(r) {
+			return outward[:i]
+		}
+	}
+	return outward
+}
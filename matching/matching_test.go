@@ -0,0 +1,119 @@
+package matching
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+)
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		s1, s2   string
+		expected float64
+	}{
+		{"MARTHA", "MARHTA", 0.961},
+		{"DWAYNE", "DUANE", 0.840},
+		{"DIXON", "DICKSONX", 0.813},
+		{"JONES", "JONES", 1},
+		{"", "", 1},
+		{"SMITH", "", 0},
+		{"ABC", "XYZ", 0},
+	}
+	for _, test := range tests {
+		if jw := JaroWinkler(test.s1, test.s2); math.Abs(jw-test.expected) > 0.001 {
+			t.Errorf("JaroWinkler(%q, %q): expected %.3f, got %.3f", test.s1, test.s2, test.expected, jw)
+		}
+	}
+}
+
+func birthDate(year int, month time.Month, day int) *apiv1.Patient {
+	ts, _ := ptypes.TimestampProto(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+	return &apiv1.Patient{BirthDate: ts}
+}
+
+func postcode(pc string) *apiv1.Patient {
+	return &apiv1.Patient{Addresses: []*apiv1.Address{{Postcode: pc}}}
+}
+
+func TestComparators(t *testing.T) {
+	tests := []struct {
+		name       string
+		compare    Comparator
+		pt1, pt2   *apiv1.Patient
+		similarity float64
+		missing    bool
+	}{
+		{"same birth date", CompareBirthDates, birthDate(1960, 3, 4), birthDate(1960, 3, 4), 1, false},
+		{"transposed birth date", CompareBirthDates, birthDate(1960, 3, 4), birthDate(1960, 4, 3), 0.7, false},
+		{"birth year differs", CompareBirthDates, birthDate(1960, 3, 4), birthDate(1906, 3, 4), 0.5, false},
+		{"different birth date", CompareBirthDates, birthDate(1960, 3, 4), birthDate(1970, 5, 6), 0, false},
+		{"missing birth date", CompareBirthDates, birthDate(1960, 3, 4), &apiv1.Patient{}, 0, true},
+		{"same postcode", ComparePostcodes, postcode("CF14 4XW"), postcode("cf144xw"), 1, false},
+		{"same sector", ComparePostcodes, postcode("CF14 4XW"), postcode("CF14 4AB"), 0.7, false},
+		{"same district", ComparePostcodes, postcode("CF14 4XW"), postcode("CF14 7AB"), 0.4, false},
+		{"same area", ComparePostcodes, postcode("CF14 4XW"), postcode("CF5 1AB"), 0.1, false},
+		{"different postcode", ComparePostcodes, postcode("CF14 4XW"), postcode("SA1 1AB"), 0, false},
+		{"missing postcode", ComparePostcodes, postcode("CF14 4XW"), postcode(""), 0, true},
+		{"same name", CompareNames(lastName), &apiv1.Patient{Lastname: "O'Brien"}, &apiv1.Patient{Lastname: "OBRIEN"}, 1, false},
+		{"different name", CompareNames(lastName), &apiv1.Patient{Lastname: "Smith"}, &apiv1.Patient{Lastname: "Davies"}, 0, false},
+		{"unknown gender", CompareGenders, &apiv1.Patient{Gender: apiv1.Gender_MALE}, &apiv1.Patient{}, 0, true},
+		{"different NHS number", CompareIdentifiers(identifiers.NHSNumber),
+			&apiv1.Patient{Identifiers: []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "1111111111"}}},
+			&apiv1.Patient{Identifiers: []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "2222222222"}}}, 0, false},
+		{"same NHS number", CompareIdentifiers(identifiers.NHSNumber),
+			&apiv1.Patient{Identifiers: []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "111 111 1111"}}},
+			&apiv1.Patient{Identifiers: []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "1111111111"}}}, 1, false},
+	}
+	for _, test := range tests {
+		similarity, missing := test.compare(test.pt1, test.pt2)
+		if math.Abs(similarity-test.similarity) > 0.001 || missing != test.missing {
+			t.Errorf("%s: expected %v (missing: %v), got %v (missing: %v)", test.name, test.similarity, test.missing, similarity, missing)
+		}
+	}
+}
+
+func TestMatchPatient(t *testing.T) {
+	pt := birthDate(1960, 1, 1)
+	pt.Lastname, pt.Firstnames, pt.Gender = "DUMMY", "ALBERT", apiv1.Gender_MALE
+	pt.Addresses = []*apiv1.Address{{Postcode: "CF14 4XW"}}
+	pt.Identifiers = []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "1111111111"}}
+
+	typo := birthDate(1960, 1, 1)
+	typo.Lastname, typo.Firstnames, typo.Gender = "DUMY", "ALBERT JOHN", apiv1.Gender_MALE
+	typo.Addresses = []*apiv1.Address{{Postcode: "CF14 4XW"}}
+
+	moved := birthDate(1960, 1, 10)
+	moved.Lastname, moved.Firstnames, moved.Gender = "DUMMY", "ALBERT", apiv1.Gender_MALE
+	moved.Addresses = []*apiv1.Address{{Postcode: "SA1 1AB"}}
+
+	other := birthDate(1975, 6, 12)
+	other.Lastname, other.Firstnames, other.Gender = "JONES", "MARY", apiv1.Gender_FEMALE
+	other.Identifiers = []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "2222222222"}}
+
+	m := NewMatcher()
+	result, err := m.MatchPatient(context.Background(), &apiv1.PatientMatchRequest{Patient: pt, Candidates: []*apiv1.Patient{other, moved, typo}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		candidate      int32
+		classification apiv1.PatientMatch_Classification
+	}{
+		{2, apiv1.PatientMatch_MATCH},
+		{1, apiv1.PatientMatch_POSSIBLE_MATCH},
+		{0, apiv1.PatientMatch_NON_MATCH},
+	}
+	for i, match := range result.GetMatches() {
+		if match.GetCandidate() != expected[i].candidate || match.GetClassification() != expected[i].classification {
+			t.Errorf("match %d: expected candidate %d (%s), got candidate %d (%s) weight: %.1f", i, expected[i].candidate, expected[i].classification, match.GetCandidate(), match.GetClassification(), match.GetWeight())
+		}
+	}
+	if len(result.GetMatches()) != 3 || len(result.GetMatches()[0].GetFields()) != len(DefaultFields) {
+		t.Fatalf("incorrect matches: %v", result)
+	}
+}
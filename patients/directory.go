@@ -0,0 +1,228 @@
+// Package patients provides a directory of patients, routing requests to the EMPI or to patient administration
+// systems according to the system of each patient identifier.
+package patients
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxIdentifiers is the maximum number of identifiers in a single request for patients
+const maxIdentifiers = 100
+
+// Provider provides patients with identifiers in one or more systems, such as the EMPI or a patient
+// administration system
+type Provider interface {
+	GetPatient(ctx context.Context, id *apiv1.Identifier) (*apiv1.Patient, error)
+}
+
+// Searcher searches for patients by demographics. The directory checks that the caller may access each patient
+// found, unless the searcher implements AccessChecked.
+type Searcher interface {
+	SearchPatient(r *apiv1.PatientSearchRequest, s apiv1.PatientDirectory_SearchPatientServer) error
+}
+
+// AccessChecked is implemented by searchers that already omit patients the caller may not access, using
+// identifiers.CheckAccess, so that the directory does not check each patient again
+type AccessChecked interface {
+	AccessChecked() bool
+}
+
+type namedProvider struct {
+	name string
+	Provider
+}
+
+// Directory is the patient directory service, routing requests for patients to the provider registered for the
+// system of the identifier. Patients are resolved as identifiers, so that access policies apply and superseded
+// identifiers are followed, as for the identifier service.
+// This is thread-safe.
+type Directory struct {
+	mu        sync.RWMutex
+	providers map[string]namedProvider // providers by system
+	searcher  Searcher
+}
+
+var _ apiv1.PatientDirectoryServer = (*Directory)(nil)
+
+// NewDirectory creates a directory with no providers
+func NewDirectory() *Directory {
+	return &Directory{providers: make(map[string]namedProvider)}
+}
+
+// RegisterProvider registers a provider of patients with identifiers in the systems specified. The provider is
+// also registered to resolve identifiers in those systems. Registering a provider again using the same name, such
+// as when configuration is reloaded, replaces the systems for which it was registered.
+func (d *Directory) RegisterProvider(name string, p Provider, systems ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	registered := make(map[string]struct{}, len(systems))
+	for _, system := range systems {
+		registered[system] = struct{}{}
+		if existing, found := d.providers[system]; found && existing.name == name {
+			identifiers.UnregisterResolver(system)
+		}
+		d.providers[system] = namedProvider{name: name, Provider: p}
+		identifiers.RegisterResolver(system, func(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
+			pt, err := p.GetPatient(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			return pt, nil
+		})
+		log.Printf("patients: registered provider '%s' for %s", name, system)
+	}
+	for system, existing := range d.providers {
+		if _, found := registered[system]; !found && existing.name == name {
+			delete(d.providers, system)
+			identifiers.UnregisterResolver(system)
+			log.Printf("patients: unregistered provider '%s' for %s", name, system)
+		}
+	}
+}
+
+// RegisterSearcher registers the service used to search for patients by demographics
+func (d *Directory) RegisterSearcher(s Searcher) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.searcher = s
+}
+
+func (d *Directory) provider(system string) (namedProvider, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	p, ok := d.providers[system]
+	return p, ok
+}
+
+// GetPatient returns the patient with the identifier specified
+func (d *Directory) GetPatient(ctx context.Context, id *apiv1.Identifier) (*apiv1.Patient, error) {
+	if id.GetSystem() == "" || id.GetValue() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid identifier: must specify system and value")
+	}
+	if _, ok := d.provider(id.GetSystem()); !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported patient identifier system: %s", id.GetSystem())
+	}
+	o, err := identifiers.Resolve(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	pt, ok := o.(*apiv1.Patient)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "'%s|%s' did not resolve to a patient", id.GetSystem(), id.GetValue())
+	}
+	return pt, nil
+}
+
+// GetPatients returns the patients with the identifiers specified. Each identifier counts as a request against
+// rate limits, as each patient is fetched separately.
+func (d *Directory) GetPatients(ctx context.Context, r *apiv1.PatientsRequest) (*apiv1.PatientsResponse, error) {
+	return Lookup(ctx, r.GetIdentifiers(), d.GetPatient)
+}
+
+// Lookup returns the patients with the identifiers specified, using the function specified to get each patient,
+// together with the identifiers for which no patient was found. Any other error fails the lookup.
+func Lookup(ctx context.Context, ids []*apiv1.Identifier, get func(context.Context, *apiv1.Identifier) (*apiv1.Patient, error)) (*apiv1.PatientsResponse, error) {
+	if len(ids) > maxIdentifiers {
+		return nil, status.Errorf(codes.InvalidArgument, "too many identifiers: %d (maximum %d)", len(ids), maxIdentifiers)
+	}
+	result := new(apiv1.PatientsResponse)
+	for _, id := range ids {
+		pt, err := get(ctx, id)
+		if status.Code(err) == codes.NotFound {
+			result.NotFound = append(result.NotFound, id)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Patients = append(result.Patients, pt)
+	}
+	return result, nil
+}
+
+// SearchPatient searches for patients by demographics, omitting patients the caller may not access, as for
+// GetPatient
+func (d *Directory) SearchPatient(r *apiv1.PatientSearchRequest, s apiv1.PatientDirectory_SearchPatientServer) error {
+	d.mu.RLock()
+	searcher := d.searcher
+	d.mu.RUnlock()
+	if searcher == nil {
+		return status.Errorf(codes.Unimplemented, "patient search not available")
+	}
+	if ac, ok := searcher.(AccessChecked); ok && ac.AccessChecked() {
+		return searcher.SearchPatient(r, s)
+	}
+	ps := &permittedStream{PatientDirectory_SearchPatientServer: s}
+	if err := searcher.SearchPatient(r, ps); err != nil {
+		return err
+	}
+	if ps.withheld > 0 {
+		log.Printf("patients: search: %d patients withheld", ps.withheld)
+	}
+	return nil
+}
+
+// permittedStream sends only the patients that the caller may access
+type permittedStream struct {
+	apiv1.PatientDirectory_SearchPatientServer
+	withheld int
+}
+
+func (s *permittedStream) Send(pt *apiv1.Patient) error {
+	err := identifiers.CheckAccess(s.Context(), pt)
+	if status.Code(err) == codes.PermissionDenied {
+		s.withheld++
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.PatientDirectory_SearchPatientServer.Send(pt)
+}
+
+// CheckHealth reports whether the providers of patients are available
+func (d *Directory) CheckHealth(ctx context.Context) error {
+	d.mu.RLock()
+	checked := make(map[string]bool)
+	var checkers []namedProvider
+	for _, p := range d.providers {
+		if _, ok := p.Provider.(server.HealthChecker); ok && !checked[p.name] {
+			checked[p.name] = true
+			checkers = append(checkers, p)
+		}
+	}
+	d.mu.RUnlock()
+	sort.Slice(checkers, func(i, j int) bool { return checkers[i].name < checkers[j].name })
+	for _, p := range checkers {
+		if err := p.Provider.(server.HealthChecker).CheckHealth(ctx); err != nil {
+			return fmt.Errorf("%s: %w", p.name, err)
+		}
+	}
+	return nil
+}
+
+// RegisterServer registers this server
+func (d *Directory) RegisterServer(s *grpc.Server) {
+	apiv1.RegisterPatientDirectoryServer(s, d)
+}
+
+// RegisterHTTPProxy registers this as a reverse HTTP proxy
+func (d *Directory) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	return apiv1.RegisterPatientDirectoryHandlerFromEndpoint(ctx, mux, endpoint, opts)
+}
+
+// Close closes any linked resources
+func (d *Directory) Close() error { return nil }
@@ -0,0 +1,161 @@
+package patients
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	testPAS  = "https://concierge.test/Id/pas"
+	testEMPI = "https://concierge.test/Id/empi"
+)
+
+type testProvider struct {
+	name    string
+	healthy bool
+}
+
+func (p *testProvider) GetPatient(ctx context.Context, id *apiv1.Identifier) (*apiv1.Patient, error) {
+	if id.GetValue() == "unknown" {
+		return nil, status.Errorf(codes.NotFound, "patient not found")
+	}
+	if id.GetValue() == "failure" {
+		return nil, status.Errorf(codes.Unavailable, "%s unavailable", p.name)
+	}
+	return &apiv1.Patient{Lastname: p.name, Identifiers: []*apiv1.Identifier{id}}, nil
+}
+
+func (p *testProvider) CheckHealth(ctx context.Context) error {
+	if !p.healthy {
+		return errors.New("unavailable")
+	}
+	return nil
+}
+
+func TestDirectory(t *testing.T) {
+	ctx := context.Background()
+	d := NewDirectory()
+	pas, empi := &testProvider{name: "PAS", healthy: true}, &testProvider{name: "EMPI", healthy: true}
+	d.RegisterProvider("pas", pas, testPAS)
+	d.RegisterProvider("empi", empi, testEMPI)
+
+	// patients are routed by system
+	for system, name := range map[string]string{testPAS: "PAS", testEMPI: "EMPI"} {
+		pt, err := d.GetPatient(ctx, &apiv1.Identifier{System: system, Value: "A999998"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pt.GetLastname() != name {
+			t.Fatalf("patient with %s identifier from incorrect provider: %v", system, pt)
+		}
+	}
+	// and can be resolved as identifiers
+	if o, err := identifiers.Resolve(ctx, &apiv1.Identifier{System: testPAS, Value: "A999998"}); err != nil || o.(*apiv1.Patient).GetLastname() != "PAS" {
+		t.Fatalf("identifier not resolved by provider: %v %v", o, err)
+	}
+	if _, err := d.GetPatient(ctx, &apiv1.Identifier{System: "https://concierge.test/Id/other", Value: "A999998"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected unsupported system to be invalid, got: %v", err)
+	}
+
+	result, err := d.GetPatients(ctx, &apiv1.PatientsRequest{Identifiers: []*apiv1.Identifier{
+		{System: testEMPI, Value: "1"}, {System: testPAS, Value: "unknown"}, {System: testPAS, Value: "2"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.GetPatients()) != 2 || result.GetPatients()[0].GetLastname() != "EMPI" || result.GetPatients()[1].GetLastname() != "PAS" ||
+		len(result.GetNotFound()) != 1 || result.GetNotFound()[0].GetValue() != "unknown" {
+		t.Fatalf("incorrect patients: %v", result)
+	}
+	if _, err := d.GetPatients(ctx, &apiv1.PatientsRequest{Identifiers: []*apiv1.Identifier{{System: testPAS, Value: "failure"}}}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected failure of provider to fail lookup, got: %v", err)
+	}
+
+	if err := d.SearchPatient(&apiv1.PatientSearchRequest{}, nil); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected search without searcher to be unimplemented, got: %v", err)
+	}
+	if err := d.CheckHealth(ctx); err != nil {
+		t.Fatal(err)
+	}
+	pas.healthy = false
+	if err := d.CheckHealth(ctx); err == nil || err.Error() != "pas: unavailable" {
+		t.Fatalf("expected unhealthy provider to be reported, got: %v", err)
+	}
+
+	// registering a provider again replaces the systems for which it is registered
+	const testOther = "https://concierge.test/Id/other-empi"
+	d.RegisterProvider("empi", empi, testOther)
+	t.Cleanup(func() { identifiers.UnregisterResolver(testOther) })
+	if _, err := d.GetPatient(ctx, &apiv1.Identifier{System: testEMPI, Value: "A999998"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected system no longer registered to be invalid, got: %v", err)
+	}
+	if _, err := identifiers.Resolve(ctx, &apiv1.Identifier{System: testEMPI, Value: "A999998"}); err == nil {
+		t.Fatalf("identifier resolved for system no longer registered")
+	}
+	if pt, err := d.GetPatient(ctx, &apiv1.Identifier{System: testOther, Value: "A999998"}); err != nil || pt.GetLastname() != "EMPI" {
+		t.Fatalf("patient not provided for newly registered system: %v %v", pt, err)
+	}
+}
+
+// testSearcher finds a patient for each identifier value in the last name searched for
+type testSearcher struct {
+	checked bool
+}
+
+func (ts *testSearcher) SearchPatient(r *apiv1.PatientSearchRequest, s apiv1.PatientDirectory_SearchPatientServer) error {
+	for _, value := range strings.Fields(r.GetLastName()) {
+		if err := s.Send(&apiv1.Patient{Identifiers: []*apiv1.Identifier{{System: testPAS, Value: value}}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ts *testSearcher) AccessChecked() bool {
+	return ts.checked
+}
+
+type testSearchStream struct {
+	grpc.ServerStream
+	patients []*apiv1.Patient
+}
+
+func (s *testSearchStream) Context() context.Context { return context.Background() }
+
+func (s *testSearchStream) Send(pt *apiv1.Patient) error {
+	s.patients = append(s.patients, pt)
+	return nil
+}
+
+func TestSearchPatient(t *testing.T) {
+	checks := 0
+	identifiers.RegisterAccessPolicy("test-restricted", func(ctx context.Context, id *apiv1.Identifier, o proto.Message) (bool, string) {
+		checks++
+		return id.GetValue() == "A999997", "restricted record"
+	})
+	t.Cleanup(func() { identifiers.UnregisterAccessPolicy("test-restricted") })
+	d := NewDirectory()
+	d.RegisterSearcher(&testSearcher{})
+	s := &testSearchStream{}
+	if err := d.SearchPatient(&apiv1.PatientSearchRequest{LastName: "A999997 A999998"}, s); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.patients) != 1 || s.patients[0].GetIdentifiers()[0].GetValue() != "A999998" {
+		t.Fatalf("restricted patient not withheld from search results: %v", s.patients)
+	}
+	// patients are not checked again if the searcher has checked them
+	checks = 0
+	d.RegisterSearcher(&testSearcher{checked: true})
+	if err := d.SearchPatient(&apiv1.PatientSearchRequest{LastName: "A999997 A999998"}, &testSearchStream{}); err != nil || checks != 0 {
+		t.Fatalf("search results checked twice: %d checks (%v)", checks, err)
+	}
+}
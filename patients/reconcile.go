@@ -0,0 +1,245 @@
+package patients
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Reconciler reconciles the demographics of patients held in the EMPI with those held in the patient
+// administration systems (PAS) of health boards, supporting data quality workflows.
+// The identifier requested is resolved as for the identifier service, so that access policies apply, and the
+// patient is then fetched from the other source directly, with access to that record checked in the same way, as
+// it may be restricted even if the first is not.
+// This is thread-safe.
+type Reconciler struct {
+	empi Provider
+
+	mu  sync.RWMutex
+	pas map[string]Provider // patient administration systems, by system of the identifiers they issue
+}
+
+var _ apiv1.ReconciliationServer = (*Reconciler)(nil)
+
+// NewReconciler creates a reconciler of patients in the EMPI specified with those in patient administration systems
+func NewReconciler(empi Provider) *Reconciler {
+	return &Reconciler{empi: empi, pas: make(map[string]Provider)}
+}
+
+// RegisterPAS registers a patient administration system issuing identifiers in the system specified
+func (r *Reconciler) RegisterPAS(system string, p Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pas[system] = p
+	log.Printf("patients: registered patient administration system for reconciliation: %s", system)
+}
+
+// ReconcilePatient fetches the patient with the identifier specified from the EMPI and from a patient
+// administration system, returning a merged view and the discrepancies between them
+func (r *Reconciler) ReconcilePatient(ctx context.Context, id *apiv1.Identifier) (*apiv1.PatientReconciliation, error) {
+	if id.GetSystem() == "" || id.GetValue() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid identifier: must specify system and value")
+	}
+	current, _, err := identifiers.Current(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	o, err := identifiers.Resolve(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	pt, ok := o.(*apiv1.Patient)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "'%s|%s' does not identify a patient", id.GetSystem(), id.GetValue())
+	}
+	result := new(apiv1.PatientReconciliation)
+	r.mu.RLock()
+	_, isPAS := r.pas[current.GetSystem()]
+	r.mu.RUnlock()
+	if isPAS {
+		// the EMPI holds the identifiers issued by each health board
+		result.Pas = pt
+		if result.Empi, err = r.empi.GetPatient(ctx, current); err != nil {
+			return nil, err
+		}
+	} else {
+		result.Empi = pt
+		pas, pasID := r.pasFor(pt)
+		if pas == nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "'%s|%s' has no identifier issued by a patient administration system", id.GetSystem(), id.GetValue())
+		}
+		if result.Pas, err = pas.GetPatient(ctx, pasID); err != nil {
+			return nil, err
+		}
+	}
+	other := result.Pas
+	if isPAS {
+		other = result.Empi
+	}
+	if err := identifiers.CheckAccess(ctx, other); err != nil {
+		return nil, err
+	}
+	result.Discrepancies = Discrepancies(result.Empi, result.Pas)
+	result.Patient = merge(result.Empi, result.Pas)
+	log.Printf("patients: reconciled '%s|%s': %d discrepancies", id.GetSystem(), id.GetValue(), len(result.Discrepancies))
+	return result, nil
+}
+
+// pasFor returns a patient administration system, and the identifier it issued, for the patient specified
+func (r *Reconciler) pasFor(pt *apiv1.Patient) (Provider, *apiv1.Identifier) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, id := range pt.GetIdentifiers() {
+		if p, ok := r.pas[id.GetSystem()]; ok {
+			return p, id
+		}
+	}
+	return nil, nil
+}
+
+// Discrepancies returns the differences in the demographics of a patient between the EMPI and a patient
+// administration system. Names and addresses are compared ignoring case and spacing, and only the current
+// address is compared.
+func Discrepancies(empi *apiv1.Patient, pas *apiv1.Patient) []*apiv1.Discrepancy {
+	now := time.Now()
+	var result []*apiv1.Discrepancy
+	compare := func(field apiv1.Discrepancy_Field, empiValue string, pasValue string) {
+		if normalise(empiValue) != normalise(pasValue) {
+			result = append(result, &apiv1.Discrepancy{Field: field, EmpiValue: empiValue, PasValue: pasValue})
+		}
+	}
+	compare(apiv1.Discrepancy_NAME, formatName(empi), formatName(pas))
+	compare(apiv1.Discrepancy_BIRTH_DATE, formatDate(empi.GetBirthDate()), formatDate(pas.GetBirthDate()))
+	if empi.GetGender() != pas.GetGender() {
+		result = append(result, &apiv1.Discrepancy{Field: apiv1.Discrepancy_GENDER, EmpiValue: empi.GetGender().String(), PasValue: pas.GetGender().String()})
+	}
+	compare(apiv1.Discrepancy_ADDRESS, formatAddress(empi.CurrentAddress(now)), formatAddress(pas.CurrentAddress(now)))
+	compare(apiv1.Discrepancy_GENERAL_PRACTITIONER, empi.GetGeneralPractitioner(), pas.GetGeneralPractitioner())
+	compare(apiv1.Discrepancy_SURGERY, empi.GetSurgery(), pas.GetSurgery())
+	compare(apiv1.Discrepancy_DECEASED, formatDeceased(empi), formatDeceased(pas))
+	compare(apiv1.Discrepancy_NHS_NUMBER, nhsNumber(empi), nhsNumber(pas))
+	return result
+}
+
+// merge returns a merged view of a patient, preferring the EMPI, as the master index, and using the patient
+// administration system for demographics missing from the EMPI. Identifiers from both are included, and the
+// patient is deceased if deceased in either.
+func merge(empi *apiv1.Patient, pas *apiv1.Patient) *apiv1.Patient {
+	pt := proto.Clone(empi).(*apiv1.Patient)
+	if pt.Lastname == "" {
+		pt.Lastname, pt.Firstnames, pt.Title = pas.GetLastname(), pas.GetFirstnames(), pas.GetTitle()
+	}
+	if pt.Gender == apiv1.Gender_UNKNOWN {
+		pt.Gender = pas.GetGender()
+	}
+	if pt.BirthDate == nil {
+		pt.BirthDate = pas.GetBirthDate()
+	}
+	if pt.Deceased == nil {
+		switch d := pas.GetDeceased().(type) {
+		case *apiv1.Patient_DeceasedDate:
+			pt.Deceased = &apiv1.Patient_DeceasedDate{DeceasedDate: d.DeceasedDate}
+		case *apiv1.Patient_DeceasedBoolean:
+			pt.Deceased = &apiv1.Patient_DeceasedBoolean{DeceasedBoolean: d.DeceasedBoolean}
+		}
+	}
+	if pt.Surgery == "" && pt.GeneralPractitioner == "" {
+		pt.Surgery, pt.GeneralPractitioner = pas.GetSurgery(), pas.GetGeneralPractitioner()
+	}
+	if len(pt.Addresses) == 0 {
+		pt.Addresses = pas.GetAddresses()
+	}
+	if len(pt.Telephones) == 0 {
+		pt.Telephones = pas.GetTelephones()
+	}
+	for _, id := range pas.GetIdentifiers() {
+		if !hasIdentifier(pt, id) {
+			pt.Identifiers = append(pt.Identifiers, id)
+		}
+	}
+	return pt
+}
+
+func hasIdentifier(pt *apiv1.Patient, id *apiv1.Identifier) bool {
+	for _, other := range pt.GetIdentifiers() {
+		if other.GetSystem() == id.GetSystem() && strings.EqualFold(other.GetValue(), id.GetValue()) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalise normalises a value for comparison, ignoring case and differences in spacing
+func normalise(s string) string {
+	return strings.Join(strings.Fields(strings.ToUpper(s)), " ")
+}
+
+func formatName(pt *apiv1.Patient) string {
+	if pt.GetLastname() == "" {
+		return strings.TrimSpace(pt.GetFirstnames())
+	}
+	return strings.TrimSpace(pt.GetLastname() + ", " + pt.GetFirstnames())
+}
+
+func formatDate(ts *timestamp.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func formatAddress(a *apiv1.Address) string {
+	var parts []string
+	for _, s := range []string{a.GetAddress1(), a.GetAddress2(), a.GetAddress3(), a.GetPostcode()} {
+		if s = strings.TrimSpace(s); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatDeceased(pt *apiv1.Patient) string {
+	if d := formatDate(pt.GetDeceasedDate()); d != "" {
+		return d
+	}
+	if pt.GetDeceasedBoolean() {
+		return "deceased"
+	}
+	return ""
+}
+
+func nhsNumber(pt *apiv1.Patient) string {
+	if ids, ok := pt.GetIdentifiersForSystem(identifiers.NHSNumber); ok {
+		return strings.ReplaceAll(ids[0].GetValue(), " ", "")
+	}
+	return ""
+}
+
+// RegisterServer registers this server
+func (r *Reconciler) RegisterServer(s *grpc.Server) {
+	apiv1.RegisterReconciliationServer(s, r)
+}
+
+// RegisterHTTPProxy registers this as a reverse HTTP proxy
+func (r *Reconciler) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	return apiv1.RegisterReconciliationHandlerFromEndpoint(ctx, mux, endpoint, opts)
+}
+
+// Close closes any linked resources
+func (r *Reconciler) Close() error { return nil }
@@ -0,0 +1,108 @@
+package patients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const testReconcilePAS = "https://concierge.test/Id/reconcile-pas"
+
+type fixedProvider struct {
+	patients map[string]*apiv1.Patient // by system|value
+}
+
+func (p *fixedProvider) GetPatient(ctx context.Context, id *apiv1.Identifier) (*apiv1.Patient, error) {
+	if pt, ok := p.patients[id.GetSystem()+"|"+id.GetValue()]; ok {
+		return pt, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "patient not found")
+}
+
+func date(year int, month time.Month, day int) *apiv1.Patient_DeceasedDate {
+	ts, _ := ptypes.TimestampProto(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+	return &apiv1.Patient_DeceasedDate{DeceasedDate: ts}
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	birthDate, _ := ptypes.TimestampProto(time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC))
+	crn := &apiv1.Identifier{System: testReconcilePAS, Value: "A999998"}
+	nhs := &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"}
+	empiPt := &apiv1.Patient{
+		Lastname: "Dummy", Firstnames: "Albert", Gender: apiv1.Gender_MALE, BirthDate: birthDate,
+		GeneralPractitioner: "G9342400", Surgery: "W95010",
+		Identifiers: []*apiv1.Identifier{nhs, crn},
+		Addresses: []*apiv1.Address{
+			{Address1: "1 Station Road", Postcode: "CF14 4XW", Period: &apiv1.Period{End: birthDate}},
+			{Address1: "2 Park Lane", Postcode: "CF11 9AB"},
+		},
+	}
+	pasPt := &apiv1.Patient{
+		Lastname: "DUMMY", Firstnames: "ALBERT", Gender: apiv1.Gender_MALE, BirthDate: birthDate,
+		Surgery:     "W95011",
+		Deceased:    date(2020, 6, 1),
+		Identifiers: []*apiv1.Identifier{crn, {System: "https://concierge.test/Id/other", Value: "1"}},
+		Addresses:   []*apiv1.Address{{Address1: "2 PARK LANE", Postcode: "CF11  9AB"}},
+	}
+	empi := &fixedProvider{patients: map[string]*apiv1.Patient{
+		identifiers.NHSNumber + "|1111111111": empiPt,
+		testReconcilePAS + "|A999998":         empiPt,
+	}}
+	pas := &fixedProvider{patients: map[string]*apiv1.Patient{testReconcilePAS + "|A999998": pasPt}}
+	d := NewDirectory()
+	d.RegisterProvider("empi", empi, identifiers.NHSNumber)
+	d.RegisterProvider("pas", pas, testReconcilePAS)
+	r := NewReconciler(empi)
+	r.RegisterPAS(testReconcilePAS, pas)
+
+	// the same patient is reconciled from either identifier
+	for _, id := range []*apiv1.Identifier{nhs, crn} {
+		result, err := r.ReconcilePatient(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.GetEmpi() != empiPt || result.GetPas() != pasPt {
+			t.Fatalf("incorrect patients reconciled for %v: %v", id, result)
+		}
+		fields := make(map[apiv1.Discrepancy_Field]*apiv1.Discrepancy)
+		for _, d := range result.GetDiscrepancies() {
+			fields[d.GetField()] = d
+		}
+		if len(fields) != 4 || fields[apiv1.Discrepancy_GENERAL_PRACTITIONER] == nil || fields[apiv1.Discrepancy_SURGERY] == nil ||
+			fields[apiv1.Discrepancy_DECEASED].GetPasValue() != "2020-06-01" || fields[apiv1.Discrepancy_NHS_NUMBER].GetEmpiValue() != "1111111111" {
+			t.Fatalf("incorrect discrepancies: %v", result.GetDiscrepancies())
+		}
+		pt := result.GetPatient()
+		if pt.GetLastname() != "Dummy" || pt.GetSurgery() != "W95010" || !proto.Equal(pt.GetDeceasedDate(), pasPt.GetDeceasedDate()) || len(pt.GetIdentifiers()) != 3 {
+			t.Fatalf("incorrect merged patient: %v", pt)
+		}
+		if empiPt.GetDeceased() != nil || len(empiPt.GetIdentifiers()) != 2 {
+			t.Fatalf("patient from EMPI modified by merge: %v", empiPt)
+		}
+	}
+
+	// access to the record fetched from the other source is checked, as it may be restricted even if the record
+	// resolved is not
+	identifiers.RegisterAccessPolicy("test-restricted", func(ctx context.Context, id *apiv1.Identifier, o proto.Message) (bool, string) {
+		return id.GetSystem() == "https://concierge.test/Id/other", "restricted record"
+	})
+	defer identifiers.UnregisterAccessPolicy("test-restricted")
+	if _, err := r.ReconcilePatient(ctx, nhs); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected access to restricted record in patient administration system to be denied, got: %v", err)
+	}
+	identifiers.UnregisterAccessPolicy("test-restricted")
+
+	// patients without an identifier from a patient administration system cannot be reconciled
+	empiPt.Identifiers = []*apiv1.Identifier{nhs}
+	if _, err := r.ReconcilePatient(ctx, nhs); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected patient without a PAS identifier not to be reconciled, got: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package relationship
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"golang.org/x/sync/singleflight"
+)
+
+// ClinicSource returns the patients booked into the specified clinics on a date, such as cav.PMSService.PatientsForClinics
+type ClinicSource func(ctx context.Context, date time.Time, clinics []*apiv1.Identifier) ([]*apiv1.Patient, error)
+
+// clinicListTTL is how long a clinic list is used before it is fetched again
+const clinicListTTL = 15 * time.Minute
+
+// ClinicBookings checks that a patient is booked into one of the user's clinics, within a window of days around today.
+// Clinic lists are cached, and checked before any are fetched, and dates nearest today are checked first, so that
+// most checks need few, if any, calls to the source. Concurrent requests for the same clinic list share a single
+// fetch. This is thread-safe.
+type ClinicBookings struct {
+	source  ClinicSource
+	days    int // number of days before and after today in which a booking is a relationship
+	mu      sync.Mutex
+	clinics map[string][]*apiv1.Identifier // clinics by user
+	lists   map[string]*clinicList         // clinic lists by clinic and date
+	fetches singleflight.Group             // fetches of clinic lists in progress, by clinic and date
+	now     func() time.Time
+}
+
+type clinicList struct {
+	fetched  time.Time
+	patients map[string]struct{} // identifiers of booked patients, as system|value
+}
+
+var _ Checker = (*ClinicBookings)(nil)
+
+// NewClinicBookings creates a check for clinic bookings from the source specified, within the number of days
+// before and after today specified
+func NewClinicBookings(source ClinicSource, days int) *ClinicBookings {
+	return &ClinicBookings{
+		source:  source,
+		days:    days,
+		clinics: make(map[string][]*apiv1.Identifier),
+		lists:   make(map[string]*clinicList),
+		now:     time.Now,
+	}
+}
+
+// AddClinic records that the user works in the specified clinic
+func (cb *ClinicBookings) AddClinic(user *apiv1.Identifier, clinic *apiv1.Identifier) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.clinics[key(user)] = append(cb.clinics[key(user)], clinic)
+}
+
+// Relationship returns the clinic booking for the patient in one of the user's clinics, if any
+func (cb *ClinicBookings) Relationship(ctx context.Context, user *apiv1.Identifier, patient *apiv1.Identifier) (string, error) {
+	cb.mu.Lock()
+	clinics := cb.clinics[key(user)]
+	cb.mu.Unlock()
+	if len(clinics) == 0 {
+		return "", nil
+	}
+	today := cb.now()
+	dates := make([]time.Time, 0, 2*cb.days+1) // nearest today first
+	dates = append(dates, today)
+	for d := 1; d <= cb.days; d++ {
+		dates = append(dates, today.AddDate(0, 0, d), today.AddDate(0, 0, -d))
+	}
+	var missing []func() (string, error)
+	for _, date := range dates {
+		for _, clinic := range clinics {
+			date, clinic := date, clinic
+			if list, found := cb.cachedList(clinic, date); found {
+				if list.has(patient) {
+					return booking(clinic, date), nil
+				}
+				continue
+			}
+			missing = append(missing, func() (string, error) {
+				list, err := cb.clinicList(ctx, clinic, date)
+				if err != nil || !list.has(patient) {
+					return "", err
+				}
+				return booking(clinic, date), nil
+			})
+		}
+	}
+	for _, check := range missing {
+		if basis, err := check(); err != nil || basis != "" {
+			return basis, err
+		}
+	}
+	return "", nil
+}
+
+func booking(clinic *apiv1.Identifier, date time.Time) string {
+	return "clinic booking: " + clinic.GetValue() + " on " + date.Format("2006-01-02")
+}
+
+func (l *clinicList) has(patient *apiv1.Identifier) bool {
+	_, found := l.patients[key(patient)]
+	return found
+}
+
+func listKey(clinic *apiv1.Identifier, date time.Time) string {
+	return key(clinic) + "/" + date.Format("2006-01-02")
+}
+
+// cachedList returns the patients booked into the clinic on the date, if the list has already been fetched
+func (cb *ClinicBookings) cachedList(clinic *apiv1.Identifier, date time.Time) (*clinicList, bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	list, found := cb.lists[listKey(clinic, date)]
+	if !found || cb.now().Sub(list.fetched) >= clinicListTTL {
+		return nil, false
+	}
+	return list, true
+}
+
+// clinicList returns the patients booked into the clinic on the date, fetching the list if necessary
+func (cb *ClinicBookings) clinicList(ctx context.Context, clinic *apiv1.Identifier, date time.Time) (*clinicList, error) {
+	if list, found := cb.cachedList(clinic, date); found {
+		return list, nil
+	}
+	k := listKey(clinic, date)
+	o, err, _ := cb.fetches.Do(k, func() (interface{}, error) {
+		pts, err := cb.source(ctx, date, []*apiv1.Identifier{clinic})
+		if err != nil {
+			log.Printf("relationship: failed to fetch clinic list for %s on %s: %s", clinic.GetValue(), date.Format("2006-01-02"), err)
+			return nil, err
+		}
+		list := &clinicList{fetched: cb.now(), patients: make(map[string]struct{})}
+		for _, pt := range pts {
+			for _, id := range pt.GetIdentifiers() {
+				list.patients[key(id)] = struct{}{}
+			}
+		}
+		cb.mu.Lock()
+		for lk, l := range cb.lists { // discard expired lists
+			if cb.now().Sub(l.fetched) >= clinicListTTL {
+				delete(cb.lists, lk)
+			}
+		}
+		cb.lists[k] = list
+		cb.mu.Unlock()
+		return list, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return o.(*clinicList), nil
+}
@@ -0,0 +1,174 @@
+// Package relationship provides checks that a user has a legitimate relationship with a patient, such as a
+// clinic booking, referral or team membership, before the user can look up that patient's details.
+package relationship
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/server"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Checker checks for a legitimate relationship between a user and a patient
+type Checker interface {
+	// Relationship returns the basis for the user's relationship with the patient, or an empty string if there is none
+	Relationship(ctx context.Context, user *apiv1.Identifier, patient *apiv1.Identifier) (string, error)
+}
+
+// Policy returns a policy for identifiers.RegisterRelationshipPolicy that checks for a relationship using each
+// checker in turn. Service users, such as other systems, are trusted and need no relationship.
+func Policy(checkers ...Checker) identifiers.RelationshipPolicy {
+	return func(ctx context.Context, id *apiv1.Identifier) (string, error) {
+		ucd := server.GetContextData(ctx)
+		user := ucd.GetAuthenticatedUser()
+		if user == nil {
+			return "", status.Errorf(codes.Unauthenticated, "a relationship with a patient requires an authenticated user")
+		}
+		if user.GetSystem() == identifiers.ConciergeServiceUser {
+			return "service user", nil
+		}
+		for _, c := range checkers {
+			basis, err := c.Relationship(ctx, user, id)
+			if err != nil {
+				return "", err
+			}
+			if basis != "" {
+				return basis, nil
+			}
+		}
+		return "", nil
+	}
+}
+
+func key(id *apiv1.Identifier) string {
+	return id.GetSystem() + "|" + id.GetValue()
+}
+
+// parseIdentifier parses an identifier in the form system|value, or simply a value in the default namespace
+func parseIdentifier(s string, defaultSystem string) (*apiv1.Identifier, error) {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndex(s, "|"); i >= 0 {
+		if i == 0 || i == len(s)-1 {
+			return nil, fmt.Errorf("invalid identifier: '%s'", s)
+		}
+		return &apiv1.Identifier{System: s[:i], Value: s[i+1:]}, nil
+	}
+	if s == "" || defaultSystem == "" {
+		return nil, fmt.Errorf("invalid identifier: '%s': use system|value", s)
+	}
+	return &apiv1.Identifier{System: defaultSystem, Value: s}, nil
+}
+
+type relationship struct {
+	basis   string
+	expires time.Time // zero if the relationship does not expire
+}
+
+// Store is a local store of relationships between users and patients, such as team membership or referrals.
+// This is thread-safe.
+type Store struct {
+	mu            sync.RWMutex
+	relationships map[string]map[string]relationship // by user, then by patient
+}
+
+var _ Checker = (*Store)(nil)
+
+// NewStore creates an empty relationship store
+func NewStore() *Store {
+	return &Store{relationships: make(map[string]map[string]relationship)}
+}
+
+// Add records a relationship between a user and a patient, which expires at the time specified, or never if zero
+func (s *Store) Add(user *apiv1.Identifier, patient *apiv1.Identifier, basis string, expires time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	patients, found := s.relationships[key(user)]
+	if !found {
+		patients = make(map[string]relationship)
+		s.relationships[key(user)] = patients
+	}
+	patients[key(patient)] = relationship{basis: basis, expires: expires}
+}
+
+// Relationship returns the basis of the relationship between the user and patient, if it has not expired
+func (s *Store) Relationship(ctx context.Context, user *apiv1.Identifier, patient *apiv1.Identifier) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if r, found := s.relationships[key(user)][key(patient)]; found && (r.expires.IsZero() || time.Now().Before(r.expires)) {
+		return r.basis, nil
+	}
+	return "", nil
+}
+
+// Len returns the number of relationships in the store
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n := 0
+	for _, patients := range s.relationships {
+		n += len(patients)
+	}
+	return n
+}
+
+// Load reads relationships in CSV format: user, patient, basis and, optionally, an expiry date (YYYY-MM-DD).
+// Users may be given as a NHS Wales username, and patients as a NHS number, or either as system|value.
+// Lines beginning with '#' are ignored.
+func (s *Store) Load(r io.Reader) error {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	for n := 1; ; n++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(record) < 3 || len(record) > 4 {
+			return fmt.Errorf("record %d: expected user, patient, basis and optional expiry", n)
+		}
+		user, err := parseIdentifier(record[0], identifiers.CymruUserID)
+		if err != nil {
+			return fmt.Errorf("record %d: %w", n, err)
+		}
+		patient, err := parseIdentifier(record[1], identifiers.NHSNumber)
+		if err != nil {
+			return fmt.Errorf("record %d: %w", n, err)
+		}
+		var expires time.Time
+		if len(record) == 4 && strings.TrimSpace(record[3]) != "" {
+			if expires, err = time.ParseInLocation("2006-01-02", strings.TrimSpace(record[3]), time.Local); err != nil {
+				return fmt.Errorf("record %d: invalid expiry date: %w", n, err)
+			}
+		}
+		s.Add(user, patient, strings.TrimSpace(record[2]), expires)
+	}
+}
+
+// LoadFile reads relationships from the specified CSV file (see Load)
+func (s *Store) LoadFile(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := s.Load(f); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	log.Printf("relationship: loaded %d relationships from %s", s.Len(), filename)
+	return nil
+}
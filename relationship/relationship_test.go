@@ -0,0 +1,128 @@
+package relationship
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStore(t *testing.T) {
+	s := NewStore()
+	data := `# user, patient, basis, expiry
+ma090906, 1111111111, referral
+ma090906, https://fhir.nhs.uk/Id/nhs-number|2222222222, team, 2000-01-01
+https://concierge.test/Id/user|bob, 3333333333, team
+`
+	if err := s.Load(strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if s.Len() != 3 {
+		t.Fatalf("expected 3 relationships, got %d", s.Len())
+	}
+	user := &apiv1.Identifier{System: identifiers.CymruUserID, Value: "ma090906"}
+	tests := []struct {
+		user    *apiv1.Identifier
+		patient string
+		basis   string
+	}{
+		{user, "1111111111", "referral"},
+		{user, "2222222222", ""}, // expired
+		{user, "3333333333", ""},
+		{&apiv1.Identifier{System: "https://concierge.test/Id/user", Value: "bob"}, "3333333333", "team"},
+	}
+	for _, test := range tests {
+		basis, err := s.Relationship(context.Background(), test.user, &apiv1.Identifier{System: identifiers.NHSNumber, Value: test.patient})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if basis != test.basis {
+			t.Errorf("relationship between %s and %s: expected '%s', got '%s'", test.user.GetValue(), test.patient, test.basis, basis)
+		}
+	}
+	if err := NewStore().Load(strings.NewReader("ma090906, 1111111111\n")); err == nil {
+		t.Fatal("expected error for record without basis")
+	}
+}
+
+func TestClinicBookings(t *testing.T) {
+	today := time.Date(2020, 6, 15, 9, 0, 0, 0, time.Local)
+	fetches := 0
+	source := func(ctx context.Context, date time.Time, clinics []*apiv1.Identifier) ([]*apiv1.Patient, error) {
+		fetches++
+		if clinics[0].GetValue() == "NEUR1" && date.Day() == 17 {
+			return []*apiv1.Patient{{Identifiers: []*apiv1.Identifier{
+				{System: identifiers.CardiffAndValeCRN, Value: "A999998"},
+				{System: identifiers.NHSNumber, Value: "1111111111"},
+			}}}, nil
+		}
+		return nil, nil
+	}
+	cb := NewClinicBookings(source, 2)
+	cb.now = func() time.Time { return today }
+	user := &apiv1.Identifier{System: identifiers.CymruUserID, Value: "ma090906"}
+	cb.AddClinic(user, &apiv1.Identifier{System: identifiers.CardiffAndValeClinicCode, Value: "NEUR1"})
+
+	basis, err := cb.Relationship(context.Background(), user, &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if basis != "clinic booking: NEUR1 on 2020-06-17" {
+		t.Fatalf("unexpected relationship: '%s'", basis)
+	}
+	if fetches != 4 { // dates nearest today are checked first
+		t.Fatalf("expected clinic lists fetched for 15th, 16th, 14th and 17th, got %d fetches", fetches)
+	}
+	for i := 0; i < 2; i++ {
+		if basis, _ := cb.Relationship(context.Background(), user, &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "A999999"}); basis != "" {
+			t.Fatalf("unexpected relationship for unbooked patient: '%s'", basis)
+		}
+		if fetches != 5 { // only the list for the 13th not already fetched
+			t.Fatalf("clinic lists not cached: %d fetches", fetches)
+		}
+	}
+	if basis, _ := cb.Relationship(context.Background(), &apiv1.Identifier{System: identifiers.CymruUserID, Value: "other"}, &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"}); basis != "" {
+		t.Fatalf("unexpected relationship for user without clinics: '%s'", basis)
+	}
+}
+
+func TestClinicBookingsSingleFetch(t *testing.T) {
+	var fetches int32
+	release := make(chan struct{})
+	source := func(ctx context.Context, date time.Time, clinics []*apiv1.Identifier) ([]*apiv1.Patient, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		return nil, nil
+	}
+	cb := NewClinicBookings(source, 0)
+	user := &apiv1.Identifier{System: identifiers.CymruUserID, Value: "ma090906"}
+	cb.AddClinic(user, &apiv1.Identifier{System: identifiers.CardiffAndValeClinicCode, Value: "NEUR1"})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cb.Relationship(context.Background(), user, &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"})
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Fatalf("expected concurrent checks to share a single fetch, got %d fetches", n)
+	}
+}
+
+func TestPolicyRequiresUser(t *testing.T) {
+	p := Policy(NewStore())
+	if _, err := p(context.Background(), &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected unauthenticated, got: %v", err)
+	}
+}
@@ -0,0 +1,324 @@
+// Package resilience provides retries, with jittered exponential backoff, and circuit breakers for calls
+// to outbound backend services, such as the NHS Wales EMPI or the NADEX directory.
+//
+// Each backend endpoint has a named circuit breaker, held in a registry. When a backend fails repeatedly, its
+// breaker opens and calls fail fast with codes.Unavailable, rather than waiting for timeouts, until a trial
+// call after a cooling-off period succeeds.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Policy defines how calls to a backend are retried, and when its circuit breaker opens
+type Policy struct {
+	MaxAttempts      int           // maximum attempts for an idempotent call, including the first
+	InitialBackoff   time.Duration // maximum delay before the first retry; doubled for each subsequent retry
+	MaxBackoff       time.Duration // upper bound on the delay between retries
+	FailureThreshold int           // consecutive failures before the breaker opens; zero to never open
+	OpenTimeout      time.Duration // how long the breaker stays open before permitting a trial call
+	Deadline         time.Duration // upper bound on the total time for a call, including retries; zero for none
+}
+
+// DefaultPolicy is the policy used for backends without a specific configuration
+var DefaultPolicy = Policy{
+	MaxAttempts:      3,
+	InitialBackoff:   100 * time.Millisecond,
+	MaxBackoff:       2 * time.Second,
+	FailureThreshold: 5,
+	OpenTimeout:      30 * time.Second,
+	Deadline:         10 * time.Second,
+}
+
+// State is the state of a circuit breaker
+type State int
+
+// Circuit breaker states
+const (
+	Closed   State = iota // calls are permitted
+	Open                  // calls fail fast
+	HalfOpen              // a single trial call is permitted to test whether the backend has recovered
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+var (
+	registryMu    sync.Mutex
+	defaultPolicy = DefaultPolicy
+	backends      = make(map[string]*Backend)
+)
+
+// Backend is a named backend endpoint with its own circuit breaker
+type Backend struct {
+	name       string
+	mu         sync.Mutex
+	policy     Policy
+	configured bool // policy explicitly configured, rather than the default
+	state      State
+	failures   int
+	openedAt   time.Time
+	trial      bool // a trial call is in progress while half-open
+	now        func() time.Time
+	sleep      func(ctx context.Context, d time.Duration) error
+}
+
+// Get returns the named backend, creating it with the default policy if necessary
+func Get(name string) *Backend {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	b, found := backends[name]
+	if !found {
+		b = &Backend{name: name, policy: defaultPolicy, now: time.Now, sleep: sleep}
+		backends[name] = b
+	}
+	return b
+}
+
+// Lookup returns the named backend, if it has been used or configured
+func Lookup(name string) (*Backend, bool) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	b, found := backends[name]
+	return b, found
+}
+
+// Backends returns the names of all registered backends
+func Backends() []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	result := make([]string, 0, len(backends))
+	for name := range backends {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// SetDefaultPolicy sets the policy for all backends without a specific configuration
+func SetDefaultPolicy(p Policy) {
+	registryMu.Lock()
+	defaultPolicy = p
+	bs := make([]*Backend, 0, len(backends))
+	for _, b := range backends {
+		bs = append(bs, b)
+	}
+	registryMu.Unlock()
+	for _, b := range bs {
+		b.mu.Lock()
+		if !b.configured {
+			b.policy = p
+		}
+		b.mu.Unlock()
+	}
+	log.Printf("resilience: default policy: %s", p)
+}
+
+// Configure sets the policy for the named backend
+func Configure(name string, p Policy) {
+	b := Get(name)
+	b.mu.Lock()
+	b.policy = p
+	b.configured = true
+	b.mu.Unlock()
+	log.Printf("resilience: policy for backend '%s': %s", name, p)
+}
+
+func (p Policy) String() string {
+	return fmt.Sprintf("attempts:%d backoff:%s-%s deadline:%s breaker threshold:%d timeout:%s",
+		p.MaxAttempts, p.InitialBackoff, p.MaxBackoff, p.Deadline, p.FailureThreshold, p.OpenTimeout)
+}
+
+// Name returns the name of the backend
+func (b *Backend) Name() string {
+	return b.name
+}
+
+// State returns the current state of the backend's circuit breaker.
+// An open breaker whose timeout has elapsed is reported as half-open.
+func (b *Backend) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == Open && b.now().Sub(b.openedAt) >= b.policy.OpenTimeout {
+		return HalfOpen
+	}
+	return b.state
+}
+
+// permanentError wraps an error that should not be retried, and that does not indicate a backend failure
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error as permanent, such as a rejected request, so that it is not retried and does not
+// count towards opening the circuit breaker. The original error is returned to the caller of Do.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// isFailure returns whether an error indicates a failure of the backend, rather than a problem with the request
+func isFailure(err error) bool {
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.Unauthenticated, codes.FailedPrecondition, codes.OutOfRange, codes.Unimplemented:
+		return false
+	}
+	return true
+}
+
+// Do calls f, subject to the backend's circuit breaker. Idempotent calls, such as reads, are retried on failure
+// with jittered exponential backoff; other calls are attempted only once. If the breaker is open, Do fails fast
+// with codes.Unavailable without calling f. The context passed to f is cancelled if the caller's context is
+// cancelled, or when the policy's deadline for the call, including any retries, has passed.
+func (b *Backend) Do(ctx context.Context, idempotent bool, f func(ctx context.Context) error) error {
+	b.mu.Lock()
+	policy := b.policy
+	b.mu.Unlock()
+	attempts := 1
+	if idempotent && policy.MaxAttempts > 1 {
+		attempts = policy.MaxAttempts
+	}
+	callCtx := ctx
+	if policy.Deadline > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, policy.Deadline)
+		defer cancel()
+	}
+	for attempt := 1; ; attempt++ {
+		if err := b.allow(); err != nil {
+			return err
+		}
+		err := f(callCtx)
+		if ctx.Err() != nil && err != nil {
+			b.release() // abandoned by the caller, so says nothing about the backend
+			return unwrap(err)
+		}
+		failed := err != nil && isFailure(err)
+		b.record(failed)
+		if !failed || attempt >= attempts || callCtx.Err() != nil {
+			return unwrap(err)
+		}
+		backoff := policy.backoff(attempt)
+		log.Printf("resilience: backend '%s' attempt %d/%d failed: %s: retrying in %s", b.name, attempt, attempts, err, backoff)
+		if sleepErr := b.sleep(callCtx, backoff); sleepErr != nil {
+			if ctx.Err() != nil {
+				return sleepErr
+			}
+			return unwrap(err) // deadline passed, so report the last failure
+		}
+	}
+}
+
+// backoff returns a random delay before the specified retry, using 'full jitter' to avoid synchronised retries
+func (p Policy) backoff(attempt int) time.Duration {
+	max := p.InitialBackoff
+	for i := 1; i < attempt && max < p.MaxBackoff; i++ {
+		max *= 2
+	}
+	if p.MaxBackoff > 0 && max > p.MaxBackoff {
+		max = p.MaxBackoff
+	}
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// allow returns an error if a call is not permitted by the circuit breaker
+func (b *Backend) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case Open:
+		if b.now().Sub(b.openedAt) < b.policy.OpenTimeout {
+			return status.Errorf(codes.Unavailable, "backend '%s' unavailable: circuit breaker open", b.name)
+		}
+		log.Printf("resilience: backend '%s' circuit breaker half-open: permitting trial request", b.name)
+		b.state = HalfOpen
+		b.trial = true
+	case HalfOpen:
+		if b.trial {
+			return status.Errorf(codes.Unavailable, "backend '%s' unavailable: circuit breaker half-open", b.name)
+		}
+		b.trial = true
+	}
+	return nil
+}
+
+// release abandons a call without recording its outcome
+func (b *Backend) release() {
+	b.mu.Lock()
+	b.trial = false
+	b.mu.Unlock()
+}
+
+// record records the outcome of a call, opening or closing the circuit breaker as required
+func (b *Backend) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+	if !failed {
+		if b.state != Closed {
+			log.Printf("resilience: backend '%s' circuit breaker closed", b.name)
+		}
+		b.state = Closed
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == HalfOpen || (b.policy.FailureThreshold > 0 && b.failures >= b.policy.FailureThreshold) {
+		if b.state != Open {
+			log.Printf("resilience: backend '%s' circuit breaker open after %d consecutive failures", b.name, b.failures)
+		}
+		b.state = Open
+		b.openedAt = b.now()
+	}
+}
+
+func unwrap(err error) error {
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return permanent.err
+	}
+	return err
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
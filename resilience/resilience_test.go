@@ -0,0 +1,145 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestBackend(p Policy) (*Backend, *time.Time) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	b := &Backend{name: "test", policy: p, now: func() time.Time { return now }}
+	b.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+	return b, &now
+}
+
+func TestRetry(t *testing.T) {
+	b, _ := newTestBackend(Policy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Second})
+	calls := 0
+	err := b.Do(context.Background(), true, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("expected success after three attempts, got %v after %d", err, calls)
+	}
+	calls = 0
+	err = b.Do(context.Background(), false, func(ctx context.Context) error {
+		calls++
+		return errors.New("connection refused")
+	})
+	if err == nil || calls != 1 {
+		t.Fatalf("non-idempotent call should be attempted once; got %d attempts", calls)
+	}
+	calls = 0
+	notFound := status.Error(codes.NotFound, "not found")
+	if err := b.Do(context.Background(), true, func(ctx context.Context) error { calls++; return notFound }); err != notFound || calls != 1 {
+		t.Fatalf("not found should not be retried: got %v after %d attempts", err, calls)
+	}
+	calls = 0
+	rejected := errors.New("rejected")
+	if err := b.Do(context.Background(), true, func(ctx context.Context) error { calls++; return Permanent(rejected) }); err != rejected || calls != 1 {
+		t.Fatalf("permanent error should not be retried and should be unwrapped: got %v after %d attempts", err, calls)
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	b, _ := newTestBackend(Policy{MaxAttempts: 10, FailureThreshold: 100, Deadline: 50 * time.Millisecond})
+	calls := 0
+	slow := func(ctx context.Context) error {
+		calls++
+		<-ctx.Done()
+		return status.Error(codes.DeadlineExceeded, "timeout")
+	}
+	start := time.Now()
+	if err := b.Do(context.Background(), true, slow); status.Code(err) != codes.DeadlineExceeded || calls != 1 {
+		t.Fatalf("expected call to stop at deadline without retries, got %v after %d attempts", err, calls)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("call not bounded by deadline: %s", elapsed)
+	}
+	if b.failures != 1 {
+		t.Fatalf("call exceeding deadline should count as a failure, got %d failures", b.failures)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Do(ctx, true, slow); err == nil || b.failures != 1 {
+		t.Fatalf("call abandoned by caller should not count as a failure, got %d failures", b.failures)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := Policy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	for attempt := 1; attempt < 10; attempt++ {
+		for i := 0; i < 100; i++ {
+			d := p.backoff(attempt)
+			if d < 0 || d >= p.MaxBackoff {
+				t.Fatalf("backoff for attempt %d out of range: %s", attempt, d)
+			}
+			if attempt == 1 && d >= p.InitialBackoff {
+				t.Fatalf("first backoff out of range: %s", d)
+			}
+		}
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	b, now := newTestBackend(Policy{MaxAttempts: 1, FailureThreshold: 2, OpenTimeout: 10 * time.Second})
+	failing := func(ctx context.Context) error { return errors.New("timeout") }
+	calls := 0
+	ok := func(ctx context.Context) error { calls++; return nil }
+	b.Do(context.Background(), true, failing)
+	if b.State() != Closed {
+		t.Fatalf("breaker opened before threshold")
+	}
+	b.Do(context.Background(), true, failing)
+	if b.State() != Open {
+		t.Fatalf("breaker not open after threshold: %s", b.State())
+	}
+	err := b.Do(context.Background(), true, ok)
+	if status.Code(err) != codes.Unavailable || calls != 0 {
+		t.Fatalf("open breaker should fail fast with unavailable: got %v", err)
+	}
+	*now = now.Add(11 * time.Second)
+	if b.State() != HalfOpen {
+		t.Fatalf("breaker not half-open after timeout: %s", b.State())
+	}
+	b.Do(context.Background(), true, failing)
+	if b.State() != Open {
+		t.Fatalf("failed trial should re-open breaker: %s", b.State())
+	}
+	*now = now.Add(11 * time.Second)
+	if err := b.Do(context.Background(), true, ok); err != nil || calls != 1 {
+		t.Fatalf("trial call not permitted: %v", err)
+	}
+	if b.State() != Closed {
+		t.Fatalf("successful trial should close breaker: %s", b.State())
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	b := Get("registry-test")
+	if b2, found := Lookup("registry-test"); !found || b2 != b {
+		t.Fatal("backend not registered")
+	}
+	if _, found := Lookup("registry-missing"); found {
+		t.Fatal("unexpected backend")
+	}
+	p := Policy{MaxAttempts: 7}
+	Configure("registry-configured", p)
+	SetDefaultPolicy(Policy{MaxAttempts: 2})
+	defer SetDefaultPolicy(DefaultPolicy)
+	if got := Get("registry-configured").policy.MaxAttempts; got != 7 {
+		t.Fatalf("configured policy overwritten by default: %d", got)
+	}
+	if got := b.policy.MaxAttempts; got != 2 {
+		t.Fatalf("default policy not applied: %d", got)
+	}
+}
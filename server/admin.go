@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Admin provides runtime introspection and control of a server, restricted to administrators.
+type Admin struct {
+	sv      *Server
+	version string
+	started time.Time
+}
+
+var _ apiv1.AdminServer = (*Admin)(nil)
+
+// NewAdmin creates a new administrative service for the specified server
+func NewAdmin(sv *Server, version string) *Admin {
+	return &Admin{
+		sv:      sv,
+		version: version,
+		started: time.Now(),
+	}
+}
+
+// RegisterServer registers this server
+func (a *Admin) RegisterServer(s *grpc.Server) {
+	apiv1.RegisterAdminServer(s, a)
+}
+
+// RegisterHTTPProxy registers this as a reverse HTTP proxy
+func (a *Admin) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	return apiv1.RegisterAdminHandlerFromEndpoint(ctx, mux, endpoint, opts)
+}
+
+// Close closes any linked resources
+func (a *Admin) Close() error { return nil }
+
+// authorize checks that the user making the request is an administrator
+func (a *Admin) authorize(ctx context.Context) error {
+	if a.sv.auth == nil {
+		return status.Error(codes.PermissionDenied, "administrative services require authentication")
+	}
+	user := GetContextData(ctx).GetAuthenticatedUser()
+	if !a.sv.auth.IsAdministrator(user) {
+		log.Printf("admin: permission denied for '%s|%s'", user.GetSystem(), user.GetValue())
+		return status.Errorf(codes.PermissionDenied, "'%s|%s' is not an administrator", user.GetSystem(), user.GetValue())
+	}
+	return nil
+}
+
+// GetServerStatus returns the build version and authentication status of the server
+func (a *Admin) GetServerStatus(ctx context.Context, r *apiv1.ServerStatusRequest) (*apiv1.ServerStatus, error) {
+	if err := a.authorize(ctx); err != nil {
+		return nil, err
+	}
+	started, err := ptypes.TimestampProto(a.started)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.ServerStatus{
+		Version:      a.version,
+		Started:      started,
+		TokenSigning: a.sv.auth.tokenSigningStatus(),
+		ApiKeys:      a.sv.auth.apiKeyUsageStatus(),
+	}, nil
+}
+
+// ListProviders returns the registered providers and their health
+func (a *Admin) ListProviders(ctx context.Context, r *apiv1.ListProvidersRequest) (*apiv1.ListProvidersResponse, error) {
+	if err := a.authorize(ctx); err != nil {
+		return nil, err
+	}
+	result := new(apiv1.ListProvidersResponse)
+	for name, p := range a.sv.providers {
+		ps := &apiv1.ProviderStatus{Name: name, Healthy: true}
+		if hc, ok := p.(HealthChecker); ok {
+			if err := hc.CheckHealth(ctx); err != nil {
+				ps.Healthy = false
+				ps.Message = err.Error()
+			}
+		}
+		result.Providers = append(result.Providers, ps)
+	}
+	sort.Slice(result.Providers, func(i, j int) bool { return result.Providers[i].Name < result.Providers[j].Name })
+	return result, nil
+}
+
+// ListRegistrations returns the registered identifier systems, resolvers and mappers
+func (a *Admin) ListRegistrations(ctx context.Context, r *apiv1.ListRegistrationsRequest) (*apiv1.ListRegistrationsResponse, error) {
+	if err := a.authorize(ctx); err != nil {
+		return nil, err
+	}
+	result := &apiv1.ListRegistrationsResponse{
+		Resolvers: identifiers.Resolvers(),
+		Mappers:   identifiers.Mappers(),
+	}
+	sort.Strings(result.Mappers)
+	for _, uri := range identifiers.Systems() {
+		if system, found := identifiers.Lookup(uri); found {
+			result.Systems = append(result.Systems, system)
+		}
+	}
+	return result, nil
+}
+
+// ListCaches returns the registered caches
+func (a *Admin) ListCaches(ctx context.Context, r *apiv1.ListCachesRequest) (*apiv1.ListCachesResponse, error) {
+	if err := a.authorize(ctx); err != nil {
+		return nil, err
+	}
+	a.sv.mu.Lock()
+	defer a.sv.mu.Unlock()
+	result := new(apiv1.ListCachesResponse)
+	for name, c := range a.sv.caches {
+		result.Caches = append(result.Caches, &apiv1.CacheStatus{Name: name, Items: int64(c.ItemCount())})
+	}
+	sort.Slice(result.Caches, func(i, j int) bool { return result.Caches[i].Name < result.Caches[j].Name })
+	return result, nil
+}
+
+// ListUsage returns today's usage by client and by rate-limited backend
+func (a *Admin) ListUsage(ctx context.Context, r *apiv1.ListUsageRequest) (*apiv1.ListUsageResponse, error) {
+	if err := a.authorize(ctx); err != nil {
+		return nil, err
+	}
+	a.sv.mu.Lock()
+	limits := a.sv.limits
+	a.sv.mu.Unlock()
+	if limits == nil {
+		return &apiv1.ListUsageResponse{Date: time.Now().UTC().Format("2006-01-02")}, nil
+	}
+	return limits.usage(), nil
+}
+
+// FlushCache removes all items from the named cache
+func (a *Admin) FlushCache(ctx context.Context, r *apiv1.FlushCacheRequest) (*apiv1.CacheStatus, error) {
+	if err := a.authorize(ctx); err != nil {
+		return nil, err
+	}
+	a.sv.mu.Lock()
+	c, found := a.sv.caches[r.GetName()]
+	a.sv.mu.Unlock()
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "no cache named '%s'", r.GetName())
+	}
+	user := GetContextData(ctx).GetAuthenticatedUser()
+	log.Printf("admin: flushing cache '%s' (%d items) at request of '%s|%s'", r.GetName(), c.ItemCount(), user.GetSystem(), user.GetValue())
+	c.Flush()
+	return &apiv1.CacheStatus{Name: r.GetName(), Items: int64(c.ItemCount())}, nil
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type testCache struct {
+	items int
+}
+
+func (c *testCache) ItemCount() int { return c.items }
+func (c *testCache) Flush()         { c.items = 0 }
+
+func TestAdmin(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin := &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "ops"}
+	auth.RegisterAdministrator(admin)
+	sv := New(Options{})
+	sv.RegisterAuthenticator(auth)
+	cache := &testCache{items: 10}
+	sv.RegisterCache("test", cache)
+	a := NewAdmin(sv, "test-version")
+
+	userCtx := context.WithValue(context.Background(), userContextKey, &UserContextData{
+		authenticatedUser: &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "client"},
+	})
+	if _, err := a.FlushCache(userCtx, &apiv1.FlushCacheRequest{Name: "test"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected permission denied for non-administrator, got: %v", err)
+	}
+	if cache.items != 10 {
+		t.Fatal("cache flushed by non-administrator")
+	}
+	adminCtx := context.WithValue(context.Background(), userContextKey, &UserContextData{authenticatedUser: admin})
+	ss, err := a.GetServerStatus(adminCtx, &apiv1.ServerStatusRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ss.GetVersion() != "test-version" || !ss.GetTokenSigning().GetTemporaryKey() {
+		t.Fatalf("unexpected server status: %v", ss)
+	}
+	cs, err := a.FlushCache(adminCtx, &apiv1.FlushCacheRequest{Name: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cs.GetItems() != 0 || cache.items != 0 {
+		t.Fatalf("cache not flushed: %v", cs)
+	}
+	if _, err := a.FlushCache(adminCtx, &apiv1.FlushCacheRequest{Name: "missing"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found for missing cache, got: %v", err)
+	}
+}
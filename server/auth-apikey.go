@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// apiKeyHeader is the header (or gRPC metadata) in which a client sends its API key
+const apiKeyHeader = "x-api-key"
+
+// APIKey is a static key permitting read-only access to identifier resolution and mapping for
+// a restricted set of namespaces, without needing to login.
+type APIKey struct {
+	Name   string
+	Scopes []string // namespace uris that may be resolved or mapped using this key
+}
+
+// APIKeyStore looks up API keys by their hash (see HashAPIKey)
+type APIKeyStore interface {
+	APIKey(hash string) (*APIKey, error) // returns nil if there is no matching key
+}
+
+// apiKeyMethods are the only methods that may be called using an API key
+var apiKeyMethods = map[string]struct{}{
+	"/apiv1.Identifiers/GetIdentifier": struct{}{},
+	"/apiv1.Identifiers/MapIdentifier": struct{}{},
+}
+
+// apiKeyUsage records the use of an API key
+type apiKeyUsage struct {
+	count    uint64
+	lastUsed time.Time
+}
+
+// GenerateAPIKey generates a random API key, returning the key, to be given to the client, and its hash, to be stored
+func GenerateAPIKey() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	key := base64.RawURLEncoding.EncodeToString(b)
+	return key, HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hash of an API key.
+// As keys are long and random, a fast hash is sufficient, permitting keys to be checked on every request.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// staticAPIKeys is an API key store for a fixed set of keys, such as from a configuration file
+type staticAPIKeys map[string]*APIKey
+
+// NewStaticAPIKeyStore creates an API key store from the specified keys, by hash
+func NewStaticAPIKeyStore(keys map[string]*APIKey) APIKeyStore {
+	return staticAPIKeys(keys)
+}
+
+func (keys staticAPIKeys) APIKey(hash string) (*APIKey, error) {
+	return keys[hash], nil
+}
+
+// RegisterAPIKeyStore registers a source of API keys. Keys are looked up in each store in the order registered.
+func (auth *Auth) RegisterAPIKeyStore(name string, store APIKeyStore) {
+	auth.apiKeyStores = append(auth.apiKeyStores, store)
+	log.Printf("auth: registered api key store: %s", name)
+}
+
+// userFromAPIKey returns user context data for a caller that has sent a valid API key, or nil
+func (auth *Auth) userFromAPIKey(ctx context.Context) (*UserContextData, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	keys := md.Get(apiKeyHeader)
+	if len(keys) == 0 || len(auth.apiKeyStores) == 0 {
+		return nil, nil
+	}
+	hash := HashAPIKey(keys[0])
+	for _, store := range auth.apiKeyStores {
+		key, err := store.APIKey(hash)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			continue
+		}
+		auth.statsMu.Lock()
+		usage := auth.apiKeyUsage[key.Name]
+		if usage == nil {
+			usage = new(apiKeyUsage)
+			auth.apiKeyUsage[key.Name] = usage
+		}
+		usage.count++
+		usage.lastUsed = time.Now()
+		auth.statsMu.Unlock()
+		scopes := make(map[string]struct{}, len(key.Scopes))
+		for _, scope := range key.Scopes {
+			scopes[scope] = struct{}{}
+		}
+		return &UserContextData{
+			authenticatedUser: &apiv1.Identifier{System: identifiers.ConciergeAPIKey, Value: key.Name},
+			scopes:            scopes,
+		}, nil
+	}
+	auth.recordFailedLogin()
+	return nil, ErrInvalidToken
+}
+
+// apiKeyUsageStatus returns the usage of API keys since the server was started
+func (auth *Auth) apiKeyUsageStatus() []*apiv1.ApiKeyUsage {
+	auth.statsMu.Lock()
+	defer auth.statsMu.Unlock()
+	result := make([]*apiv1.ApiKeyUsage, 0, len(auth.apiKeyUsage))
+	for name, usage := range auth.apiKeyUsage {
+		lastUsed, _ := ptypes.TimestampProto(usage.lastUsed)
+		result = append(result, &apiv1.ApiKeyUsage{Name: name, Count: usage.count, LastUsed: lastUsed})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// permits checks that a user restricted to a set of scopes, such as a caller using an API key, may make the
+// specified request. Users without restricted scopes are permitted to make any request.
+func (ucd *UserContextData) permits(method string, req interface{}) error {
+	if ucd == nil || ucd.scopes == nil {
+		return nil
+	}
+	if _, found := apiKeyMethods[method]; !found {
+		return status.Errorf(codes.PermissionDenied, "api key '%s' not permitted to call %s", ucd.authenticatedUser.GetValue(), method)
+	}
+	var systems []string
+	switch r := req.(type) {
+	case *apiv1.Identifier:
+		systems = []string{r.GetSystem()}
+	case *apiv1.IdentifierMapRequest:
+		systems = []string{r.GetSystem(), r.GetTargetUri()}
+	default:
+		return status.Errorf(codes.PermissionDenied, "api key '%s' not permitted to make request %T", ucd.authenticatedUser.GetValue(), req)
+	}
+	for _, system := range systems {
+		if _, found := ucd.scopes[system]; !found {
+			return status.Errorf(codes.PermissionDenied, "api key '%s' not permitted to access '%s'", ucd.authenticatedUser.GetValue(), system)
+		}
+	}
+	return nil
+}
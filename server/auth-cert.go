@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// clientCertificateTokenDuration is the lifetime of the token used to pass the identity of a
+// client authenticated by certificate through the REST gateway
+const clientCertificateTokenDuration = time.Minute
+
+// RegisterClientCertificate maps a client certificate to an account, so that a caller presenting a
+// verified certificate is authenticated as that account without needing to login.
+// The name is matched against the certificate's subject alternative names (URI, DNS and email) and
+// its subject common name.
+// Client certificates are only requested if the server is configured with client CA certificates.
+func (auth *Auth) RegisterClientCertificate(name string, id *apiv1.Identifier) {
+	auth.clientCerts[name] = id
+	log.Printf("auth: registered client certificate '%s' for '%s|%s'", name, id.GetSystem(), id.GetValue())
+}
+
+// clientCertificateIdentity returns the account registered for the given verified client certificate, or nil
+func (auth *Auth) clientCertificateIdentity(cert *x509.Certificate) *apiv1.Identifier {
+	names := make([]string, 0, len(cert.URIs)+len(cert.DNSNames)+len(cert.EmailAddresses)+1)
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	names = append(names, cert.DNSNames...)
+	names = append(names, cert.EmailAddresses...)
+	names = append(names, cert.Subject.CommonName)
+	for _, name := range names {
+		if id, found := auth.clientCerts[name]; found && name != "" {
+			return id
+		}
+	}
+	log.Printf("auth: no account registered for client certificate '%s'", cert.Subject)
+	return nil
+}
+
+// userFromPeer returns user context data for a caller that has presented a verified, registered, client
+// certificate, or nil
+func (auth *Auth) userFromPeer(ctx context.Context) *UserContextData {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	id := auth.clientCertificateIdentity(cert)
+	if id == nil {
+		return nil
+	}
+	return &UserContextData{authenticatedUser: id, tokenExpiresAt: cert.NotAfter}
+}
+
+// clientCertificateHandler wraps a HTTP handler for the REST gateway so that requests with a verified, registered,
+// client certificate, and no authorization header, are passed to the gRPC server with a short-lived token
+// for that account; the gateway connects in-process and so cannot itself present the client's certificate.
+func (auth *Auth) clientCertificateHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+			if id := auth.clientCertificateIdentity(r.TLS.VerifiedChains[0][0]); id != nil {
+				token, err := auth.signToken(id, clientCertificateTokenDuration)
+				if err != nil {
+					log.Printf("auth: failed to generate token for client certificate: %s", err)
+					http.Error(w, "internal error", http.StatusInternalServerError)
+					return
+				}
+				r.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -2,7 +2,10 @@ package server
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	_ "github.com/lib/pq"
@@ -11,50 +14,326 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-type dbAuthProvider struct {
+// ErrServiceAccountNotFound is returned when a service account does not exist
+var ErrServiceAccountNotFound = errors.New("service account not found")
+
+// DatabaseAuthProvider is an auth provider that uses a PostgreSQL database to store and validate service account credentials
+// and API keys
+type DatabaseAuthProvider struct {
 	db *sql.DB
 }
 
-// NewDatabaseAuthProvider is an auth provider that uses a PostgreSQL database to validate credentials
-func NewDatabaseAuthProvider(connStr string) (AuthProvider, error) {
+// ServiceAccount is a service account stored in the authentication database
+type ServiceAccount struct {
+	Username    string
+	Description string
+	Enabled     bool
+	Created     time.Time
+	Expires     time.Time // zero if the account does not expire
+	LastLogin   time.Time // zero if the account has never been used
+}
+
+// Expired returns whether the account has expired at the specified time
+func (sa *ServiceAccount) Expired(t time.Time) bool {
+	return !sa.Expires.IsZero() && !t.Before(sa.Expires)
+}
+
+// NewDatabaseAuthProvider is an auth provider that uses a PostgreSQL database to validate credentials.
+// The connection is retried until the database is available, and the schema migrated to the latest version.
+func NewDatabaseAuthProvider(connStr string) (*DatabaseAuthProvider, error) {
 	for {
-		db, err := sql.Open("postgres", connStr)
-		if err != nil {
-			goto dberror
-		}
-		err = db.Ping()
-		if err != nil {
-			goto dberror
+		dba, err := OpenDatabaseAuthProvider(connStr)
+		if err == nil {
+			return dba, nil
 		}
-		return &dbAuthProvider{
-			db: db,
-		}, nil
-	dberror:
 		log.Println(err)
 		log.Println("auth: error connecting to the authentication database, retrying in 5 secs.")
 		time.Sleep(5 * time.Second)
 	}
 }
 
-func (dba *dbAuthProvider) Authenticate(id *apiv1.Identifier, credential string) (bool, error) {
-	rows, err := dba.db.Query("SELECT password FROM users WHERE username=$1", id.GetValue())
+// OpenDatabaseAuthProvider opens the authentication database, without retrying, and migrates
+// the schema to the latest version.
+func OpenDatabaseAuthProvider(connStr string) (*DatabaseAuthProvider, error) {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	dba := &DatabaseAuthProvider{db: db}
+	if err := dba.Migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return dba, nil
+}
+
+// Close closes the database
+func (dba *DatabaseAuthProvider) Close() error {
+	return dba.db.Close()
+}
+
+// migrations are the schema migrations for the authentication database, applied in order.
+// These must never be changed once released; add a new migration instead.
+// The first migration is compatible with a pre-existing, manually created, users table.
+var migrations = []string{
+	`CREATE TABLE IF NOT EXISTS users (
+		username text PRIMARY KEY,
+		password text NOT NULL
+	)`,
+	`ALTER TABLE users
+		ADD COLUMN IF NOT EXISTS description text NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS enabled boolean NOT NULL DEFAULT true,
+		ADD COLUMN IF NOT EXISTS created timestamptz NOT NULL DEFAULT now(),
+		ADD COLUMN IF NOT EXISTS expires timestamptz,
+		ADD COLUMN IF NOT EXISTS last_login timestamptz`,
+	`CREATE TABLE IF NOT EXISTS api_keys (
+		name text PRIMARY KEY,
+		hash text NOT NULL UNIQUE,
+		scopes text NOT NULL,
+		created timestamptz NOT NULL DEFAULT now()
+	)`,
+}
+
+// migrationLock is the key of the advisory lock held while migrating, so that replicas started together do not
+// apply the same migration concurrently
+const migrationLock = 0x636f6e63 // "conc"
+
+// Migrate migrates the authentication database schema to the latest version.
+// Migrations are applied in a single transaction holding an advisory lock, so that servers started concurrently
+// against the same database wait for, rather than race, each other.
+func (dba *DatabaseAuthProvider) Migrate() error {
+	tx, err := dba.db.Begin()
 	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", migrationLock); err != nil {
+		return fmt.Errorf("auth: failed to lock database for migration: %w", err)
+	}
+	if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version integer PRIMARY KEY,
+		applied timestamptz NOT NULL DEFAULT now()
+	)`); err != nil {
+		return fmt.Errorf("auth: failed to create migrations table: %w", err)
+	}
+	var current int
+	if err := tx.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&current); err != nil {
+		return err
+	}
+	for i := current; i < len(migrations); i++ {
+		version := i + 1
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			return fmt.Errorf("auth: failed to apply migration %d: %w", version, err)
+		}
+		if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES ($1)", version); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if current < len(migrations) {
+		log.Printf("auth: migrated authentication database from version %d to %d", current, len(migrations))
+	}
+	return nil
+}
+
+var _ AccountStore = (*DatabaseAuthProvider)(nil)
+
+// Authenticate checks the credentials for an enabled, unexpired, service account, recording the time of
+// a successful login.
+func (dba *DatabaseAuthProvider) Authenticate(id *apiv1.Identifier, credential string) (bool, error) {
+	hash, active, err := dba.account(id)
+	if err != nil || !active {
 		return false, err
 	}
-	defer rows.Close()
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(credential)); err != nil {
+		return false, err
+	}
+	dba.RecordLogin(id)
+	return true, nil
+}
+
+// CheckAccount returns whether the service account exists, is enabled and has not expired
+func (dba *DatabaseAuthProvider) CheckAccount(id *apiv1.Identifier) (bool, error) {
+	_, active, err := dba.account(id)
+	return active, err
+}
+
+// RecordLogin records the time of a successful login to the service account
+func (dba *DatabaseAuthProvider) RecordLogin(id *apiv1.Identifier) {
+	if _, err := dba.db.Exec("UPDATE users SET last_login=now() WHERE username=$1", id.GetValue()); err != nil {
+		log.Printf("auth: failed to record last login for %s|%s: %s", id.GetSystem(), id.GetValue(), err)
+	}
+}
+
+// account returns the password hash of the service account, and whether it exists, is enabled and has not expired
+func (dba *DatabaseAuthProvider) account(id *apiv1.Identifier) (string, bool, error) {
 	var hash string
+	var enabled bool
+	var expires sql.NullTime
+	err := dba.db.QueryRow("SELECT password, enabled, expires FROM users WHERE username=$1", id.GetValue()).Scan(&hash, &enabled, &expires)
+	if err == sql.ErrNoRows {
+		log.Printf("auth: no user found matching %s|%s", id.GetSystem(), id.GetValue())
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	if !enabled {
+		log.Printf("auth: account %s|%s is disabled", id.GetSystem(), id.GetValue())
+		return "", false, nil
+	}
+	if expires.Valid && !time.Now().Before(expires.Time) {
+		log.Printf("auth: account %s|%s expired %s", id.GetSystem(), id.GetValue(), expires.Time)
+		return "", false, nil
+	}
+	return hash, true, nil
+}
+
+// CreateServiceAccount creates a new service account with a randomly generated password, which is returned.
+// A zero expiry time creates an account that does not expire.
+func (dba *DatabaseAuthProvider) CreateServiceAccount(username string, description string, expires time.Time) (string, error) {
+	password, hash, err := GenerateCredentials()
+	if err != nil {
+		return "", err
+	}
+	if _, err := dba.db.Exec("INSERT INTO users (username, password, description, expires) VALUES ($1, $2, $3, $4)",
+		username, hash, description, nullTime(expires)); err != nil {
+		return "", err
+	}
+	log.Printf("auth: created service account '%s'", username)
+	return password, nil
+}
+
+// ServiceAccounts returns all service accounts, ordered by username
+func (dba *DatabaseAuthProvider) ServiceAccounts() ([]*ServiceAccount, error) {
+	rows, err := dba.db.Query("SELECT username, description, enabled, created, expires, last_login FROM users ORDER BY username")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make([]*ServiceAccount, 0)
 	for rows.Next() {
-		if err := rows.Scan(&hash); err != nil {
-			return false, err
+		sa := new(ServiceAccount)
+		var expires, lastLogin sql.NullTime
+		if err := rows.Scan(&sa.Username, &sa.Description, &sa.Enabled, &sa.Created, &expires, &lastLogin); err != nil {
+			return nil, err
 		}
-		if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(credential)); err != nil {
-			return false, err
+		sa.Expires = expires.Time
+		sa.LastLogin = lastLogin.Time
+		result = append(result, sa)
+	}
+	return result, rows.Err()
+}
+
+// SetEnabled enables or disables the specified service account
+func (dba *DatabaseAuthProvider) SetEnabled(username string, enabled bool) error {
+	log.Printf("auth: setting service account '%s' enabled: %t", username, enabled)
+	return dba.update("UPDATE users SET enabled=$2 WHERE username=$1", username, enabled)
+}
+
+// SetExpiry sets the expiry of the specified service account. A zero time removes any expiry.
+func (dba *DatabaseAuthProvider) SetExpiry(username string, expires time.Time) error {
+	log.Printf("auth: setting service account '%s' expiry: %s", username, expires)
+	return dba.update("UPDATE users SET expires=$2 WHERE username=$1", username, nullTime(expires))
+}
+
+// RotatePassword replaces the password of the specified service account with a new randomly generated password,
+// which is returned.
+func (dba *DatabaseAuthProvider) RotatePassword(username string) (string, error) {
+	password, hash, err := GenerateCredentials()
+	if err != nil {
+		return "", err
+	}
+	if err := dba.update("UPDATE users SET password=$2 WHERE username=$1", username, hash); err != nil {
+		return "", err
+	}
+	log.Printf("auth: rotated password for service account '%s'", username)
+	return password, nil
+}
+
+// CreateAPIKey creates a new API key permitting access to the specified namespaces, returning the key
+func (dba *DatabaseAuthProvider) CreateAPIKey(name string, scopes []string) (string, error) {
+	key, hash, err := GenerateAPIKey()
+	if err != nil {
+		return "", err
+	}
+	if _, err := dba.db.Exec("INSERT INTO api_keys (name, hash, scopes) VALUES ($1, $2, $3)", name, hash, strings.Join(scopes, " ")); err != nil {
+		return "", err
+	}
+	log.Printf("auth: created api key '%s' for %v", name, scopes)
+	return key, nil
+}
+
+// APIKey returns the API key with the specified hash, or nil if there is no such key
+func (dba *DatabaseAuthProvider) APIKey(hash string) (*APIKey, error) {
+	key := new(APIKey)
+	var scopes string
+	err := dba.db.QueryRow("SELECT name, scopes FROM api_keys WHERE hash=$1", hash).Scan(&key.Name, &scopes)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	key.Scopes = strings.Fields(scopes)
+	return key, nil
+}
+
+// APIKeys returns all API keys, ordered by name
+func (dba *DatabaseAuthProvider) APIKeys() ([]*APIKey, error) {
+	rows, err := dba.db.Query("SELECT name, scopes FROM api_keys ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make([]*APIKey, 0)
+	for rows.Next() {
+		key := new(APIKey)
+		var scopes string
+		if err := rows.Scan(&key.Name, &scopes); err != nil {
+			return nil, err
 		}
-		return true, nil
+		key.Scopes = strings.Fields(scopes)
+		result = append(result, key)
 	}
-	if err := rows.Err(); err != nil {
-		return false, err
+	return result, rows.Err()
+}
+
+// RevokeAPIKey deletes the named API key
+func (dba *DatabaseAuthProvider) RevokeAPIKey(name string) error {
+	result, err := dba.db.Exec("DELETE FROM api_keys WHERE name=$1", name)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("api key not found: '%s'", name)
+	}
+	log.Printf("auth: revoked api key '%s'", name)
+	return nil
+}
+
+// update executes the update for a single service account, returning ErrServiceAccountNotFound if no account updated
+func (dba *DatabaseAuthProvider) update(query string, username string, args ...interface{}) error {
+	result, err := dba.db.Exec(query, append([]interface{}{username}, args...)...)
+	if err != nil {
+		return err
 	}
-	log.Printf("auth: no user found matching %s|%s", id.GetSystem(), id.GetValue())
-	return false, nil
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: '%s'", ErrServiceAccountNotFound, username)
+	}
+	return nil
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
 }
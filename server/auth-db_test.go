@@ -0,0 +1,207 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func expectMigration(mock sqlmock.Sqlmock, current int) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).WithArgs(migrationLock).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(version), 0) FROM schema_migrations")).WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(current))
+	for i := current; i < len(migrations); i++ {
+		mock.ExpectExec(regexp.QuoteMeta(migrations[i])).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(i + 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+}
+
+func TestMigrate(t *testing.T) {
+	for _, current := range []int{0, 1, len(migrations)} {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectMigration(mock, current)
+		dba := &DatabaseAuthProvider{db: db}
+		if err := dba.Migrate(); err != nil {
+			t.Fatalf("failed to migrate from version %d: %s", current, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("migration from version %d: %s", current, err)
+		}
+		db.Close()
+	}
+}
+
+func TestDatabaseAuthenticate(t *testing.T) {
+	password, hash, err := GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	dba := &DatabaseAuthProvider{db: db}
+	id := &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "test"}
+	query := regexp.QuoteMeta("SELECT password, enabled, expires FROM users WHERE username=$1")
+	columns := []string{"password", "enabled", "expires"}
+	tests := []struct {
+		name     string
+		enabled  bool
+		expires  interface{}
+		password string
+		success  bool
+	}{
+		{"valid", true, nil, password, true},
+		{"unexpired", true, time.Now().Add(time.Hour), password, true},
+		{"disabled", false, nil, password, false},
+		{"expired", true, time.Now().Add(-time.Hour), password, false},
+		{"incorrect password", true, nil, "wibble", false},
+	}
+	for _, test := range tests {
+		mock.ExpectQuery(query).WithArgs("test").WillReturnRows(sqlmock.NewRows(columns).AddRow(hash, test.enabled, test.expires))
+		if test.success {
+			mock.ExpectExec("UPDATE users SET last_login").WithArgs("test").WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		if ok, _ := dba.Authenticate(id, test.password); ok != test.success {
+			t.Errorf("%s: expected authentication %t, got %t", test.name, test.success, ok)
+		}
+	}
+	mock.ExpectQuery(query).WithArgs("test").WillReturnRows(sqlmock.NewRows(columns))
+	if ok, err := dba.Authenticate(id, password); ok || err != nil {
+		t.Errorf("unknown account: expected authentication to fail without error, got %t (%v)", ok, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRefreshDisabledAccount(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	auth.RegisterAuthProvider(identifiers.ConciergeServiceUser, "test-db", &DatabaseAuthProvider{db: db}, true)
+	id := &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "test"}
+	ctx := context.WithValue(context.Background(), userContextKey, &UserContextData{authenticatedUser: id})
+	query := regexp.QuoteMeta("SELECT password, enabled, expires FROM users WHERE username=$1")
+	columns := []string{"password", "enabled", "expires"}
+
+	mock.ExpectQuery(query).WithArgs("test").WillReturnRows(sqlmock.NewRows(columns).AddRow("hash", true, nil))
+	if _, err := auth.Refresh(ctx, &apiv1.TokenRefreshRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	mock.ExpectQuery(query).WithArgs("test").WillReturnRows(sqlmock.NewRows(columns).AddRow("hash", false, nil))
+	if _, err := auth.Refresh(ctx, &apiv1.TokenRefreshRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected disabled account to be refused a refreshed token, got: %v", err)
+	}
+	mock.ExpectQuery(query).WithArgs("test").WillReturnRows(sqlmock.NewRows(columns).AddRow("hash", true, time.Now().Add(-time.Hour)))
+	if _, err := auth.Refresh(ctx, &apiv1.TokenRefreshRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected expired account to be refused a refreshed token, got: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJWTAssertionDisabledAccount(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ap := NewJWTAssertionAuthProvider("concierge", &DatabaseAuthProvider{db: db})
+	if err := ap.RegisterPublicKey("test", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})); err != nil {
+		t.Fatal(err)
+	}
+	id := &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "test"}
+	query := regexp.QuoteMeta("SELECT password, enabled, expires FROM users WHERE username=$1")
+	columns := []string{"password", "enabled", "expires"}
+	tests := []struct {
+		name    string
+		enabled bool
+		expires interface{}
+		success bool
+	}{
+		{"valid", true, nil, true},
+		{"disabled", false, nil, false},
+		{"expired", true, time.Now().Add(-time.Hour), false},
+	}
+	for _, test := range tests {
+		assertion, err := NewJWTAssertion("test", "concierge", key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mock.ExpectQuery(query).WithArgs("test").WillReturnRows(sqlmock.NewRows(columns).AddRow("hash", test.enabled, test.expires))
+		if test.success {
+			mock.ExpectExec("UPDATE users SET last_login").WithArgs("test").WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		if ok, _ := ap.Authenticate(id, assertion); ok != test.success {
+			t.Errorf("%s: expected authentication %t, got %t", test.name, test.success, ok)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestConcurrentMigrate migrates a real database from several servers at once, as when replicas are started
+// together, if a database is specified in CONCIERGE_TEST_DB, such as "dbname=concierge_test sslmode=disable".
+// The database should be empty, as it is migrated to the latest version.
+func TestConcurrentMigrate(t *testing.T) {
+	connStr := os.Getenv("CONCIERGE_TEST_DB")
+	if connStr == "" {
+		t.Skip("CONCIERGE_TEST_DB not set")
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dba, err := OpenDatabaseAuthProvider(connStr)
+			if err != nil {
+				errs <- err
+				return
+			}
+			dba.Close()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,250 @@
+package server
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/wardle/concierge/apiv1"
+)
+
+// maxAssertionLifetime is the maximum permitted lifetime of a client assertion, as per SMART backend services
+const maxAssertionLifetime = 5 * time.Minute
+
+var (
+	// ErrInvalidAssertion means that a client assertion could not be verified
+	ErrInvalidAssertion = errors.New("invalid client assertion")
+)
+
+// JWTAssertionAuthProvider authenticates service accounts using a JWT assertion signed by the client
+// using its private key, and verified using a public key registered for that account ("private_key_jwt").
+// The assertion must have 'iss' and 'sub' claims equal to the account name, an 'aud' claim matching the
+// configured audience, a unique 'jti' and an 'exp' no more than five minutes in the future.
+//
+// Credentials that are not JWTs are passed to an optional fallback provider, so that
+// accounts can be migrated from shared secrets one at a time. If the fallback provider holds the state
+// of accounts, such as the authentication database, accounts must also be enabled and unexpired there.
+type JWTAssertionAuthProvider struct {
+	audience string
+	fallback AuthProvider
+
+	mu   sync.RWMutex
+	keys map[string]crypto.PublicKey // public keys by account name
+
+	seenMu sync.Mutex
+	seen   map[string]time.Time // jti of used assertions, and their expiry, to prevent replay
+}
+
+var _ AuthProvider = (*JWTAssertionAuthProvider)(nil)
+var _ AccountStore = (*JWTAssertionAuthProvider)(nil)
+
+// NewJWTAssertionAuthProvider creates a new provider for client assertions for the given audience, with an
+// optional fallback provider for credentials that are not JWTs
+func NewJWTAssertionAuthProvider(audience string, fallback AuthProvider) *JWTAssertionAuthProvider {
+	return &JWTAssertionAuthProvider{
+		audience: audience,
+		fallback: fallback,
+		keys:     make(map[string]crypto.PublicKey),
+		seen:     make(map[string]time.Time),
+	}
+}
+
+// RegisterPublicKey registers a PEM-encoded RSA or ECDSA public key for the named account
+func (ap *JWTAssertionAuthProvider) RegisterPublicKey(username string, pemBytes []byte) error {
+	key, err := parsePublicKey(pemBytes)
+	if err != nil {
+		return fmt.Errorf("auth: invalid public key for '%s': %w", username, err)
+	}
+	ap.mu.Lock()
+	ap.keys[username] = key
+	ap.mu.Unlock()
+	return nil
+}
+
+// LoadPublicKeys replaces the registered public keys with those in the specified directory.
+// Each key must be PEM-encoded in a file named after the account, e.g. 'patientcare.pem'.
+// On error, the existing keys are left unchanged.
+func (ap *JWTAssertionAuthProvider) LoadPublicKeys(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+	keys := make(map[string]crypto.PublicKey, len(files))
+	for _, file := range files {
+		username := strings.TrimSuffix(filepath.Base(file), ".pem")
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		key, err := parsePublicKey(b)
+		if err != nil {
+			return fmt.Errorf("auth: invalid public key for '%s' in %s: %w", username, file, err)
+		}
+		keys[username] = key
+	}
+	ap.mu.Lock()
+	ap.keys = keys
+	ap.mu.Unlock()
+	log.Printf("auth: loaded %d public key(s) for client assertions from %s", len(keys), dir)
+	return nil
+}
+
+func parsePublicKey(pemBytes []byte) (crypto.PublicKey, error) {
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(pemBytes); err == nil {
+		return key, nil
+	}
+	return jwt.ParseECPublicKeyFromPEM(pemBytes)
+}
+
+// Authenticate verifies the client assertion for the specified account
+func (ap *JWTAssertionAuthProvider) Authenticate(id *apiv1.Identifier, credential string) (bool, error) {
+	if strings.Count(credential, ".") != 2 {
+		if ap.fallback != nil {
+			return ap.fallback.Authenticate(id, credential)
+		}
+		return false, nil
+	}
+	ap.mu.RLock()
+	key, found := ap.keys[id.GetValue()]
+	ap.mu.RUnlock()
+	if !found {
+		if ap.fallback != nil {
+			return ap.fallback.Authenticate(id, credential)
+		}
+		log.Printf("auth: no public key registered for '%s|%s'", id.GetSystem(), id.GetValue())
+		return false, nil
+	}
+	claims := &jwt.StandardClaims{}
+	token, err := jwt.ParseWithClaims(credential, claims, func(t *jwt.Token) (interface{}, error) {
+		switch key.(type) {
+		case *rsa.PublicKey:
+			if _, ok := t.Method.(*jwt.SigningMethodRSA); ok {
+				return key, nil
+			}
+		case *ecdsa.PublicKey:
+			if _, ok := t.Method.(*jwt.SigningMethodECDSA); ok {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+	})
+	if err != nil || !token.Valid {
+		return false, fmt.Errorf("%w: %s", ErrInvalidAssertion, err)
+	}
+	if claims.Issuer != id.GetValue() || claims.Subject != id.GetValue() {
+		return false, fmt.Errorf("%w: issuer and subject must be '%s'", ErrInvalidAssertion, id.GetValue())
+	}
+	if !claims.VerifyAudience(ap.audience, true) {
+		return false, fmt.Errorf("%w: invalid audience '%s'", ErrInvalidAssertion, claims.Audience)
+	}
+	expires := time.Unix(claims.ExpiresAt, 0)
+	if claims.ExpiresAt == 0 || time.Until(expires) > maxAssertionLifetime {
+		return false, fmt.Errorf("%w: expiry must be within %v", ErrInvalidAssertion, maxAssertionLifetime)
+	}
+	if claims.Id == "" {
+		return false, fmt.Errorf("%w: missing jti", ErrInvalidAssertion)
+	}
+	if !ap.markUsed(id.GetValue()+"|"+claims.Id, expires) {
+		return false, fmt.Errorf("%w: assertion already used", ErrInvalidAssertion)
+	}
+	if store, ok := ap.fallback.(AccountStore); ok {
+		active, err := store.CheckAccount(id)
+		if err != nil || !active {
+			return false, err
+		}
+		store.RecordLogin(id)
+	}
+	return true, nil
+}
+
+// CheckAccount returns whether the account is active in the fallback provider, if that holds the state of
+// accounts, or, without a fallback provider, whether a public key is registered for the account
+func (ap *JWTAssertionAuthProvider) CheckAccount(id *apiv1.Identifier) (bool, error) {
+	if store, ok := ap.fallback.(AccountStore); ok {
+		return store.CheckAccount(id)
+	}
+	if ap.fallback != nil {
+		return true, nil
+	}
+	ap.mu.RLock()
+	_, found := ap.keys[id.GetValue()]
+	ap.mu.RUnlock()
+	return found, nil
+}
+
+// RecordLogin records a successful login in the fallback provider, if that holds the state of accounts
+func (ap *JWTAssertionAuthProvider) RecordLogin(id *apiv1.Identifier) {
+	if store, ok := ap.fallback.(AccountStore); ok {
+		store.RecordLogin(id)
+	}
+}
+
+// markUsed records use of an assertion, returning false if it has already been used
+func (ap *JWTAssertionAuthProvider) markUsed(jti string, expires time.Time) bool {
+	ap.seenMu.Lock()
+	defer ap.seenMu.Unlock()
+	now := time.Now()
+	for k, exp := range ap.seen {
+		if now.After(exp) {
+			delete(ap.seen, k)
+		}
+	}
+	if _, used := ap.seen[jti]; used {
+		return false
+	}
+	ap.seen[jti] = expires
+	return true
+}
+
+// NewJWTAssertion creates a client assertion for the named account, for use as the password in a login request
+func NewJWTAssertion(username string, audience string, key crypto.PrivateKey) (string, error) {
+	var method jwt.SigningMethod
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		method = jwt.SigningMethodRS256
+	case *ecdsa.PrivateKey:
+		switch k.Curve.Params().BitSize {
+		case 256:
+			method = jwt.SigningMethodES256
+		case 384:
+			method = jwt.SigningMethodES384
+		default:
+			method = jwt.SigningMethodES512
+		}
+	default:
+		return "", fmt.Errorf("unsupported private key type: %T", key)
+	}
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims := &jwt.StandardClaims{
+		Issuer:    username,
+		Subject:   username,
+		Audience:  audience,
+		Id:        hex.EncodeToString(jti),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(maxAssertionLifetime).Unix(),
+	}
+	return jwt.NewWithClaims(method, claims).SignedString(key)
+}
+
+// ParsePrivateKey parses a PEM-encoded RSA or ECDSA private key, for signing client assertions
+func ParsePrivateKey(pemBytes []byte) (crypto.PrivateKey, error) {
+	if key, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes); err == nil {
+		return key, nil
+	}
+	return jwt.ParseECPrivateKeyFromPEM(pemBytes)
+}
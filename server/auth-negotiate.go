@@ -0,0 +1,111 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"gopkg.in/jcmturner/goidentity.v3"
+	"gopkg.in/jcmturner/gokrb5.v7/keytab"
+	"gopkg.in/jcmturner/gokrb5.v7/service"
+	"gopkg.in/jcmturner/gokrb5.v7/spnego"
+)
+
+const (
+	// negotiatePath is the REST endpoint at which a Kerberos ticket can be exchanged for an authentication token
+	negotiatePath = "/v1/auth/negotiate"
+	// serviceAuthorizationHeader carries the token of the service account making a negotiate login, as the
+	// authorization header carries the Kerberos ticket
+	serviceAuthorizationHeader = "X-Service-Authorization"
+)
+
+// negotiateAuthenticator exchanges Kerberos tickets, sent using SPNEGO ('Authorization: Negotiate'), for tokens
+type negotiateAuthenticator struct {
+	keytab    *keytab.Keytab
+	realm     string
+	namespace string
+	settings  []func(*service.Settings)
+}
+
+// RegisterNegotiate permits users holding a Kerberos ticket for the service principal in the keytab to obtain
+// an authentication token, without a password, using SPNEGO at the REST endpoint /v1/auth/negotiate.
+// Only users from the specified realm are accepted, and are issued a token as a user in the specified namespace,
+// such as identifiers.CymruUserID. An empty service principal uses the principal from the ticket.
+// As for Login, a user may only log in through a service account, identified by its token in the
+// X-Service-Authorization header, or by a verified client certificate registered to the service account.
+func (auth *Auth) RegisterNegotiate(kt *keytab.Keytab, servicePrincipal string, realm string, namespace string) {
+	settings := []func(*service.Settings){service.DecodePAC(false)}
+	if servicePrincipal != "" {
+		settings = append(settings, service.KeytabPrincipal(servicePrincipal))
+	}
+	auth.negotiate = &negotiateAuthenticator{keytab: kt, realm: realm, namespace: namespace, settings: settings}
+	log.Printf("auth: registered kerberos negotiate authentication for realm '%s': namespace uri: '%s'", realm, namespace)
+}
+
+// negotiateHandler wraps a HTTP handler for the REST gateway, handling Kerberos ticket exchange at negotiatePath
+func (auth *Auth) negotiateHandler(h http.Handler) http.Handler {
+	exchange := spnego.SPNEGOKRB5Authenticate(http.HandlerFunc(auth.negotiateLogin), auth.negotiate.keytab, auth.negotiate.settings...)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != negotiatePath {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		if !auth.hasServiceAccount(r) {
+			log.Printf("auth: attempt to login using kerberos negotiate without service account")
+			http.Error(w, "need service account login before logging in using normal user account", http.StatusUnauthorized)
+			return
+		}
+		exchange.ServeHTTP(w, r)
+	})
+}
+
+// hasServiceAccount returns whether a request is made by a service account, identified by its token in the
+// service authorization header, or by a verified client certificate registered to the service account
+func (auth *Auth) hasServiceAccount(r *http.Request) bool {
+	var id *apiv1.Identifier
+	if token := r.Header.Get(serviceAuthorizationHeader); token != "" {
+		if ucd, err := auth.parseToken(token); err == nil {
+			id = ucd.GetAuthenticatedUser()
+		}
+	} else if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		id = auth.clientCertificateIdentity(r.TLS.VerifiedChains[0][0])
+	}
+	_, isService := auth.serviceAccounts[id.GetSystem()]
+	return id != nil && isService
+}
+
+// negotiateLogin issues a token to a user whose Kerberos ticket has been validated
+func (auth *Auth) negotiateLogin(w http.ResponseWriter, r *http.Request) {
+	creds, ok := r.Context().Value(spnego.CTXKeyCredentials).(goidentity.Identity)
+	if !ok {
+		http.Error(w, spnego.UnauthorizedMsg, http.StatusUnauthorized)
+		return
+	}
+	if !strings.EqualFold(creds.Domain(), auth.negotiate.realm) {
+		log.Printf("auth: negotiate login rejected for '%s@%s': unsupported realm", creds.UserName(), creds.Domain())
+		auth.recordFailedLogin()
+		http.Error(w, spnego.UnauthorizedMsg, http.StatusUnauthorized)
+		return
+	}
+	id := &apiv1.Identifier{System: auth.negotiate.namespace, Value: creds.UserName()}
+	token, err := auth.generateToken(id, defaultTokenDuration)
+	if err != nil {
+		log.Printf("auth: failed to generate token: %s", err)
+		http.Error(w, "could not generate token", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("auth: generated authentication token for %s|%s using kerberos negotiate: %v", id.GetSystem(), id.GetValue(), defaultTokenDuration)
+	b, err := protojson.Marshal(&apiv1.LoginResponse{Token: token})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
@@ -0,0 +1,288 @@
+package server
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/wardle/concierge/apiv1"
+)
+
+// minimumKeyRefresh is the minimum interval between fetches of an identity provider's keys,
+// so that tokens with unknown key identifiers cannot be used to flood the provider with requests.
+const minimumKeyRefresh = time.Minute
+
+var (
+	// ErrInvalidIDToken means that an OpenID Connect ID token could not be verified
+	ErrInvalidIDToken = errors.New("invalid id token")
+)
+
+// OIDCAuthProvider authenticates users using an ID token issued by an OpenID Connect identity provider,
+// so that user passwords need never be passed to concierge. The issuer's signing keys are found using
+// its discovery document, and the token must have been issued to the configured client.
+//
+// The value of the configured claim (e.g. 'preferred_username' or 'upn') must match the identifier being
+// authenticated; if the claim is in the form user@domain, and the domain is one of those configured, the part
+// before the '@' may be used instead.
+// Credentials that are not JWTs are passed to an optional fallback provider.
+type OIDCAuthProvider struct {
+	issuer   string
+	clientID string
+	claim    string
+	domains  []string // domains of claims in the form user@domain that may be matched by user alone
+	fallback AuthProvider
+	client   *http.Client
+
+	mu          sync.Mutex
+	jwksURI     string
+	keys        map[string]crypto.PublicKey // keys by key identifier ('kid')
+	keysFetched time.Time
+}
+
+var _ AuthProvider = (*OIDCAuthProvider)(nil)
+
+// NewOIDCAuthProvider creates a provider for ID tokens from the specified issuer, issued to the specified client.
+// Claims in the form user@domain match the user alone only for the domains specified.
+// The issuer is not contacted until the first authentication.
+func NewOIDCAuthProvider(issuer string, clientID string, claim string, domains []string, fallback AuthProvider) *OIDCAuthProvider {
+	return &OIDCAuthProvider{
+		issuer:   strings.TrimSuffix(issuer, "/"),
+		clientID: clientID,
+		claim:    claim,
+		domains:  domains,
+		fallback: fallback,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		keys:     make(map[string]crypto.PublicKey),
+	}
+}
+
+// Authenticate verifies that the credential is a valid ID token for the specified user
+func (ap *OIDCAuthProvider) Authenticate(id *apiv1.Identifier, credential string) (bool, error) {
+	if strings.Count(credential, ".") != 2 {
+		if ap.fallback != nil {
+			return ap.fallback.Authenticate(id, credential)
+		}
+		return false, nil
+	}
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(credential, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := ap.key(kid)
+		if err != nil {
+			return nil, err
+		}
+		switch key.(type) {
+		case *rsa.PublicKey:
+			if _, ok := t.Method.(*jwt.SigningMethodRSA); ok {
+				return key, nil
+			}
+		case *ecdsa.PublicKey:
+			if _, ok := t.Method.(*jwt.SigningMethodECDSA); ok {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+	})
+	if err != nil || !token.Valid {
+		return false, fmt.Errorf("%w: %s", ErrInvalidIDToken, err)
+	}
+	if iss, _ := claims["iss"].(string); strings.TrimSuffix(iss, "/") != ap.issuer {
+		return false, fmt.Errorf("%w: unexpected issuer '%s'", ErrInvalidIDToken, iss)
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return false, fmt.Errorf("%w: missing or invalid expiry", ErrInvalidIDToken)
+	}
+	if !hasAudience(claims["aud"], ap.clientID) {
+		return false, fmt.Errorf("%w: not issued for client '%s'", ErrInvalidIDToken, ap.clientID)
+	}
+	value, _ := claims[ap.claim].(string)
+	if !matchesClaim(id.GetValue(), value, ap.domains) {
+		log.Printf("auth: id token claim '%s' ('%s') does not match '%s|%s'", ap.claim, value, id.GetSystem(), id.GetValue())
+		return false, nil
+	}
+	return true, nil
+}
+
+// hasAudience returns whether the 'aud' claim, which may be a string or an array, contains the client
+func hasAudience(aud interface{}, clientID string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == clientID
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok && s == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesClaim returns whether a username matches a claim value, either exactly or as the user part of user@domain
+// for one of the domains specified
+func matchesClaim(username string, claim string, domains []string) bool {
+	if username == "" || claim == "" {
+		return false
+	}
+	if strings.EqualFold(username, claim) {
+		return true
+	}
+	i := strings.LastIndex(claim, "@")
+	if i <= 0 || !strings.EqualFold(username, claim[:i]) {
+		return false
+	}
+	for _, domain := range domains {
+		if strings.EqualFold(claim[i+1:], domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// key returns the issuer's public key with the specified key identifier, fetching the issuer's keys if
+// the key is not already known
+func (ap *OIDCAuthProvider) key(kid string) (crypto.PublicKey, error) {
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+	if key, found := ap.keys[kid]; found {
+		return key, nil
+	}
+	if time.Since(ap.keysFetched) < minimumKeyRefresh {
+		return nil, fmt.Errorf("unknown key '%s'", kid)
+	}
+	if err := ap.fetchKeys(); err != nil {
+		log.Printf("auth: failed to fetch keys from identity provider '%s': %s", ap.issuer, err)
+		return nil, err
+	}
+	if key, found := ap.keys[kid]; found {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key '%s'", kid)
+}
+
+// fetchKeys fetches the issuer's discovery document, if not already done, and its current keys.
+// The caller must hold the lock.
+func (ap *OIDCAuthProvider) fetchKeys() error {
+	ap.keysFetched = time.Now()
+	if ap.jwksURI == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := ap.getJSON(ap.issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+			return err
+		}
+		if strings.TrimSuffix(discovery.Issuer, "/") != ap.issuer {
+			return fmt.Errorf("discovery document issuer '%s' does not match '%s'", discovery.Issuer, ap.issuer)
+		}
+		if discovery.JWKSURI == "" {
+			return fmt.Errorf("no jwks_uri in discovery document")
+		}
+		ap.jwksURI = discovery.JWKSURI
+	}
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := ap.getJSON(ap.jwksURI, &jwks); err != nil {
+		return err
+	}
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			log.Printf("auth: ignoring key '%s' from identity provider '%s': %s", jwk.Kid, ap.issuer, err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	ap.keys = keys
+	log.Printf("auth: fetched %d key(s) from identity provider '%s'", len(keys), ap.issuer)
+	return nil
+}
+
+func (ap *OIDCAuthProvider) getJSON(url string, v interface{}) error {
+	resp, err := ap.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response from '%s': %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// jsonWebKey is a public key in JSON Web Key format (RFC 7517)
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (jwk *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBase64Int(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBase64Int(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve '%s'", jwk.Crv)
+		}
+		x, err := decodeBase64Int(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBase64Int(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("invalid point on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type '%s'", jwk.Kty)
+}
+
+func decodeBase64Int(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -8,7 +8,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
@@ -34,8 +36,18 @@ var (
 // Auth is an authentication server
 type Auth struct {
 	jwtPrivatekey   *rsa.PrivateKey
+	temporaryKey    bool
 	authProviders   map[string]AuthProvider
 	serviceAccounts map[string]struct{}
+	administrators  map[string]struct{}          // accounts, as system|value, permitted to use administrative services
+	clientCerts     map[string]*apiv1.Identifier // accounts by client certificate subject or subject alternative name
+	negotiate       *negotiateAuthenticator      // optional kerberos ticket exchange
+	apiKeyStores    []APIKeyStore
+
+	statsMu      sync.Mutex
+	tokensIssued map[string]uint64       // count of issued tokens by namespace uri
+	apiKeyUsage  map[string]*apiKeyUsage // usage of api keys by name
+	failedLogins uint64
 }
 
 // AuthProvider is a mechanism for plugging in modular authentication schemes
@@ -44,6 +56,15 @@ type AuthProvider interface {
 	Authenticate(id *apiv1.Identifier, credential string) (bool, error)
 }
 
+// AccountStore is implemented by authentication providers that hold the state of accounts, so that an account
+// disabled or expired since login cannot continue to obtain tokens, such as by refreshing a token.
+type AccountStore interface {
+	// CheckAccount returns whether the account exists, is enabled and has not expired
+	CheckAccount(id *apiv1.Identifier) (bool, error)
+	// RecordLogin records a successful login to the account using credentials verified elsewhere
+	RecordLogin(id *apiv1.Identifier)
+}
+
 // NewAuthenticationServer creates a new authentication server that can issue JWT tokens
 func NewAuthenticationServer(rsaPrivateKey string) (*Auth, error) {
 	key, err := ioutil.ReadFile(rsaPrivateKey)
@@ -55,8 +76,13 @@ func NewAuthenticationServer(rsaPrivateKey string) (*Auth, error) {
 		return nil, fmt.Errorf("error parsing jwt private key: %w", err)
 	}
 	return &Auth{
-		jwtPrivatekey: parsedKey,
-		authProviders: make(map[string]AuthProvider),
+		jwtPrivatekey:   parsedKey,
+		authProviders:   make(map[string]AuthProvider),
+		serviceAccounts: make(map[string]struct{}),
+		administrators:  make(map[string]struct{}),
+		clientCerts:     make(map[string]*apiv1.Identifier),
+		tokensIssued:    make(map[string]uint64),
+		apiKeyUsage:     make(map[string]*apiKeyUsage),
 	}, nil
 }
 
@@ -65,8 +91,13 @@ func NewAuthenticationServerWithTemporaryKey() (*Auth, error) {
 	auth := new(Auth)
 	var err error
 	auth.jwtPrivatekey, err = rsa.GenerateKey(rand.Reader, 2048)
+	auth.temporaryKey = true
 	auth.authProviders = make(map[string]AuthProvider)
 	auth.serviceAccounts = make(map[string]struct{})
+	auth.administrators = make(map[string]struct{})
+	auth.clientCerts = make(map[string]*apiv1.Identifier)
+	auth.tokensIssued = make(map[string]uint64)
+	auth.apiKeyUsage = make(map[string]*apiKeyUsage)
 	return auth, err
 }
 
@@ -97,6 +128,47 @@ func (auth *Auth) RegisterAuthProvider(uri string, name string, ap AuthProvider,
 	log.Printf("auth: registered authentication provider for namespace uri: '%s': %s", uri, name)
 }
 
+// RegisterAdministrator permits the specified account to use administrative services
+func (auth *Auth) RegisterAdministrator(id *apiv1.Identifier) {
+	auth.administrators[id.GetSystem()+"|"+id.GetValue()] = struct{}{}
+	log.Printf("auth: registered administrator: '%s|%s'", id.GetSystem(), id.GetValue())
+}
+
+// IsAdministrator returns whether the specified account is permitted to use administrative services
+func (auth *Auth) IsAdministrator(id *apiv1.Identifier) bool {
+	if id == nil {
+		return false
+	}
+	_, found := auth.administrators[id.GetSystem()+"|"+id.GetValue()]
+	return found
+}
+
+// tokenSigningStatus returns the current status of token signing, including counts of issued tokens
+func (auth *Auth) tokenSigningStatus() *apiv1.TokenSigningStatus {
+	result := &apiv1.TokenSigningStatus{
+		Enabled:      true,
+		Algorithm:    jwt.SigningMethodRS256.Alg(),
+		TemporaryKey: auth.temporaryKey,
+	}
+	if auth.jwtPrivatekey != nil {
+		result.KeySize = int32(auth.jwtPrivatekey.N.BitLen())
+	}
+	auth.statsMu.Lock()
+	defer auth.statsMu.Unlock()
+	for system, count := range auth.tokensIssued {
+		result.Issued = append(result.Issued, &apiv1.TokenCount{System: system, Count: count})
+	}
+	sort.Slice(result.Issued, func(i, j int) bool { return result.Issued[i].System < result.Issued[j].System })
+	result.FailedLogins = auth.failedLogins
+	return result
+}
+
+func (auth *Auth) recordFailedLogin() {
+	auth.statsMu.Lock()
+	auth.failedLogins++
+	auth.statsMu.Unlock()
+}
+
 // Login performs an authentication.
 // User account login can only be performed with an already logged in service account
 // A service user login is currently performed using a user key and secret key, but could itself be from a third-party
@@ -121,9 +193,11 @@ func (auth *Auth) Login(ctx context.Context, r *apiv1.LoginRequest) (*apiv1.Logi
 	success, err := ap.Authenticate(r.GetUser(), r.GetPassword())
 	if err != nil {
 		log.Printf("auth: failed to authenticate: %s", err)
+		auth.recordFailedLogin()
 		return nil, status.Errorf(codes.Unauthenticated, "failed to authenticate: %s", err)
 	}
 	if !success {
+		auth.recordFailedLogin()
 		log.Printf("auth: invalid credentials for '%s|%s'", r.GetUser().GetSystem(), r.GetUser().GetValue())
 		return nil, status.Errorf(codes.Unauthenticated, "invalid credentials")
 	}
@@ -144,9 +218,19 @@ func (auth *Auth) Login(ctx context.Context, r *apiv1.LoginRequest) (*apiv1.Logi
 // Refresh refreshes an authenitcation token
 func (auth *Auth) Refresh(ctx context.Context, r *apiv1.TokenRefreshRequest) (*apiv1.LoginResponse, error) {
 	ucd := GetContextData(ctx)
+	if store, ok := auth.authProviders[ucd.GetAuthenticatedUser().GetSystem()].(AccountStore); ok {
+		active, err := store.CheckAccount(ucd.GetAuthenticatedUser())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "could not check account: %s", err)
+		}
+		if !active {
+			log.Printf("auth: refused to refresh token for inactive account '%s|%s'", ucd.GetAuthenticatedUser().GetSystem(), ucd.GetAuthenticatedUser().GetValue())
+			return nil, status.Errorf(codes.Unauthenticated, "account disabled or expired")
+		}
+	}
 	// do we really need to refresh token? send old one back if there is plenty of time
 	remaining := ucd.GetTokenExpiresAt().Sub(time.Now())
-	if remaining > 5*time.Minute {
+	if remaining > 5*time.Minute && ucd.token != "" {
 		log.Printf("auth: re-issuing still active token for '%s|%s' expiry:%v ", ucd.GetAuthenticatedUser().GetSystem(), ucd.GetAuthenticatedUser().GetValue(), ucd.GetTokenExpiresAt())
 		return &apiv1.LoginResponse{Token: ucd.token}, nil
 	}
@@ -163,6 +247,17 @@ func (auth *Auth) Refresh(ctx context.Context, r *apiv1.TokenRefreshRequest) (*a
 }
 
 func (auth *Auth) generateToken(id *apiv1.Identifier, duration time.Duration) (string, error) {
+	ss, err := auth.signToken(id, duration)
+	if err == nil {
+		auth.statsMu.Lock()
+		auth.tokensIssued[id.GetSystem()]++
+		auth.statsMu.Unlock()
+	}
+	return ss, err
+}
+
+// signToken signs a token for the specified user, without recording it in the count of issued tokens
+func (auth *Auth) signToken(id *apiv1.Identifier, duration time.Duration) (string, error) {
 	claims := &jwt.StandardClaims{
 		ExpiresAt: time.Now().Add(duration).Unix(),
 		IssuedAt:  time.Now().Unix(),
@@ -212,6 +307,7 @@ type UserContextData struct {
 	authenticatedUser *apiv1.Identifier
 	token             string
 	tokenExpiresAt    time.Time
+	scopes            map[string]struct{} // namespaces to which access is restricted, or nil if unrestricted
 }
 
 // GetAuthenticatedUser returns the authenticated user, guarding against nils
@@ -240,6 +336,10 @@ var noAuthEndpoints = map[string]struct{}{
 func (sv *Server) unaryAuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	ctx, err := sv.auth.contextWithUserData(ctx)
 	if err == nil {
+		if err := GetContextData(ctx).permits(info.FullMethod, req); err != nil {
+			log.Printf("server: unauthorised call to '%s': %s", info.FullMethod, err)
+			return nil, err
+		}
 		return handler(ctx, req)
 	}
 	if _, found := noAuthEndpoints[info.FullMethod]; found { // is this endpoint in our list of unprotected endpoints?
@@ -253,7 +353,8 @@ func (sv *Server) unaryAuthInterceptor(ctx context.Context, req interface{}, inf
 // SendMsg method call.
 type wrappedStream struct {
 	grpc.ServerStream
-	ucd *UserContextData
+	ucd    *UserContextData
+	method string
 }
 
 func (ws wrappedStream) Context() context.Context {
@@ -261,7 +362,10 @@ func (ws wrappedStream) Context() context.Context {
 }
 
 func (w *wrappedStream) RecvMsg(m interface{}) error {
-	return w.ServerStream.RecvMsg(m)
+	if err := w.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return w.ucd.permits(w.method, m)
 }
 
 func (w *wrappedStream) SendMsg(m interface{}) error {
@@ -275,7 +379,7 @@ func (sv *Server) streamAuthInterceptor(srv interface{}, ss grpc.ServerStream, i
 	}
 	ucd := GetContextData(ctx)
 	ucd.GetAuthenticatedUser()
-	err = handler(srv, &wrappedStream{ss, ucd})
+	err = handler(srv, &wrappedStream{ss, ucd, info.FullMethod})
 	if err != nil {
 		log.Printf("auth: streaming failed with error: %v", err)
 	}
@@ -283,14 +387,23 @@ func (sv *Server) streamAuthInterceptor(srv interface{}, ss grpc.ServerStream, i
 }
 
 // contextWithUserData returns a new context containing UserContextData specifically
-//  returning the old context in the event of an error
+//  returning the old context in the event of an error.
+// An authorization token takes precedence; otherwise an API key or a verified client certificate
+// registered to an account is used, if present.
 func (auth *Auth) contextWithUserData(ctx context.Context) (context.Context, error) {
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return ctx, fmt.Errorf("invalid token")
-	}
+	md, _ := metadata.FromIncomingContext(ctx)
 	tokenString, ok := md["authorization"]
 	if !ok {
+		user, err := auth.userFromAPIKey(ctx)
+		if err != nil {
+			return ctx, err
+		}
+		if user != nil {
+			return context.WithValue(ctx, userContextKey, user), nil
+		}
+		if user := auth.userFromPeer(ctx); user != nil {
+			return context.WithValue(ctx, userContextKey, user), nil
+		}
 		return ctx, fmt.Errorf("invalid token")
 	}
 	user, err := auth.parseToken(tokenString[0])
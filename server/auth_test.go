@@ -2,11 +2,43 @@ package server
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/wardle/concierge/apiv1"
 	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"gopkg.in/jcmturner/gokrb5.v7/client"
+	krbconfig "gopkg.in/jcmturner/gokrb5.v7/config"
+	"gopkg.in/jcmturner/gokrb5.v7/iana/etypeID"
+	"gopkg.in/jcmturner/gokrb5.v7/iana/nametype"
+	"gopkg.in/jcmturner/gokrb5.v7/keytab"
+	"gopkg.in/jcmturner/gokrb5.v7/messages"
+	"gopkg.in/jcmturner/gokrb5.v7/spnego"
+	"gopkg.in/jcmturner/gokrb5.v7/test/testdata"
+	"gopkg.in/jcmturner/gokrb5.v7/types"
 )
 
 func TestServiceLogin(t *testing.T) {
@@ -40,3 +72,333 @@ func TestServiceLogin(t *testing.T) {
 		t.Fatalf("did not get correct system/value identifier from token. got: %s|%s", user.authenticatedUser.GetSystem(), user.authenticatedUser.GetValue())
 	}
 }
+
+func TestJWTAssertionLogin(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	password, hash, err := GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ap := NewJWTAssertionAuthProvider("concierge", NewSingleAuthProvider(hash))
+	if err := ap.RegisterPublicKey("client", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})); err != nil {
+		t.Fatal(err)
+	}
+	auth.RegisterAuthProvider(identifiers.ConciergeServiceUser, "test-jwt", ap, true)
+	id := &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "client"}
+	assertion, err := NewJWTAssertion("client", "concierge", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Login(context.Background(), &apiv1.LoginRequest{User: id, Password: assertion}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Login(context.Background(), &apiv1.LoginRequest{User: id, Password: assertion}); err == nil {
+		t.Fatal("replayed assertion accepted")
+	}
+	other := &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "other"}
+	if _, err := auth.Login(context.Background(), &apiv1.LoginRequest{User: other, Password: assertion}); err == nil {
+		t.Fatal("assertion accepted for another account")
+	}
+	wrongAudience, err := NewJWTAssertion("client", "elsewhere", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Login(context.Background(), &apiv1.LoginRequest{User: id, Password: wrongAudience}); err == nil {
+		t.Fatal("assertion accepted for wrong audience")
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := NewJWTAssertion("client", "concierge", otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Login(context.Background(), &apiv1.LoginRequest{User: id, Password: forged}); err == nil {
+		t.Fatal("assertion signed with unregistered key accepted")
+	}
+	// passwords continue to be checked by the fallback provider
+	if _, err := auth.Login(context.Background(), &apiv1.LoginRequest{User: other, Password: password}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClientCertificateLogin(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "pas"}
+	auth.RegisterClientCertificate("pas.example.nhs.uk", id)
+	peerContext := func(cert *x509.Certificate) context.Context {
+		state := tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+	}
+	registered := &x509.Certificate{Subject: pkix.Name{CommonName: "PAS"}, DNSNames: []string{"pas.example.nhs.uk"}, NotAfter: time.Now().Add(time.Hour)}
+	ctx, err := auth.contextWithUserData(peerContext(registered))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user := GetContextData(ctx).GetAuthenticatedUser(); user.GetSystem() != id.GetSystem() || user.GetValue() != id.GetValue() {
+		t.Fatalf("did not get correct user from client certificate. got: %s|%s", user.GetSystem(), user.GetValue())
+	}
+	unregistered := &x509.Certificate{Subject: pkix.Name{CommonName: "other.example.nhs.uk"}}
+	if _, err := auth.contextWithUserData(peerContext(unregistered)); err == nil {
+		t.Fatal("unregistered client certificate authenticated")
+	}
+	// an unverified certificate must not authenticate
+	state := tls.ConnectionState{PeerCertificates: []*x509.Certificate{registered}}
+	if _, err := auth.contextWithUserData(peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})); err == nil {
+		t.Fatal("unverified client certificate authenticated")
+	}
+	// the REST gateway passes the identity to the gRPC server as a short-lived token
+	var authorization string
+	h := auth.clientCertificateHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/v1/identifier", nil)
+	r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{registered}}}
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	user, err := auth.parseToken(authorization)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.GetAuthenticatedUser().GetValue() != id.GetValue() {
+		t.Fatalf("did not get correct user from gateway token. got: %s", user.GetAuthenticatedUser().GetValue())
+	}
+}
+
+func TestOIDCLogin(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a stub identity provider, with discovery document and keys
+	var issuer string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": issuer, "jwks_uri": issuer + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "key1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	idp := httptest.NewServer(mux)
+	defer idp.Close()
+	issuer = idp.URL
+	idToken := func(claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "key1"
+		ss, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ss
+	}
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, hash, err := GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.RegisterAuthProvider(identifiers.ConciergeServiceUser, "test-single", NewSingleAuthProvider(hash), true)
+	auth.RegisterAuthProvider(identifiers.CymruUserID, "test-oidc", NewOIDCAuthProvider(issuer, "concierge", "preferred_username", []string{"cymru.nhs.uk"}, nil), false)
+	// user login must be made using a logged-in service account
+	ctx := context.WithValue(context.Background(), userContextKey, &UserContextData{
+		authenticatedUser: &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "client"},
+	})
+	id := &apiv1.Identifier{System: identifiers.CymruUserID, Value: "ma090906"}
+	exp := time.Now().Add(time.Hour).Unix()
+	valid := idToken(jwt.MapClaims{"iss": issuer, "aud": []string{"concierge"}, "exp": exp, "preferred_username": "ma090906@cymru.nhs.uk"})
+	r, err := auth.Login(ctx, &apiv1.LoginRequest{User: id, Password: valid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := auth.parseToken(r.GetToken())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.GetAuthenticatedUser().GetSystem() != id.GetSystem() || user.GetAuthenticatedUser().GetValue() != id.GetValue() {
+		t.Fatalf("did not get correct user from token. got: %s|%s", user.GetAuthenticatedUser().GetSystem(), user.GetAuthenticatedUser().GetValue())
+	}
+	other := &apiv1.Identifier{System: identifiers.CymruUserID, Value: "other"}
+	if _, err := auth.Login(ctx, &apiv1.LoginRequest{User: other, Password: valid}); err == nil {
+		t.Fatal("id token accepted for another user")
+	}
+	invalid := []jwt.MapClaims{
+		{"iss": issuer, "aud": "concierge", "exp": exp, "preferred_username": "ma090906@elsewhere.example.com"},
+		{"iss": issuer, "aud": "another-client", "exp": exp, "preferred_username": "ma090906"},
+		{"iss": "https://elsewhere.example.com", "aud": "concierge", "exp": exp, "preferred_username": "ma090906"},
+		{"iss": issuer, "aud": "concierge", "exp": time.Now().Add(-time.Minute).Unix(), "preferred_username": "ma090906"},
+		{"iss": issuer, "aud": "concierge", "preferred_username": "ma090906"},
+	}
+	for i, claims := range invalid {
+		if _, err := auth.Login(ctx, &apiv1.LoginRequest{User: id, Password: idToken(claims)}); err == nil {
+			t.Fatalf("invalid id token %d accepted", i)
+		}
+	}
+	if _, err := auth.Login(ctx, &apiv1.LoginRequest{User: id, Password: "password"}); err == nil {
+		t.Fatal("password accepted without fallback provider")
+	}
+}
+
+func TestNegotiateLogin(t *testing.T) {
+	b, _ := hex.DecodeString(testdata.HTTP_KEYTAB)
+	kt := keytab.New()
+	if err := kt.Unmarshal(b); err != nil {
+		t.Fatal(err)
+	}
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.RegisterNegotiate(kt, "", "TEST.GOKRB5", identifiers.CymruUserID)
+	_, hash, err := GenerateCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.RegisterAuthProvider(identifiers.ConciergeServiceUser, "test-single", NewSingleAuthProvider(hash), true)
+	serviceToken, err := auth.generateToken(&apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "client"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	userToken, err := auth.generateToken(&apiv1.Identifier{System: identifiers.CymruUserID, Value: "testuser2"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv := New(Options{})
+	sv.RegisterAuthenticator(auth)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) })
+	h := sv.restHandler(next, false)
+
+	// other paths are passed through unchanged
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/identifier", nil))
+	if w.Code != http.StatusTeapot {
+		t.Fatalf("request not passed to gateway: %d", w.Code)
+	}
+	// clients without a ticket are challenged, with CORS headers for browser clients
+	r := httptest.NewRequest(http.MethodGet, negotiatePath, nil)
+	r.Header.Set(serviceAuthorizationHeader, "Bearer "+serviceToken)
+	r.Header.Set("Origin", "https://example.com")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") != "Negotiate" {
+		t.Fatalf("expected negotiate challenge, got: %d %s", w.Code, w.Header().Get("WWW-Authenticate"))
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") == "" {
+		t.Fatal("no CORS headers in negotiate response")
+	}
+	// clients with a ticket for the service, issued here using the service keytab rather than a KDC, are issued a
+	// token, if logging in using a service account
+	negotiate := func(username string, realm string, service string) *httptest.ResponseRecorder {
+		cname := types.NewPrincipalName(nametype.KRB_NT_PRINCIPAL, username)
+		sname := types.NewPrincipalName(nametype.KRB_NT_PRINCIPAL, "HTTP/host.test.gokrb5")
+		now := time.Now().UTC()
+		tkt, sessionKey, err := messages.NewTicket(cname, realm, sname, "TEST.GOKRB5", types.NewKrbFlags(), kt, etypeID.AES256_CTS_HMAC_SHA1_96, 1, now, now, now.Add(time.Hour), now.Add(time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cl := client.NewClientWithPassword(username, realm, "unused", krbconfig.NewConfig())
+		init, err := spnego.NewNegTokenInitKRB5(cl, tkt, sessionKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		st, err := (&spnego.SPNEGOToken{Init: true, NegTokenInit: init}).Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := httptest.NewRequest(http.MethodPost, negotiatePath, nil)
+		r.Header.Set("Authorization", "Negotiate "+base64.StdEncoding.EncodeToString(st))
+		if service != "" {
+			r.Header.Set(serviceAuthorizationHeader, "Bearer "+service)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+	for _, token := range []string{"", userToken, "invalid"} {
+		if w = negotiate("testuser1", "TEST.GOKRB5", token); w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected kerberos login without service account to fail, got: %d", w.Code)
+		}
+	}
+	w = negotiate("testuser1", "TEST.GOKRB5", serviceToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("kerberos login failed: %d %s", w.Code, w.Body.String())
+	}
+	response := new(apiv1.LoginResponse)
+	if err := protojson.Unmarshal(w.Body.Bytes(), response); err != nil {
+		t.Fatal(err)
+	}
+	user, err := auth.parseToken(response.GetToken())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.GetAuthenticatedUser().GetSystem() != identifiers.CymruUserID || user.GetAuthenticatedUser().GetValue() != "testuser1" {
+		t.Fatalf("did not get correct user from token. got: %s|%s", user.GetAuthenticatedUser().GetSystem(), user.GetAuthenticatedUser().GetValue())
+	}
+	// users from other realms are rejected
+	if w = negotiate("testuser1", "OTHER.GOKRB5", serviceToken); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected kerberos login from another realm to fail, got: %d", w.Code)
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	auth, err := NewAuthenticationServerWithTemporaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, hash, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.RegisterAPIKeyStore("test", NewStaticAPIKeyStore(map[string]*APIKey{
+		hash: {Name: "browser", Scopes: []string{identifiers.SNOMEDCT, identifiers.SDSJobRoleNameURI}},
+	}))
+	sv := &Server{auth: auth}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return req, nil }
+	call := func(apiKey string, method string, req interface{}) error {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(apiKeyHeader, apiKey))
+		_, err := sv.unaryAuthInterceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+	if err := call(key, "/apiv1.Identifiers/GetIdentifier", &apiv1.Identifier{System: identifiers.SNOMEDCT, Value: "24700007"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := call(key, "/apiv1.Identifiers/MapIdentifier", &apiv1.IdentifierMapRequest{System: identifiers.SDSJobRoleNameURI, Value: "R0260", TargetUri: identifiers.SNOMEDCT}); err != nil {
+		t.Fatal(err)
+	}
+	if err := call(key, "/apiv1.Identifiers/GetIdentifier", &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected permission denied for namespace outside scope, got: %v", err)
+	}
+	if err := call(key, "/apiv1.Identifiers/MapIdentifier", &apiv1.IdentifierMapRequest{System: identifiers.SNOMEDCT, Value: "24700007", TargetUri: identifiers.ReadV2}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected permission denied for target namespace outside scope, got: %v", err)
+	}
+	if err := call(key, "/apiv1.Authenticator/Refresh", &apiv1.TokenRefreshRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected permission denied for method, got: %v", err)
+	}
+	if err := call("invalid", "/apiv1.Identifiers/GetIdentifier", &apiv1.Identifier{System: identifiers.SNOMEDCT, Value: "24700007"}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected invalid api key to be unauthenticated, got: %v", err)
+	}
+	if usage := auth.apiKeyUsageStatus(); len(usage) != 1 || usage[0].GetName() != "browser" || usage[0].GetCount() != 5 {
+		t.Fatalf("unexpected api key usage: %v", usage)
+	}
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/wardle/concierge/identifiers"
+)
+
+// BreakGlassEntry is an entry in the break-glass log, recording access to a restricted record
+type BreakGlassEntry struct {
+	Time          time.Time `json:"time"`
+	User          string    `json:"user"` // authenticated user, as system|value
+	System        string    `json:"system"`
+	Value         string    `json:"value"`
+	Policy        string    `json:"policy"`
+	Reason        string    `json:"reason"`
+	Justification string    `json:"justification"`
+}
+
+// BreakGlassLog is an append-only log of access to restricted records, for information governance review.
+// Entries are written as JSON, one per line.
+type BreakGlassLog struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// OpenBreakGlassLog opens, or creates, the break-glass log at the specified path
+func OpenBreakGlassLog(path string) (*BreakGlassLog, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("server: recording break-glass access in %s", path)
+	return &BreakGlassLog{f: f}, nil
+}
+
+// Record records access to a restricted record by the authenticated user, returning only once written to disk
+func (bgl *BreakGlassLog) Record(ctx context.Context, bg *identifiers.BreakGlass) error {
+	entry := &BreakGlassEntry{
+		Time:          time.Now().UTC(),
+		User:          subjectFor(ctx),
+		System:        bg.Identifier.GetSystem(),
+		Value:         bg.Identifier.GetValue(),
+		Policy:        bg.Policy,
+		Reason:        bg.Reason,
+		Justification: bg.Justification,
+	}
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	bgl.mu.Lock()
+	defer bgl.mu.Unlock()
+	if _, err := bgl.f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return bgl.f.Sync()
+}
+
+// Close closes the log
+func (bgl *BreakGlassLog) Close() error {
+	bgl.mu.Lock()
+	defer bgl.mu.Unlock()
+	return bgl.f.Close()
+}
+
+// ReadBreakGlassLog reads entries from a break-glass log, calling f for each
+func ReadBreakGlassLog(r io.Reader, f func(*BreakGlassEntry) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		entry := new(BreakGlassEntry)
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return err
+		}
+		if err := f(entry); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBreakGlass(t *testing.T) {
+	dir, err := ioutil.TempDir("", "concierge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "breakglass.log")
+	bgl, err := OpenBreakGlassLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const otherBoard = "https://concierge.test/Id/other-board-crn"
+	const ourBoard = "https://concierge.test/Id/our-board-crn"
+	resolve := func(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
+		return &apiv1.Patient{Lastname: "DUMMY", Identifiers: []*apiv1.Identifier{id, {System: identifiers.NHSNumber, Value: id.GetValue()}}}, nil
+	}
+	identifiers.RegisterResolver(otherBoard, resolve)
+	identifiers.RegisterResolver(ourBoard, resolve)
+	identifiers.RegisterAccessPolicy("test-namespaces", identifiers.NamespacePolicy(otherBoard))
+	identifiers.RegisterAccessPolicy("test-records", identifiers.RecordPolicy(&apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"}))
+	identifiers.RegisterBreakGlassRecorder(bgl.Record)
+	t.Cleanup(func() {
+		identifiers.UnregisterResolver(otherBoard)
+		identifiers.UnregisterResolver(ourBoard)
+		identifiers.UnregisterAccessPolicy("test-namespaces")
+		identifiers.UnregisterAccessPolicy("test-records")
+		identifiers.RegisterBreakGlassRecorder(nil)
+	})
+
+	user := context.WithValue(context.Background(), userContextKey, &UserContextData{
+		authenticatedUser: &apiv1.Identifier{System: identifiers.CymruUserID, Value: "ma090906"},
+	})
+	justified := metadata.NewIncomingContext(user, metadata.Pairs(identifiers.JustificationHeader, "patient in emergency department"))
+
+	// unrestricted records need no justification
+	if _, err := identifiers.Resolve(user, &apiv1.Identifier{System: ourBoard, Value: "2222222222"}); err != nil {
+		t.Fatal(err)
+	}
+	// restricted namespaces and records are denied by default
+	for _, id := range []*apiv1.Identifier{{System: otherBoard, Value: "2222222222"}, {System: ourBoard, Value: "1111111111"}} {
+		if _, err := identifiers.Resolve(user, id); status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("access to %v without justification: expected permission denied, got: %v", id, err)
+		}
+		if _, err := identifiers.Resolve(justified, id); err != nil {
+			t.Fatalf("access to %v with justification denied: %s", id, err)
+		}
+	}
+	bgl.Close()
+
+	// and accesses are recorded
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var entries []*BreakGlassEntry
+	if err := ReadBreakGlassLog(f, func(e *BreakGlassEntry) error { entries = append(entries, e); return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected two break-glass entries, got %d", len(entries))
+	}
+	e := entries[1]
+	if e.User != identifiers.CymruUserID+"|ma090906" || e.System != ourBoard || e.Policy != "test-records" || e.Justification != "patient in emergency department" || e.Time.IsZero() {
+		t.Fatalf("incorrect break-glass entry: %+v", e)
+	}
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"sync"
+)
+
+// certificateReloader provides a TLS certificate loaded from the specified files,
+// permitting the certificate to be re-read at runtime so that it can be rotated without a restart.
+// If a client CA file is specified, clients may present a certificate issued by one of those CAs,
+// which is then verified during the TLS handshake.
+type certificateReloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+	mu           sync.RWMutex
+	cert         *tls.Certificate
+	clientCAs    *x509.CertPool
+}
+
+// newCertificateReloader creates a certificate reloader, loading the certificate immediately
+func newCertificateReloader(certFile string, keyFile string, clientCAFile string) (*certificateReloader, error) {
+	cr := &certificateReloader{certFile: certFile, keyFile: keyFile, clientCAFile: clientCAFile}
+	if err := cr.Reload(); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+// Reload re-reads the certificate and key from disk.
+// If the files cannot be read or are invalid, the previously loaded certificate continues to be used.
+func (cr *certificateReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(cr.certFile, cr.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate ('%s') and key ('%s'): %w", cr.certFile, cr.keyFile, err)
+	}
+	var clientCAs *x509.CertPool
+	if cr.clientCAFile != "" {
+		pem, err := ioutil.ReadFile(cr.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to load client CA certificates ('%s'): %w", cr.clientCAFile, err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no valid client CA certificates in '%s'", cr.clientCAFile)
+		}
+	}
+	cr.mu.Lock()
+	cr.cert = &cert
+	cr.clientCAs = clientCAs
+	cr.mu.Unlock()
+	log.Printf("server: loaded tls certificate from '%s'", cr.certFile)
+	if cr.clientCAFile != "" {
+		log.Printf("server: loaded client CA certificates from '%s'", cr.clientCAFile)
+	}
+	return nil
+}
+
+// GetCertificate returns the current certificate, for use as a tls.Config GetCertificate callback
+func (cr *certificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	return cr.cert, nil
+}
+
+// serverTLSConfig returns a TLS configuration for a server that always uses the current certificate.
+// If client CAs are configured, clients may optionally present a certificate, which must be verified against
+// the current CAs; clients without a certificate can still authenticate by other means.
+func (cr *certificateReloader) serverTLSConfig() *tls.Config {
+	if cr.clientCAFile == "" {
+		return &tls.Config{GetCertificate: cr.GetCertificate}
+	}
+	return &tls.Config{
+		GetCertificate: cr.GetCertificate,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cr.mu.RLock()
+			defer cr.mu.RUnlock()
+			return &tls.Config{
+				GetCertificate: cr.GetCertificate,
+				ClientAuth:     tls.VerifyClientCertIfGiven,
+				ClientCAs:      cr.clientCAs,
+				NextProtos:     []string{"h2", "http/1.1"},
+			}, nil
+		},
+	}
+}
+
+// clientTLSConfig returns a TLS configuration for a client connecting back to ourselves (e.g. the REST gateway).
+// Rather than trusting the certificate file as a root, which would break on rotation, the peer
+// certificate is pinned to whichever certificate we are currently serving.
+func (cr *certificateReloader) clientTLSConfig() *tls.Config {
+	return &tls.Config{
+		InsecureSkipVerify: true, // verification is performed in VerifyPeerCertificate
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			current, _ := cr.GetCertificate(nil)
+			if current == nil || len(current.Certificate) == 0 || len(rawCerts) == 0 {
+				return errors.New("no certificate available for verification")
+			}
+			if !bytes.Equal(rawCerts[0], current.Certificate[0]) {
+				return errors.New("peer certificate does not match server certificate")
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeSelfSignedCertificate(t *testing.T, certFile string, keyFile string, serial int64) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := ioutil.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCertificateReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "concierge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := filepath.Join(dir, "domain.crt"), filepath.Join(dir, "domain.key")
+	writeSelfSignedCertificate(t, certFile, keyFile, 1)
+	cr, err := newCertificateReloader(certFile, keyFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert1, _ := cr.GetCertificate(nil)
+	verify := cr.clientTLSConfig().VerifyPeerCertificate
+	if err := verify(cert1.Certificate, nil); err != nil {
+		t.Fatalf("failed to verify current certificate: %s", err)
+	}
+	writeSelfSignedCertificate(t, certFile, keyFile, 2)
+	if err := cr.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	cert2, _ := cr.GetCertificate(nil)
+	if bytes.Equal(cert1.Certificate[0], cert2.Certificate[0]) {
+		t.Fatal("certificate not changed after reload")
+	}
+	if err := verify(cert1.Certificate, nil); err == nil {
+		t.Fatal("previous certificate verified after rotation")
+	}
+	if err := verify(cert2.Certificate, nil); err != nil {
+		t.Fatalf("failed to verify rotated certificate: %s", err)
+	}
+	// an invalid certificate should not replace the current certificate
+	if err := ioutil.WriteFile(certFile, []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := cr.Reload(); err == nil {
+		t.Fatal("expected error when reloading invalid certificate")
+	}
+	if cert3, _ := cr.GetCertificate(nil); cert3 != cert2 {
+		t.Fatal("certificate changed after failed reload")
+	}
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+)
+
+const (
+	internalEndpoint = "concierge.internal" // nominal address used by the REST gateway to dial the in-process gRPC server
+)
+
+// grpcHandlerFunc returns an http.Handler that delegates to grpcServer for incoming gRPC connections,
+// and to otherHandler for all other requests, permitting gRPC and REST to be served on a single port.
+// gRPC requires HTTP/2; when not using TLS, HTTP/2 without TLS ("h2c") is supported so that plaintext
+// gRPC clients can connect.
+func grpcHandlerFunc(grpcServer *grpc.Server, otherHandler http.Handler, plaintext bool) http.Handler {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		otherHandler.ServeHTTP(w, r)
+	})
+	if plaintext {
+		return h2c.NewHandler(h, &http2.Server{})
+	}
+	return h
+}
+
+// listenUnix listens on a unix domain socket at the specified path, removing any stale socket
+// left from a previous run.
+func listenUnix(path string) (net.Listener, error) {
+	if fi, err := os.Stat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("failed to listen on unix socket: '%s' exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale unix socket '%s': %w", path, err)
+		}
+	}
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize unix socket listen: %w", err)
+	}
+	return lis, nil
+}
+
+// errPipeListenerClosed is returned when dialling or accepting from a closed pipe listener
+var errPipeListenerClosed = errors.New("pipe listener closed")
+
+// pipeListener is a listener for in-process connections, each an in-memory pipe, permitting the REST gateway to
+// connect to the gRPC server without using the network.
+type pipeListener struct {
+	conns     chan net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newPipeListener() *pipeListener {
+	return &pipeListener{conns: make(chan net.Conn), done: make(chan struct{})}
+}
+
+// Dial creates a connection to this listener, blocking until it is accepted
+func (pl *pipeListener) Dial() (net.Conn, error) {
+	client, server := net.Pipe()
+	select {
+	case pl.conns <- server:
+		return client, nil
+	case <-pl.done:
+		client.Close()
+		server.Close()
+		return nil, errPipeListenerClosed
+	}
+}
+
+// Accept waits for and returns the next connection to this listener
+func (pl *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-pl.conns:
+		return conn, nil
+	case <-pl.done:
+		return nil, errPipeListenerClosed
+	}
+}
+
+// Close closes the listener; existing connections are not closed
+func (pl *pipeListener) Close() error {
+	pl.closeOnce.Do(func() { close(pl.done) })
+	return nil
+}
+
+// Addr returns the nominal address of the listener
+func (pl *pipeListener) Addr() net.Addr {
+	return pipeAddr{}
+}
+
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string { return "pipe" }
+func (pipeAddr) String() string  { return internalEndpoint }
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc"
+	health "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestSinglePort(t *testing.T) {
+	sv := New(Options{})
+	grpcServer := grpc.NewServer()
+	health.RegisterHealthServer(grpcServer, sv)
+	rest := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("rest"))
+	})
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpServer := &http.Server{Handler: grpcHandlerFunc(grpcServer, rest, true)}
+	go httpServer.Serve(lis)
+	defer httpServer.Close()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	response, err := health.NewHealthClient(conn).Check(context.Background(), &health.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.GetStatus() != health.HealthCheckResponse_SERVING {
+		t.Fatalf("unexpected health status: %s", response.GetStatus())
+	}
+	resp, err := http.Get("http://" + lis.Addr().String() + "/v1/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "rest" {
+		t.Fatalf("expected REST handler response, got: %s", body)
+	}
+}
+
+func TestPipeListener(t *testing.T) {
+	sv := New(Options{})
+	grpcServer := grpc.NewServer()
+	health.RegisterHealthServer(grpcServer, sv)
+	lis := newPipeListener()
+	go grpcServer.Serve(lis)
+	defer grpcServer.Stop()
+
+	conn, err := grpc.Dial(internalEndpoint, grpc.WithInsecure(),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	response, err := health.NewHealthClient(conn).Check(context.Background(), &health.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.GetStatus() != health.HealthCheckResponse_SERVING {
+		t.Fatalf("unexpected health status: %s", response.GetStatus())
+	}
+	lis.Close()
+	if _, err := lis.Dial(); err != errPipeListenerClosed {
+		t.Fatalf("expected error dialling closed listener, got: %v", err)
+	}
+}
@@ -0,0 +1,388 @@
+package server
+
+import (
+	"context"
+	"log"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// retryAfterHeader is the metadata key used to tell a rate-limited client when it may retry, in seconds
+const retryAfterHeader = "retry-after"
+
+// anonymousSubject is used, with the client address, to rate limit requests without an authenticated user, such
+// as login or when authentication is off
+const anonymousSubject = "anonymous"
+
+// RateLimit defines a token-bucket rate limit, with an optional daily quota
+type RateLimit struct {
+	Rate       float64 // sustained requests per second; zero for no rate limit
+	Burst      int     // maximum requests permitted at once; defaults to the rate, or one
+	DailyQuota uint64  // maximum requests per (UTC) day; zero for no quota
+}
+
+func (l RateLimit) burst() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return math.Max(1, math.Ceil(l.Rate))
+}
+
+// tokenBucket implements a token-bucket rate limit
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens accrued since the bucket was last used, up to the burst
+func (b *tokenBucket) refill(limit RateLimit, now time.Time) {
+	if b.last.IsZero() {
+		b.tokens = limit.burst()
+	} else {
+		b.tokens = math.Min(limit.burst(), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	}
+	b.last = now
+}
+
+// available returns whether n tokens are available, otherwise returning how long until they are, or zero if they
+// never will be, as more are needed than the burst
+func (b *tokenBucket) available(limit RateLimit, n int, now time.Time) (bool, time.Duration) {
+	if limit.Rate <= 0 {
+		return true, 0
+	}
+	b.refill(limit, now)
+	if b.tokens >= float64(n) {
+		return true, 0
+	}
+	if float64(n) > limit.burst() {
+		return false, 0
+	}
+	return false, time.Duration((float64(n) - b.tokens) / limit.Rate * float64(time.Second))
+}
+
+// take takes n tokens, which must be available
+func (b *tokenBucket) take(limit RateLimit, n int) {
+	if limit.Rate > 0 {
+		b.tokens -= float64(n)
+	}
+}
+
+// usage records requests on a single day
+type usage struct {
+	requests uint64
+	rejected uint64
+}
+
+// rateLimiter enforces rate limits and daily quotas per authenticated subject and per backend
+type rateLimiter struct {
+	mu             sync.Mutex
+	defaultLimit   RateLimit
+	accountLimits  map[string]RateLimit // limits by subject (system|value), overriding the default
+	backendLimits  map[string]RateLimit // limits by backend: an identifier namespace uri or gRPC service name
+	subjectBuckets map[string]*tokenBucket
+	backendBuckets map[string]*tokenBucket
+	day            string
+	subjectUsage   map[string]*usage
+	backendUsage   map[string]*usage
+	now            func() time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		accountLimits:  make(map[string]RateLimit),
+		backendLimits:  make(map[string]RateLimit),
+		subjectBuckets: make(map[string]*tokenBucket),
+		backendBuckets: make(map[string]*tokenBucket),
+		subjectUsage:   make(map[string]*usage),
+		backendUsage:   make(map[string]*usage),
+		now:            time.Now,
+	}
+}
+
+// rateLimits returns the server's rate limiter, creating it if necessary. The caller must hold the lock.
+func (sv *Server) rateLimits() *rateLimiter {
+	if sv.limits == nil {
+		sv.limits = newRateLimiter()
+	}
+	return sv.limits
+}
+
+// SetDefaultRateLimit sets the rate limit applied to each authenticated subject without a specific limit.
+// Unauthenticated requests are limited by client address.
+func (sv *Server) SetDefaultRateLimit(limit RateLimit) {
+	sv.mu.Lock()
+	rl := sv.rateLimits()
+	sv.mu.Unlock()
+	rl.mu.Lock()
+	rl.defaultLimit = limit
+	rl.mu.Unlock()
+	log.Printf("server: default rate limit: %v/s burst:%d daily quota:%d", limit.Rate, limit.Burst, limit.DailyQuota)
+}
+
+// SetAccountRateLimit sets the rate limit for the specified account, such as a service user
+func (sv *Server) SetAccountRateLimit(id *apiv1.Identifier, limit RateLimit) {
+	sv.mu.Lock()
+	rl := sv.rateLimits()
+	sv.mu.Unlock()
+	subject := id.GetSystem() + "|" + id.GetValue()
+	rl.mu.Lock()
+	rl.accountLimits[subject] = limit
+	rl.mu.Unlock()
+	log.Printf("server: rate limit for '%s': %v/s burst:%d daily quota:%d", subject, limit.Rate, limit.Burst, limit.DailyQuota)
+}
+
+// SetBackendRateLimit sets the rate limit, across all clients, for a backend.
+// The backend is an identifier namespace uri, such as identifiers.NHSNumber, for requests to resolve or map
+// identifiers, or otherwise a gRPC service name, such as 'apiv1.PractitionerDirectory'.
+func (sv *Server) SetBackendRateLimit(backend string, limit RateLimit) {
+	sv.mu.Lock()
+	rl := sv.rateLimits()
+	sv.mu.Unlock()
+	rl.mu.Lock()
+	rl.backendLimits[backend] = limit
+	rl.mu.Unlock()
+	log.Printf("server: rate limit for backend '%s': %v/s burst:%d daily quota:%d", backend, limit.Rate, limit.Burst, limit.DailyQuota)
+}
+
+// backendFor returns the backend for the specified request
+func backendFor(method string, req interface{}) string {
+	switch r := req.(type) {
+	case *apiv1.Identifier:
+		return r.GetSystem()
+	case *apiv1.IdentifierMapRequest:
+		return r.GetSystem()
+	}
+	if i := strings.LastIndex(method, "/"); i > 0 {
+		return strings.TrimPrefix(method[:i], "/")
+	}
+	return method
+}
+
+// backendsFor returns the backend of each request counted against rate limits for the specified request. A
+// request for several patients counts as a request for each, to the backend for the system of its identifier,
+// as each is fetched separately.
+func backendsFor(method string, req interface{}) []string {
+	if r, ok := req.(*apiv1.PatientsRequest); ok && len(r.GetIdentifiers()) > 0 {
+		backends := make([]string, len(r.GetIdentifiers()))
+		for i, id := range r.GetIdentifiers() {
+			backends[i] = id.GetSystem()
+		}
+		return backends
+	}
+	return []string{backendFor(method, req)}
+}
+
+// rollover resets usage at the start of a new day, logging the previous day's usage. The caller must hold the lock.
+func (rl *rateLimiter) rollover(now time.Time) {
+	day := now.UTC().Format("2006-01-02")
+	if day == rl.day {
+		return
+	}
+	if rl.day != "" {
+		for subject, u := range rl.subjectUsage {
+			log.Printf("server: usage on %s for '%s': requests:%d rejected:%d", rl.day, subject, u.requests, u.rejected)
+		}
+		for backend, u := range rl.backendUsage {
+			log.Printf("server: usage on %s for backend '%s': requests:%d rejected:%d", rl.day, backend, u.requests, u.rejected)
+		}
+	}
+	rl.day = day
+	rl.subjectBuckets = make(map[string]*tokenBucket) // so that buckets for anonymous clients do not accumulate
+	rl.subjectUsage = make(map[string]*usage)
+	rl.backendUsage = make(map[string]*usage)
+}
+
+// check takes a token for the subject, and for each backend with a limit, for a request to each of the backends
+// specified, and checks their daily quotas, returning how long the caller should wait if the request is not
+// permitted, or zero if it never will be, as it needs more tokens than the burst.
+// Every limit and quota is checked before any tokens are taken, and only permitted requests count towards quotas,
+// so that clients retrying rejected requests do not use up their rate limit or quota.
+func (rl *rateLimiter) check(subject string, backends ...string) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	now := rl.now()
+	rl.rollover(now)
+	limit, found := rl.accountLimits[subject]
+	if !found {
+		limit = rl.defaultLimit
+	}
+	su := rl.subjectUsage[subject]
+	if su == nil {
+		su = new(usage)
+		rl.subjectUsage[subject] = su
+	}
+	sb := rl.subjectBuckets[subject]
+	if sb == nil {
+		sb = new(tokenBucket)
+		rl.subjectBuckets[subject] = sb
+	}
+	n := len(backends)
+	if ok, wait := sb.available(limit, n, now); !ok {
+		su.rejected++
+		return false, wait
+	}
+	if limit.DailyQuota > 0 && su.requests+uint64(n) > limit.DailyQuota {
+		su.rejected++
+		return false, untilTomorrow(now)
+	}
+	var limited []string // backends with limits, in the order first requested
+	counts := make(map[string]int)
+	for _, backend := range backends {
+		if _, found := rl.backendLimits[backend]; found {
+			if counts[backend] == 0 {
+				limited = append(limited, backend)
+			}
+			counts[backend]++
+		}
+	}
+	for _, backend := range limited {
+		backendLimit := rl.backendLimits[backend]
+		bu := rl.backendUsage[backend]
+		if bu == nil {
+			bu = new(usage)
+			rl.backendUsage[backend] = bu
+		}
+		bb := rl.backendBuckets[backend]
+		if bb == nil {
+			bb = new(tokenBucket)
+			rl.backendBuckets[backend] = bb
+		}
+		if ok, wait := bb.available(backendLimit, counts[backend], now); !ok {
+			bu.rejected++
+			su.rejected++
+			return false, wait
+		}
+		if backendLimit.DailyQuota > 0 && bu.requests+uint64(counts[backend]) > backendLimit.DailyQuota {
+			bu.rejected++
+			su.rejected++
+			return false, untilTomorrow(now)
+		}
+	}
+	sb.take(limit, n)
+	su.requests += uint64(n)
+	for _, backend := range limited {
+		rl.backendBuckets[backend].take(rl.backendLimits[backend], counts[backend])
+		rl.backendUsage[backend].requests += uint64(counts[backend])
+	}
+	return true, 0
+}
+
+func untilTomorrow(now time.Time) time.Duration {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC).Sub(now)
+}
+
+// usage returns today's usage by subject and by backend
+func (rl *rateLimiter) usage() *apiv1.ListUsageResponse {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.rollover(rl.now())
+	result := &apiv1.ListUsageResponse{Date: rl.day}
+	for subject, u := range rl.subjectUsage {
+		limit, found := rl.accountLimits[subject]
+		if !found {
+			limit = rl.defaultLimit
+		}
+		result.Subjects = append(result.Subjects, &apiv1.UsageCount{Name: subject, Requests: u.requests, Rejected: u.rejected, DailyQuota: limit.DailyQuota})
+	}
+	for backend, u := range rl.backendUsage {
+		result.Backends = append(result.Backends, &apiv1.UsageCount{Name: backend, Requests: u.requests, Rejected: u.rejected, DailyQuota: rl.backendLimits[backend].DailyQuota})
+	}
+	sort.Slice(result.Subjects, func(i, j int) bool { return result.Subjects[i].Name < result.Subjects[j].Name })
+	sort.Slice(result.Backends, func(i, j int) bool { return result.Backends[i].Name < result.Backends[j].Name })
+	return result
+}
+
+// subjectFor returns the subject for rate limiting from the context: the authenticated user or, for
+// unauthenticated requests such as login, the address of the client, so that one client cannot exhaust the
+// limit for all others
+func subjectFor(ctx context.Context) string {
+	if user := GetContextData(ctx).GetAuthenticatedUser(); user != nil {
+		return user.GetSystem() + "|" + user.GetValue()
+	}
+	return anonymousSubject + "|" + clientAddress(ctx)
+}
+
+// clientAddress returns the address of the client making a request. For requests from the REST gateway,
+// which connects in-process, this is the address of the REST client, which the gateway appends to any
+// x-forwarded-for header; earlier addresses in that header are set by the client and so are not trusted.
+func clientAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if p.Addr.Network() == "pipe" {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if fwd := md.Get("x-forwarded-for"); len(fwd) > 0 {
+			addresses := strings.Split(fwd[len(fwd)-1], ",")
+			return strings.TrimSpace(addresses[len(addresses)-1])
+		}
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
+
+// limit checks the rate limits for a request, returning a ResourceExhausted error if the request is not permitted,
+// and setting retry-after metadata if it may be retried
+func (rl *rateLimiter) limit(ctx context.Context, method string, req interface{}, setHeader func(metadata.MD) error) error {
+	subject := subjectFor(ctx)
+	ok, wait := rl.check(subject, backendsFor(method, req)...)
+	if ok {
+		return nil
+	}
+	if wait == 0 {
+		log.Printf("server: rate limit exceeded for '%s' calling '%s': request exceeds burst", subject, method)
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded: request is for more items than permitted at once")
+	}
+	seconds := int(math.Ceil(wait.Seconds()))
+	setHeader(metadata.Pairs(retryAfterHeader, strconv.Itoa(seconds)))
+	log.Printf("server: rate limit exceeded for '%s' calling '%s': retry after %ds", subject, method, seconds)
+	return status.Errorf(codes.ResourceExhausted, "rate limit exceeded: retry after %d seconds", seconds)
+}
+
+// unaryRateLimitInterceptor enforces rate limits; it must run after authentication so the subject is known
+func (sv *Server) unaryRateLimitInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if _, found := noRateLimitEndpoints[info.FullMethod]; !found {
+		if err := sv.limits.limit(ctx, info.FullMethod, req, func(md metadata.MD) error { return grpc.SetHeader(ctx, md) }); err != nil {
+			return nil, err
+		}
+	}
+	return handler(ctx, req)
+}
+
+// rateLimitedStream checks rate limits for each message received on a stream
+type rateLimitedStream struct {
+	grpc.ServerStream
+	limits *rateLimiter
+	method string
+}
+
+func (s *rateLimitedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.limits.limit(s.Context(), s.method, m, s.ServerStream.SetHeader)
+}
+
+func (sv *Server) streamRateLimitInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &rateLimitedStream{ss, sv.limits, info.FullMethod})
+}
+
+// endpoints that are not rate limited
+var noRateLimitEndpoints = map[string]struct{}{
+	"/grpc.health.v1.Health/Check": struct{}{},
+}
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimit(t *testing.T) {
+	now := time.Date(2020, 4, 1, 23, 59, 0, 0, time.UTC)
+	sv := New(Options{})
+	sv.SetDefaultRateLimit(RateLimit{Rate: 1, Burst: 2})
+	sv.SetAccountRateLimit(&apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "quota"}, RateLimit{DailyQuota: 3})
+	sv.SetBackendRateLimit(identifiers.NHSNumber, RateLimit{Rate: 10, Burst: 1})
+	sv.limits.now = func() time.Time { return now }
+
+	call := func(user string, req interface{}) (time.Duration, error) {
+		ctx := context.WithValue(context.Background(), userContextKey, &UserContextData{
+			authenticatedUser: &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: user},
+		})
+		var retryAfter time.Duration
+		err := sv.limits.limit(ctx, "/apiv1.Identifiers/GetIdentifier", req, func(md metadata.MD) error {
+			if v := md.Get(retryAfterHeader); len(v) > 0 {
+				retryAfter, _ = time.ParseDuration(v[0] + "s")
+			}
+			return nil
+		})
+		return retryAfter, err
+	}
+	snomed := &apiv1.Identifier{System: identifiers.SNOMEDCT, Value: "24700007"}
+
+	// a burst of two calls is permitted, after which the client must wait
+	for i := 0; i < 2; i++ {
+		if _, err := call("client1", snomed); err != nil {
+			t.Fatal(err)
+		}
+	}
+	retryAfter, err := call("client1", snomed)
+	if status.Code(err) != codes.ResourceExhausted || retryAfter != time.Second {
+		t.Fatalf("expected rate limit with retry after 1s, got: %v (retry after %v)", err, retryAfter)
+	}
+	// other clients have their own limit
+	if _, err := call("client2", snomed); err != nil {
+		t.Fatal(err)
+	}
+	// and limits recover over time
+	now = now.Add(2 * time.Second)
+	if _, err := call("client1", snomed); err != nil {
+		t.Fatal(err)
+	}
+
+	// backend limits are shared between clients
+	nnn := &apiv1.Identifier{System: identifiers.NHSNumber, Value: "1111111111"}
+	now = now.Add(10 * time.Second)
+	if _, err := call("client3", nnn); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := call("client4", nnn); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected backend rate limit, got: %v", err)
+	}
+
+	// daily quotas are reset at midnight (UTC)
+	for i := 0; i < 3; i++ {
+		sv.limits.check(identifiers.ConciergeServiceUser+"|quota", identifiers.SNOMEDCT)
+	}
+	if ok, wait := sv.limits.check(identifiers.ConciergeServiceUser+"|quota", identifiers.SNOMEDCT); ok || wait != 48*time.Second {
+		t.Fatalf("expected daily quota to be exceeded until midnight, got: %t %v", ok, wait)
+	}
+	usage := sv.limits.usage()
+	if usage.GetDate() != "2020-04-01" {
+		t.Fatalf("unexpected usage date: %s", usage.GetDate())
+	}
+	for _, u := range usage.GetSubjects() {
+		if u.GetName() == identifiers.ConciergeServiceUser+"|quota" && (u.GetRequests() != 3 || u.GetRejected() != 1 || u.GetDailyQuota() != 3) {
+			t.Fatalf("unexpected usage: %v", u)
+		}
+	}
+	now = now.Add(time.Minute)
+	if ok, _ := sv.limits.check(identifiers.ConciergeServiceUser+"|quota", identifiers.SNOMEDCT); !ok {
+		t.Fatal("expected daily quota to be reset")
+	}
+
+	// requests rejected by rate limits do not count towards quotas
+	sv.SetAccountRateLimit(&apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "retry"}, RateLimit{Rate: 1, Burst: 1, DailyQuota: 2})
+	for i := 0; i < 5; i++ {
+		sv.limits.check(identifiers.ConciergeServiceUser+"|retry", identifiers.SNOMEDCT)
+	}
+	now = now.Add(time.Second)
+	if ok, wait := sv.limits.check(identifiers.ConciergeServiceUser+"|retry", identifiers.SNOMEDCT); !ok {
+		t.Fatalf("expected request within quota after rejected retries to be permitted, got wait: %v", wait)
+	}
+	now = now.Add(time.Second)
+	if ok, wait := sv.limits.check(identifiers.ConciergeServiceUser+"|retry", identifiers.SNOMEDCT); ok || wait < time.Hour {
+		t.Fatalf("expected daily quota to be exceeded, got: %t %v", ok, wait)
+	}
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	sv := New(Options{})
+	sv.SetDefaultRateLimit(RateLimit{Rate: 1, Burst: 1})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return req, nil }
+	info := &grpc.UnaryServerInfo{FullMethod: "/apiv1.Identifiers/GetIdentifier"}
+	req := &apiv1.Identifier{System: identifiers.SNOMEDCT, Value: "24700007"}
+	client1 := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1000}})
+	if _, err := sv.unaryRateLimitInterceptor(client1, req, info, handler); err != nil {
+		t.Fatal(err)
+	}
+	client1 = peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1001}})
+	if _, err := sv.unaryRateLimitInterceptor(client1, req, info, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected anonymous requests to be rate limited, got: %v", err)
+	}
+	// anonymous clients are limited by address, including those using the REST gateway, for which only the
+	// address appended to x-forwarded-for by the gateway is used
+	client2 := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.2"), Port: 1000}})
+	if _, err := sv.unaryRateLimitInterceptor(client2, req, info, handler); err != nil {
+		t.Fatal(err)
+	}
+	gateway := func(fwd string) context.Context {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: pipeAddr{}})
+		return metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", fwd))
+	}
+	if _, err := sv.unaryRateLimitInterceptor(gateway("192.0.2.3"), req, info, handler); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sv.unaryRateLimitInterceptor(gateway("192.0.2.4, 192.0.2.3"), req, info, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected rate limit for client setting x-forwarded-for, got: %v", err)
+	}
+	// requests for several patients count as a request for each
+	sv.SetAccountRateLimit(&apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "pas"}, RateLimit{Rate: 1, Burst: 3})
+	pas := context.WithValue(context.Background(), userContextKey, &UserContextData{authenticatedUser: &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "pas"}})
+	patients := &grpc.UnaryServerInfo{FullMethod: "/apiv1.PatientDirectory/GetPatients"}
+	ids := []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "1111111111"}, {System: identifiers.NHSNumber, Value: "2222222222"}}
+	if _, err := sv.unaryRateLimitInterceptor(pas, &apiv1.PatientsRequest{Identifiers: ids}, patients, handler); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sv.unaryRateLimitInterceptor(pas, &apiv1.PatientsRequest{Identifiers: ids}, patients, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected request for patients to count each patient, got: %v", err)
+	}
+	// a request for patients rejected by the limit for one backend uses none of the other limits or quotas
+	sv.SetAccountRateLimit(&apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "batch"}, RateLimit{Rate: 1, Burst: 3, DailyQuota: 3})
+	sv.SetBackendRateLimit(identifiers.CardiffAndValeCRN, RateLimit{Rate: 1, Burst: 1})
+	batch := context.WithValue(context.Background(), userContextKey, &UserContextData{authenticatedUser: &apiv1.Identifier{System: identifiers.ConciergeServiceUser, Value: "batch"}})
+	var retryAfter metadata.MD
+	setHeader := func(md metadata.MD) error { retryAfter = md; return nil }
+	crns := &apiv1.PatientsRequest{Identifiers: []*apiv1.Identifier{{System: identifiers.NHSNumber, Value: "1111111111"},
+		{System: identifiers.CardiffAndValeCRN, Value: "A999998"}, {System: identifiers.CardiffAndValeCRN, Value: "A999997"}}}
+	if err := sv.limits.limit(batch, patients.FullMethod, crns, setHeader); status.Code(err) != codes.ResourceExhausted || retryAfter != nil {
+		t.Fatalf("expected request exceeding backend burst to be rejected without retry, got: %v %v", err, retryAfter)
+	}
+	for i := 0; i < 3; i++ {
+		if err := sv.limits.limit(batch, patients.FullMethod, &apiv1.PatientsRequest{Identifiers: ids[:1]}, setHeader); err != nil {
+			t.Fatalf("request %d after rejected request for patients: %s", i, err)
+		}
+	}
+	if err := sv.limits.limit(batch, patients.FullMethod, &apiv1.PatientsRequest{Identifiers: ids[:1]}, setHeader); status.Code(err) != codes.ResourceExhausted || retryAfter == nil {
+		t.Fatalf("expected rate limit with retry, got: %v %v", err, retryAfter)
+	}
+	// health checks are never rate limited
+	health := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	if _, err := sv.unaryRateLimitInterceptor(context.Background(), nil, health, handler); err != nil {
+		t.Fatal(err)
+	}
+}
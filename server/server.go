@@ -8,11 +8,14 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/rs/cors"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/resilience"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -31,6 +34,20 @@ type Provider interface {
 	Close() error
 }
 
+// HealthChecker is an optional interface for a Provider that can report on its health
+type HealthChecker interface {
+	// CheckHealth returns an error if the provider is not able to serve requests
+	CheckHealth(ctx context.Context) error
+}
+
+// Cache is a cache that can be inspected and flushed at runtime
+type Cache interface {
+	// ItemCount returns the number of items in the cache
+	ItemCount() int
+	// Flush removes all items from the cache
+	Flush()
+}
+
 // Server represents a combined gRPC and REST server
 // Generate self-signed local development certificates using:
 // openssl req -newkey rsa:2048 -nodes -keyout domain.key -x509 -days 365 -out domain.crt
@@ -40,6 +57,12 @@ type Server struct {
 	Options
 	auth      *Auth
 	providers map[string]Provider
+
+	mu        sync.Mutex
+	certs     *certificateReloader
+	reloaders map[string]func() error
+	caches    map[string]Cache
+	limits    *rateLimiter
 }
 
 // New creates a new server
@@ -51,12 +74,15 @@ func New(opts Options) *Server {
 
 // Options defines the options for a server.
 type Options struct {
-	RPCPort     int // port for main gRPC server
-	RESTPort    int // port for a gRPC gateway - switched off if zero
-	GRPCWebPort int // port for a gRPC-Web server - switched off if zero
+	RPCPort     int    // port for main gRPC server
+	RESTPort    int    // port for a gRPC gateway - switched off if zero
+	GRPCWebPort int    // port for a gRPC-Web server - switched off if zero
+	SinglePort  bool   // serve gRPC and the REST gateway together on RPCPort, ignoring RESTPort
+	UnixSocket  string // path of a unix domain socket on which to additionally serve gRPC - switched off if empty
 
-	CertFile string
-	KeyFile  string
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string // CA certificates used to verify client certificates, for mutual TLS - switched off if empty
 }
 
 // Close frees up any associated resources
@@ -84,6 +110,87 @@ func (sv *Server) Register(name string, p Provider) {
 	log.Printf("server: registered provider: '%s'", name)
 }
 
+// RegisterCache registers a named cache, so that it can be inspected and flushed at runtime
+func (sv *Server) RegisterCache(name string, c Cache) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	if sv.caches == nil {
+		sv.caches = make(map[string]Cache)
+	}
+	sv.caches[name] = c
+	log.Printf("server: registered cache: '%s'", name)
+}
+
+// RegisterReloader registers a function to be called when the server is asked to reload
+// its configuration, such as on receipt of SIGHUP. This may be called while the server is running.
+func (sv *Server) RegisterReloader(name string, f func() error) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	if sv.reloaders == nil {
+		sv.reloaders = make(map[string]func() error)
+	}
+	sv.reloaders[name] = f
+	log.Printf("server: registered reloader: '%s'", name)
+}
+
+// Reload re-reads the TLS certificate and key, if in use, and calls each registered reloader,
+// without interrupting existing client connections.
+// All reloaders are called even if one fails; the first error is returned.
+// Reloads are serialised, so that reloaders need not be safe for concurrent use.
+func (sv *Server) Reload() error {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	var result error
+	if sv.certs != nil {
+		if err := sv.certs.Reload(); err != nil {
+			log.Printf("server: failed to reload certificate: %s", err)
+			result = err
+		}
+	}
+	for name, f := range sv.reloaders {
+		if err := f(); err != nil {
+			log.Printf("server: failed to reload '%s': %s", name, err)
+			if result == nil {
+				result = err
+			}
+			continue
+		}
+		log.Printf("server: reloaded '%s'", name)
+	}
+	return result
+}
+
+// restHandler returns the handler for REST requests to the gateway, handling Kerberos ticket exchange and
+// client certificates, if configured, within the CORS configuration so that all responses have CORS headers
+func (sv *Server) restHandler(gateway http.Handler, clientCertificates bool) http.Handler {
+	handler := gateway
+	if sv.auth != nil && sv.auth.negotiate != nil {
+		handler = sv.auth.negotiateHandler(handler)
+	}
+	if sv.auth != nil && clientCertificates {
+		handler = sv.auth.clientCertificateHandler(handler)
+	}
+	log.Printf("server: warning: using CORS 'allow-all' permissions")
+	return cors.New(cors.Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{
+			http.MethodHead,
+			http.MethodGet,
+			http.MethodPost,
+			http.MethodPut,
+			http.MethodPatch,
+			http.MethodDelete,
+		},
+		AllowedHeaders:   []string{"*"},
+		ExposedHeaders:   []string{"*"},
+		AllowCredentials: true}).Handler(handler)
+}
+
+// useTLS returns whether the server has been configured to use TLS
+func (sv *Server) useTLS() bool {
+	return sv.Options.CertFile != "" && sv.Options.KeyFile != ""
+}
+
 // RunServer runs a GRPC and a gateway REST server concurrently
 func (sv *Server) RunServer() error {
 	ctx := context.Background()
@@ -92,26 +199,30 @@ func (sv *Server) RunServer() error {
 
 	// listen for OS signals for logging and graceful shutdown
 	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, os.Interrupt, os.Kill, syscall.SIGTERM)
+	signal.Notify(sigs, os.Interrupt, os.Kill, syscall.SIGTERM, syscall.SIGHUP)
 	defer signal.Stop(sigs)
 
 	// configure main gRPC server
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", sv.RPCPort))
-	if err != nil {
-		return fmt.Errorf("failed to initialize TCP listen: %v", err)
-	}
-	defer lis.Close()
 	opts := make([]grpc.ServerOption, 0)
 	if sv.auth != nil {
-		opts = append(opts, grpc.UnaryInterceptor(sv.unaryAuthInterceptor))
-		opts = append(opts, grpc.StreamInterceptor(sv.streamAuthInterceptor))
+		opts = append(opts, grpc.ChainUnaryInterceptor(sv.unaryAuthInterceptor))
+		opts = append(opts, grpc.ChainStreamInterceptor(sv.streamAuthInterceptor))
+	}
+	if sv.limits != nil { // rate limits are applied after authentication, so that limits can be per user
+		opts = append(opts, grpc.ChainUnaryInterceptor(sv.unaryRateLimitInterceptor))
+		opts = append(opts, grpc.ChainStreamInterceptor(sv.streamRateLimitInterceptor))
 	}
-	if sv.Options.CertFile != "" && sv.Options.KeyFile != "" {
-		creds, err := credentials.NewServerTLSFromFile(sv.Options.CertFile, sv.Options.KeyFile)
+	var certs *certificateReloader
+	if sv.useTLS() {
+		var err error
+		certs, err = newCertificateReloader(sv.Options.CertFile, sv.Options.KeyFile, sv.Options.ClientCAFile)
 		if err != nil {
 			return err
 		}
-		opts = append(opts, grpc.Creds(creds))
+		sv.mu.Lock()
+		sv.certs = certs
+		sv.mu.Unlock()
+		opts = append(opts, grpc.Creds(credentials.NewTLS(certs.serverTLSConfig())))
 	}
 	grpcServer := grpc.NewServer(opts...)
 	health.RegisterHealthServer(grpcServer, sv)
@@ -120,73 +231,118 @@ func (sv *Server) RunServer() error {
 		log.Printf("server: registered '%s' service", name)
 	}
 
-	// configure HTTP reverse gateway
-	clientAddr := fmt.Sprintf("localhost:%d", sv.RPCPort)
-	addr := fmt.Sprintf(":%d", sv.RESTPort)
-	var dialOpts []grpc.DialOption
-	if sv.Options.CertFile == "" || sv.Options.KeyFile == "" {
+	// the HTTP reverse gateway connects to the gRPC server in-process, rather than looping back over TCP
+	internalLis := newPipeListener()
+	defer internalLis.Close()
+	dialOpts := []grpc.DialOption{
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return internalLis.Dial() }),
+	}
+	if certs == nil {
 		dialOpts = append(dialOpts, grpc.WithInsecure())
 	} else {
-		creds, err := credentials.NewClientTLSFromFile(sv.Options.CertFile, "")
-		if err != nil {
-			return err
-		}
-		dialOpts = append(dialOpts, grpc.WithTransportCredentials(creds))
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(certs.clientTLSConfig())))
 	}
 	mux := runtime.NewServeMux(
 		runtime.WithIncomingHeaderMatcher(headerMatcher),                                    // handle Accept-Language
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),                            // handle Retry-After and stale responses
 		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{OrigName: false}), // handle JSON camelcase
 	)
 	for name, provider := range sv.providers {
-		if err := provider.RegisterHTTPProxy(ctx, mux, clientAddr, dialOpts); err != nil {
+		if err := provider.RegisterHTTPProxy(ctx, mux, internalEndpoint, dialOpts); err != nil {
 			log.Printf("server: failed to register reverse http proxy for '%s':%s", name, err)
 		} else {
 			log.Printf("server: registered reverse http proxy for '%s'", name)
 		}
 	}
-	httpServer := &http.Server{
-		Addr:         addr,
-		Handler:      mux,
-		ReadTimeout:  5 * time.Second,
-		WriteTimeout: 10 * time.Second,
-	}
 
-	// add CORS configuration
-	log.Printf("server: warning: using CORS 'allow-all' permissions")
-	httpServer.Handler = cors.New(cors.Options{
-		AllowedOrigins: []string{"*"},
-		AllowedMethods: []string{
-			http.MethodHead,
-			http.MethodGet,
-			http.MethodPost,
-			http.MethodPut,
-			http.MethodPatch,
-			http.MethodDelete,
-		},
-		AllowedHeaders:   []string{"*"},
-		ExposedHeaders:   []string{"*"},
-		AllowCredentials: true}).Handler(httpServer.Handler)
+	handler := sv.restHandler(mux, certs != nil && sv.ClientCAFile != "")
+
+	var httpServer *http.Server
+	var grpcLis net.Listener
+	if sv.SinglePort {
+		// serve gRPC and REST on the same port, routing gRPC requests directly to the gRPC server.
+		// read and write timeouts are not set, as these would otherwise terminate long-running gRPC streams, but
+		// slow or idle clients are still disconnected, as neither header nor idle timeouts apply to open streams.
+		httpServer = &http.Server{
+			Addr:              fmt.Sprintf(":%d", sv.RPCPort),
+			Handler:           grpcHandlerFunc(grpcServer, handler, certs == nil),
+			ReadHeaderTimeout: 5 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+		}
+	} else {
+		var err error
+		grpcLis, err = net.Listen("tcp", fmt.Sprintf(":%d", sv.RPCPort))
+		if err != nil {
+			return fmt.Errorf("failed to initialize TCP listen: %v", err)
+		}
+		defer grpcLis.Close()
+		if sv.RESTPort != 0 {
+			httpServer = &http.Server{
+				Addr:         fmt.Sprintf(":%d", sv.RESTPort),
+				Handler:      handler,
+				ReadTimeout:  5 * time.Second,
+				WriteTimeout: 10 * time.Second,
+			}
+		}
+	}
+	if httpServer != nil && certs != nil {
+		httpServer.TLSConfig = certs.serverTLSConfig()
+	}
+	var unixLis net.Listener
+	if sv.UnixSocket != "" {
+		var err error
+		if unixLis, err = listenUnix(sv.UnixSocket); err != nil {
+			return err
+		}
+		defer unixLis.Close()
+	}
 
 	// and now run the servers
 	g, ctx := errgroup.WithContext(ctx)
 	g.Go(func() error {
-		log.Printf("server: gRPC Listening on %s\n", lis.Addr().String())
-		return grpcServer.Serve(lis)
+		return grpcServer.Serve(internalLis)
 	})
-	g.Go(func() error {
-		if sv.Options.CertFile == "" || sv.Options.KeyFile == "" {
-			log.Printf("server: http listening on %s (not using https: no certificate or key specified)", addr)
-			return httpServer.ListenAndServe()
+	if grpcLis != nil {
+		g.Go(func() error {
+			log.Printf("server: gRPC Listening on %s\n", grpcLis.Addr().String())
+			return grpcServer.Serve(grpcLis)
+		})
+	}
+	if unixLis != nil {
+		g.Go(func() error {
+			log.Printf("server: gRPC Listening on unix socket %s\n", sv.UnixSocket)
+			return grpcServer.Serve(unixLis)
+		})
+	}
+	if httpServer != nil {
+		g.Go(func() error {
+			var err error
+			if certs == nil {
+				log.Printf("server: http listening on %s (not using https: no certificate or key specified)", httpServer.Addr)
+				err = httpServer.ListenAndServe()
+			} else {
+				log.Printf("server: https listening on %s\n", httpServer.Addr)
+				err = httpServer.ListenAndServeTLS("", "") // certificates provided by TLSConfig
+			}
+			if err == http.ErrServerClosed {
+				return nil
+			}
+			return err
+		})
+	}
+loop:
+	for {
+		select {
+		case sig := <-sigs:
+			log.Printf("server: received signal: %v", sig)
+			if sig == syscall.SIGHUP {
+				sv.Reload()
+				continue
+			}
+			break loop
+		case <-ctx.Done():
+			break loop
 		}
-		log.Printf("server: https listening on %s\n", addr)
-		return httpServer.ListenAndServeTLS(sv.Options.CertFile, sv.Options.KeyFile)
-	})
-	select {
-	case sig := <-sigs:
-		log.Printf("server: received signal: %v", sig)
-		break
-	case <-ctx.Done():
-		break
 	}
 	// graceful shutdown
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -210,20 +366,86 @@ func headerMatcher(headerName string) (mdName string, ok bool) {
 	if headerName == "Accept-Language" {
 		return "accept-language", true
 	}
+	if http.CanonicalHeaderKey(headerName) == http.CanonicalHeaderKey(apiKeyHeader) {
+		return apiKeyHeader, true
+	}
+	if http.CanonicalHeaderKey(headerName) == http.CanonicalHeaderKey(identifiers.JustificationHeader) {
+		return identifiers.JustificationHeader, true
+	}
 	return runtime.DefaultHeaderMatcher(headerName)
 }
 
+// outgoingHeaderMatcher passes retry-after metadata to REST clients as a standard HTTP header,
+// flags stale responses with X-Stale and X-Fetched headers, and superseded identifiers with X-Superseded-By
+func outgoingHeaderMatcher(key string) (string, bool) {
+	switch key {
+	case retryAfterHeader:
+		return "Retry-After", true
+	case staleHeader:
+		return "X-Stale", true
+	case fetchedHeader:
+		return "X-Fetched", true
+	case identifiers.SupersededHeader:
+		return "X-Superseded-By", true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
 // Check is a health check, implementing the grpc-health service
 // see https://godoc.org/google.golang.org/grpc/health/grpc_health_v1#HealthServer
 func (sv *Server) Check(ctx context.Context, r *health.HealthCheckRequest) (*health.HealthCheckResponse, error) {
 	response := new(health.HealthCheckResponse)
-	response.Status = health.HealthCheckResponse_SERVING
-	log.Printf("server: health check received: %s", response.Status)
+	response.Status = sv.servingStatus(ctx, r.GetService())
+	log.Printf("server: health check received for '%s': %s", r.GetService(), response.Status)
+	if response.Status == health.HealthCheckResponse_SERVICE_UNKNOWN {
+		return nil, status.Errorf(codes.NotFound, "unknown service: '%s'", r.GetService())
+	}
 	return response, nil
 }
 
+// healthWatchInterval is how often the serving status is checked for changes when watched
+const healthWatchInterval = time.Second
+
 // Watch is a streaming health check to issue changes in health status
 func (sv *Server) Watch(r *health.HealthCheckRequest, w health.Health_WatchServer) error {
-	log.Printf("server: health watch request received but not implemented: %+v", r)
-	return status.Error(codes.Unimplemented, "grpc health watch operation not implemented")
+	ticker := time.NewTicker(healthWatchInterval)
+	defer ticker.Stop()
+	last := health.HealthCheckResponse_ServingStatus(-1)
+	for {
+		if current := sv.servingStatus(w.Context(), r.GetService()); current != last {
+			if err := w.Send(&health.HealthCheckResponse{Status: current}); err != nil {
+				return err
+			}
+			last = current
+		}
+		select {
+		case <-w.Context().Done():
+			return w.Context().Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// servingStatus returns the serving status for the specified service, which may be empty for the server as
+// a whole, the name of a registered provider, or the name of an outbound backend such as 'empi'.
+// A backend is not serving when its circuit breaker is open.
+func (sv *Server) servingStatus(ctx context.Context, service string) health.HealthCheckResponse_ServingStatus {
+	if service == "" {
+		return health.HealthCheckResponse_SERVING
+	}
+	if p, found := sv.providers[service]; found {
+		if hc, ok := p.(HealthChecker); ok {
+			if err := hc.CheckHealth(ctx); err != nil {
+				return health.HealthCheckResponse_NOT_SERVING
+			}
+		}
+		return health.HealthCheckResponse_SERVING
+	}
+	if b, found := resilience.Lookup(service); found {
+		if b.State() == resilience.Open {
+			return health.HealthCheckResponse_NOT_SERVING
+		}
+		return health.HealthCheckResponse_SERVING
+	}
+	return health.HealthCheckResponse_SERVICE_UNKNOWN
 }
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/wardle/concierge/cache"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// staleHeader and fetchedHeader are the metadata keys used to flag a response as stale, served from the last
+// known value because a backend is unavailable, and to give the time that value was fetched (RFC 3339)
+const (
+	staleHeader   = "x-stale"
+	fetchedHeader = "x-fetched"
+)
+
+// LastKnown holds the last known value of items fetched from a backend service, so that they can be
+// served, flagged as stale, when that service is unavailable. Values are held in a cache store, so that they
+// may be shared between replicas and survive restarts, and are encrypted at rest if a key is given.
+// This is thread-safe.
+type LastKnown struct {
+	cache  *cache.Cache
+	maxAge time.Duration
+}
+
+// NewLastKnown creates a store of last known values, held in the cache store specified using keys with the
+// prefix specified, such as "empi-stale/", keeping each for the maximum age specified
+func NewLastKnown(store cache.Store, prefix string, maxAge time.Duration, key []byte) (*LastKnown, error) {
+	c, err := cache.New(store, prefix, maxAge, key)
+	if err != nil {
+		return nil, err
+	}
+	return &LastKnown{cache: c, maxAge: maxAge}, nil
+}
+
+// Set records the value for the specified key, fetched now
+func (lk *LastKnown) Set(key string, value proto.Message) {
+	if err := lk.cache.SetTimestamped(key, value, time.Now()); err != nil {
+		log.Printf("server: failed to record last known value for %s: %s", key, err)
+	}
+}
+
+// Get reads the last known value for the specified key into the message, returning when it was fetched
+func (lk *LastKnown) Get(key string, value proto.Message) (time.Time, bool) {
+	fetched, found, err := lk.cache.GetTimestamped(key, value)
+	if err != nil {
+		log.Printf("server: failed to read last known value for %s: %s", key, err)
+	}
+	return fetched, found
+}
+
+// Delete removes the value for the specified key
+func (lk *LastKnown) Delete(key string) {
+	if err := lk.cache.Delete(key); err != nil {
+		log.Printf("server: failed to remove last known value for %s: %s", key, err)
+	}
+}
+
+// MaxAge returns how long each item is kept
+func (lk *LastKnown) MaxAge() time.Duration {
+	return lk.maxAge
+}
+
+// ItemCount returns the number of items held
+func (lk *LastKnown) ItemCount() int {
+	return lk.cache.ItemCount()
+}
+
+// Flush removes all items
+func (lk *LastKnown) Flush() {
+	lk.cache.Flush()
+}
+
+// Unavailable returns whether an error from a backend service suggests the service is unavailable, rather than
+// that the request itself cannot be fulfilled, such as when an identifier is invalid or not found.
+func Unavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.Unauthenticated, codes.FailedPrecondition, codes.OutOfRange, codes.Unimplemented, codes.Canceled:
+		return false
+	}
+	return true
+}
+
+// SetStale flags the response to the current request as stale, giving the time that the data were fetched.
+// These are sent as gRPC response header metadata, and as X-Stale and X-Fetched HTTP headers to REST clients.
+func SetStale(ctx context.Context, fetched time.Time) {
+	md := metadata.Pairs(staleHeader, "true", fetchedHeader, fetched.UTC().Format(time.RFC3339))
+	if err := grpc.SetHeader(ctx, md); err != nil {
+		log.Printf("server: failed to flag response as stale: %s", err)
+	}
+}
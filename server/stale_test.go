@@ -0,0 +1,96 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/cache"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// testTransportStream records header metadata set by a handler
+type testTransportStream struct {
+	header metadata.MD
+}
+
+func (s *testTransportStream) Method() string { return "/apiv1.Identifiers/GetIdentifier" }
+func (s *testTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+func (s *testTransportStream) SendHeader(md metadata.MD) error { return s.SetHeader(md) }
+func (s *testTransportStream) SetTrailer(md metadata.MD) error { return nil }
+
+func TestLastKnown(t *testing.T) {
+	store := cache.NewMemoryStore()
+	lk, err := NewLastKnown(store, "empi-stale/", time.Hour, bytes.Repeat([]byte{42}, cache.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt := &apiv1.Patient{Lastname: "DUMMY"}
+	before := time.Now()
+	lk.Set("1111111111", pt)
+	o := new(apiv1.Patient)
+	fetched, found := lk.Get("1111111111", o)
+	if !found || !proto.Equal(o, pt) || fetched.Before(before) {
+		t.Fatalf("last known value not returned: %v %v %v", o, fetched, found)
+	}
+	if _, found := lk.Get("2222222222", o); found {
+		t.Fatal("unexpected last known value")
+	}
+	// values are held in the store, and so are shared with other replicas using it
+	replica, _ := NewLastKnown(store, "empi-stale/", time.Hour, bytes.Repeat([]byte{42}, cache.KeySize))
+	if _, found := replica.Get("1111111111", new(apiv1.Patient)); !found {
+		t.Fatal("last known value not shared")
+	}
+	lk.Flush()
+	if lk.ItemCount() != 0 {
+		t.Fatal("last known values not flushed")
+	}
+}
+
+func TestUnavailable(t *testing.T) {
+	tests := []struct {
+		err         error
+		unavailable bool
+	}{
+		{nil, false},
+		{errors.New("dial tcp: connection refused"), true},
+		{status.Error(codes.Unavailable, "circuit breaker open"), true},
+		{status.Error(codes.DeadlineExceeded, "timeout"), true},
+		{status.Error(codes.NotFound, "patient not found"), false},
+		{status.Error(codes.InvalidArgument, "invalid NHS number"), false},
+	}
+	for _, test := range tests {
+		if got := Unavailable(test.err); got != test.unavailable {
+			t.Errorf("unavailable(%v): expected %t, got %t", test.err, test.unavailable, got)
+		}
+	}
+}
+
+func TestSetStale(t *testing.T) {
+	stream := new(testTransportStream)
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	fetched := time.Date(2020, 6, 1, 9, 30, 0, 0, time.UTC)
+	SetStale(ctx, fetched)
+	if v := stream.header.Get(staleHeader); len(v) != 1 || v[0] != "true" {
+		t.Fatalf("response not flagged as stale: %v", stream.header)
+	}
+	if v := stream.header.Get(fetchedHeader); len(v) != 1 || v[0] != "2020-06-01T09:30:00Z" {
+		t.Fatalf("incorrect fetched time: %v", stream.header)
+	}
+	if h, _ := outgoingHeaderMatcher(staleHeader); h != "X-Stale" {
+		t.Fatalf("incorrect REST header for stale flag: %s", h)
+	}
+	if h, _ := outgoingHeaderMatcher(fetchedHeader); h != "X-Fetched" {
+		t.Fatalf("incorrect REST header for fetched time: %s", h)
+	}
+}
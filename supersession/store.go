@@ -0,0 +1,136 @@
+// Package supersession provides a persistent store of identifiers superseded by other identifiers, such as when
+// patient administration systems or the EMPI merge patient records, so that superseded identifiers still resolve.
+package supersession
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"sync"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/cache"
+	"github.com/wardle/concierge/identifiers"
+)
+
+// prefix is the prefix for the keys of supersessions in a shared store
+const prefix = "supersession/"
+
+// Store holds supersessions in a cache store, such as a PostgreSQL store shared between replicas, so that a
+// merge received by one replica is followed by all. Supersessions do not expire. Each supersession is held by
+// the identifier superseded, so that a later supersession of an identifier replaces an earlier one, and is
+// indexed by the surviving identifier, in a single entry listing the identifiers it supersedes, as the keys of
+// entries are hashed in an encrypted store and so cannot be searched. Index entries replaced by a later
+// supersession are ignored.
+// This is thread-safe, but replicas recording supersessions by the same surviving identifier at the same time
+// may each replace the other's index entry, so that one is missing from the merge history, although both are
+// followed when resolving identifiers.
+type Store struct {
+	mu         sync.Mutex // serialises updates to the index
+	superseded *cache.Cache
+	surviving  *cache.Cache
+}
+
+var _ identifiers.SupersessionStore = (*Store)(nil)
+
+// NewStore creates a store of supersessions using the cache store specified, encrypted using the key, if not nil
+func NewStore(store cache.Store, key []byte) (*Store, error) {
+	superseded, err := cache.New(store, prefix+"superseded/", 0, key)
+	if err != nil {
+		return nil, err
+	}
+	surviving, err := cache.New(store, prefix+"surviving/", 0, key)
+	if err != nil {
+		return nil, err
+	}
+	st := &Store{superseded: superseded, surviving: surviving}
+	log.Printf("supersession: %d superseded identifiers", st.Len())
+	return st, nil
+}
+
+func key(id *apiv1.Identifier) string {
+	return url.QueryEscape(id.GetSystem() + "|" + id.GetValue())
+}
+
+func sameIdentifier(id1 *apiv1.Identifier, id2 *apiv1.Identifier) bool {
+	return id1.GetSystem() == id2.GetSystem() && id1.GetValue() == id2.GetValue()
+}
+
+// Supersede records a supersession, replacing any earlier supersession of the same identifier
+func (st *Store) Supersede(ctx context.Context, s *apiv1.Supersession) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	previous, found, err := st.SupersededBy(ctx, s.GetSuperseded())
+	if err != nil {
+		return err
+	}
+	if err := st.index(s.GetSurviving(), s.GetSuperseded(), s); err != nil {
+		return err
+	}
+	if err := st.superseded.Set(key(s.GetSuperseded()), s); err != nil {
+		return err
+	}
+	if found && !sameIdentifier(previous.GetSurviving(), s.GetSurviving()) {
+		if err := st.index(previous.GetSurviving(), s.GetSuperseded(), nil); err != nil {
+			log.Printf("supersession: failed to remove replaced supersession: %s", err)
+		}
+	}
+	return nil
+}
+
+// index replaces the supersession of the identifier specified in the index entry for the surviving identifier,
+// removing it if the supersession is nil. The caller must hold the lock.
+func (st *Store) index(surviving *apiv1.Identifier, superseded *apiv1.Identifier, s *apiv1.Supersession) error {
+	entry := new(apiv1.MergeHistory)
+	if _, err := st.surviving.Get(key(surviving), entry); err != nil {
+		return err
+	}
+	ss := make([]*apiv1.Supersession, 0, len(entry.GetSupersessions())+1)
+	for _, other := range entry.GetSupersessions() {
+		if !sameIdentifier(other.GetSuperseded(), superseded) {
+			ss = append(ss, other)
+		}
+	}
+	if s != nil {
+		ss = append(ss, s)
+	}
+	if len(ss) == 0 {
+		return st.surviving.Delete(key(surviving))
+	}
+	return st.surviving.Set(key(surviving), &apiv1.MergeHistory{Current: surviving, Supersessions: ss})
+}
+
+// SupersededBy returns the supersession of the identifier specified, if it has been superseded
+func (st *Store) SupersededBy(ctx context.Context, id *apiv1.Identifier) (*apiv1.Supersession, bool, error) {
+	s := new(apiv1.Supersession)
+	found, err := st.superseded.Get(key(id), s)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	return s, true, nil
+}
+
+// Superseding returns the supersessions of identifiers that have been superseded by the identifier specified
+func (st *Store) Superseding(ctx context.Context, id *apiv1.Identifier) ([]*apiv1.Supersession, error) {
+	entry := new(apiv1.MergeHistory)
+	if _, err := st.surviving.Get(key(id), entry); err != nil {
+		return nil, err
+	}
+	var result []*apiv1.Supersession
+	for _, indexed := range entry.GetSupersessions() {
+		// the identifier may since have been superseded by another, if replicas recorded supersessions concurrently
+		s, found, err := st.SupersededBy(ctx, indexed.GetSuperseded())
+		if err != nil {
+			return nil, err
+		}
+		if found && sameIdentifier(s.GetSurviving(), id) {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+// Len returns the number of superseded identifiers
+func (st *Store) Len() int {
+	return st.superseded.ItemCount()
+}
@@ -0,0 +1,201 @@
+package supersession
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/cache"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const testSystem = "https://concierge.test/Id/crn"
+
+func id(value string) *apiv1.Identifier {
+	return &apiv1.Identifier{System: testSystem, Value: value}
+}
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "concierge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cache.db")
+	store, err := cache.OpenBoltStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := bytes.Repeat([]byte{42}, cache.KeySize)
+	st, err := NewStore(store, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, s := range []*apiv1.Supersession{
+		{Superseded: id("A1"), Surviving: id("A2")},
+		{Superseded: id("A3"), Surviving: id("A2")},
+		{Superseded: id("A3"), Surviving: id("A4")}, // replaces earlier supersession
+	} {
+		if err := st.Supersede(ctx, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.Close()
+	if store, err = cache.OpenBoltStore(path); err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if st, err = NewStore(store, key); err != nil {
+		t.Fatal(err)
+	}
+	if st.Len() != 2 {
+		t.Fatalf("expected 2 superseded identifiers, got %d", st.Len())
+	}
+	if s, found, _ := st.SupersededBy(ctx, id("A3")); !found || !proto.Equal(s.GetSurviving(), id("A4")) {
+		t.Fatalf("incorrect supersession of A3: %v", s)
+	}
+	if _, found, _ := st.SupersededBy(ctx, id("A2")); found {
+		t.Fatalf("A2 incorrectly superseded")
+	}
+	if ss, _ := st.Superseding(ctx, id("A2")); len(ss) != 1 || ss[0].GetSuperseded().GetValue() != "A1" {
+		t.Fatalf("incorrect identifiers superseded by A2: %v", ss)
+	}
+	// supersessions are encrypted, as they are held with cached results, and identifiers are not used as keys
+	keys, err := store.Keys(prefix)
+	if err != nil || len(keys) != 4 {
+		t.Fatalf("unexpected keys: %v (%v)", keys, err)
+	}
+	for _, k := range keys {
+		b, _, _ := store.Get(k)
+		if strings.Contains(k, "A1") || strings.Contains(k, "A2") || bytes.Contains(b, []byte("A2")) {
+			t.Fatalf("supersession not encrypted: %s", k)
+		}
+	}
+	// a replica may record a supersession after its index entry is replaced by another replica
+	st.mu.Lock()
+	err = st.index(id("A2"), id("A3"), &apiv1.Supersession{Superseded: id("A3"), Surviving: id("A2")})
+	st.mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ss, _ := st.Superseding(ctx, id("A2")); len(ss) != 1 {
+		t.Fatalf("replaced supersession returned: %v", ss)
+	}
+}
+
+func TestResolveSuperseded(t *testing.T) {
+	ctx := context.Background()
+	identifiers.RegisterResolver(testSystem, func(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
+		return &apiv1.Patient{Identifiers: []*apiv1.Identifier{id}}, nil
+	})
+	t.Cleanup(func() { identifiers.UnregisterResolver(testSystem) })
+	st, err := NewStore(cache.NewMemoryStore(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	identifiers.RegisterSupersessionStore(st)
+	defer identifiers.RegisterSupersessionStore(nil)
+
+	t1, _ := ptypes.TimestampProto(time.Date(2020, 6, 1, 9, 0, 0, 0, time.UTC))
+	t2, _ := ptypes.TimestampProto(time.Date(2020, 6, 2, 9, 0, 0, 0, time.UTC))
+	if err := identifiers.Supersede(ctx, &apiv1.Supersession{Superseded: id("B1"), Surviving: id("B2"), Recorded: t1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := identifiers.Supersede(ctx, &apiv1.Supersession{Superseded: id("B2"), Surviving: id("B3"), Recorded: t2}); err != nil {
+		t.Fatal(err)
+	}
+	// superseded identifiers resolve to the record with the current identifier
+	result, err := identifiers.Resolve(ctx, id("B1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pt := result.(*apiv1.Patient); !proto.Equal(pt.GetIdentifiers()[0], id("B3")) {
+		t.Fatalf("superseded identifier not followed: %v", pt)
+	}
+	// identifiers cannot supersede each other
+	if err := identifiers.Supersede(ctx, &apiv1.Supersession{Superseded: id("B3"), Surviving: id("B1")}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected cyclic supersession to be rejected, got: %v", err)
+	}
+	history, err := identifiers.MergeHistory(ctx, id("B2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(history.GetCurrent(), id("B3")) || len(history.GetSupersessions()) != 2 ||
+		history.GetSupersessions()[0].GetSuperseded().GetValue() != "B1" || history.GetSupersessions()[1].GetSuperseded().GetValue() != "B2" {
+		t.Fatalf("incorrect merge history: %v", history)
+	}
+}
+
+func TestConcurrentSupersede(t *testing.T) {
+	ctx := context.Background()
+	st, err := NewStore(cache.NewMemoryStore(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	identifiers.RegisterSupersessionStore(st)
+	defer identifiers.RegisterSupersessionStore(nil)
+	for i := 0; i < 50; i++ {
+		a, b := id(fmt.Sprintf("C%d", i)), id(fmt.Sprintf("D%d", i))
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for j, s := range []*apiv1.Supersession{{Superseded: a, Surviving: b}, {Superseded: b, Surviving: a}} {
+			wg.Add(1)
+			go func(j int, s *apiv1.Supersession) {
+				defer wg.Done()
+				errs[j] = identifiers.Supersede(ctx, s)
+			}(j, s)
+		}
+		wg.Wait()
+		if (errs[0] == nil) == (errs[1] == nil) {
+			t.Fatalf("expected exactly one of mutual supersessions to be recorded, got: %v", errs)
+		}
+	}
+}
+
+func TestMergeHistoryAccess(t *testing.T) {
+	ctx := context.Background()
+	identifiers.RegisterResolver(testSystem, func(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
+		return &apiv1.Patient{Identifiers: []*apiv1.Identifier{id}}, nil
+	})
+	identifiers.RegisterAccessPolicy("test-restricted", func(ctx context.Context, id *apiv1.Identifier, o proto.Message) (bool, string) {
+		return id.GetValue() == "E2", "restricted record"
+	})
+	t.Cleanup(func() {
+		identifiers.UnregisterResolver(testSystem)
+		identifiers.UnregisterAccessPolicy("test-restricted")
+	})
+	st, err := NewStore(cache.NewMemoryStore(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	identifiers.RegisterSupersessionStore(st)
+	defer identifiers.RegisterSupersessionStore(nil)
+	if err := identifiers.Supersede(ctx, &apiv1.Supersession{Superseded: id("E1"), Surviving: id("E2")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := identifiers.Supersede(ctx, &apiv1.Supersession{Superseded: id("F1"), Surviving: id("F2")}); err != nil {
+		t.Fatal(err)
+	}
+	svc := &identifiers.Server{}
+	for _, value := range []string{"E1", "E2"} {
+		if _, err := svc.GetMergeHistory(ctx, id(value)); status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("merge history of restricted record %s: expected permission denied, got: %v", value, err)
+		}
+	}
+	if history, err := svc.GetMergeHistory(ctx, id("F1")); err != nil || len(history.GetSupersessions()) != 1 {
+		t.Fatalf("incorrect merge history: %v (%v)", history, err)
+	}
+}
@@ -25,15 +25,22 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/cache"
+	"github.com/wardle/concierge/fake"
 	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/patients"
+	"github.com/wardle/concierge/resilience"
+	"github.com/wardle/concierge/server"
 	"github.com/wardle/concierge/wales/cav/soap"
-	"github.com/wardle/concierge/wales/empi"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
 
+// BackendName is the name of the CAV PMS backend for retries and circuit breaking, and for health checks
+const BackendName = "cav-pms"
+
 // PMSService represents the Cardiff and Vale Patient Management System (PMS) service.
 // This is thread-safe.
 type PMSService struct {
@@ -41,10 +48,52 @@ type PMSService struct {
 	password string
 	timeout  time.Duration
 	fake     bool
+	fakeData *fake.Data // synthetic data served in fake mode; default data are used if nil
 
 	tokenMu      sync.RWMutex
 	token        string
 	tokenExpires time.Time
+
+	lastKnownMu sync.RWMutex
+	lastKnown   *server.LastKnown // last known patients, served if the PMS is unavailable; nil if turned off
+	cacheStore  cache.Store       // store for last known patients; an in-memory store is used if nil
+	cacheKey    []byte            // key to encrypt last known patients, or nil
+}
+
+// SetCacheStore sets the store used for last known patients, and the key used to encrypt them, if not nil,
+// before serving requests
+func (pms *PMSService) SetCacheStore(store cache.Store, key []byte) {
+	pms.lastKnownMu.Lock()
+	defer pms.lastKnownMu.Unlock()
+	pms.cacheStore = store
+	pms.cacheKey = key
+}
+
+// SetStaleMaxAge sets how long the last known result for a patient may be served, flagged as stale, when the
+// PMS is unavailable. A duration of zero turns off serving stale results.
+func (pms *PMSService) SetStaleMaxAge(maxAge time.Duration) {
+	pms.lastKnownMu.Lock()
+	defer pms.lastKnownMu.Unlock()
+	switch {
+	case maxAge == 0:
+		pms.lastKnown = nil
+	case pms.lastKnown == nil || pms.lastKnown.MaxAge() != maxAge:
+		if pms.cacheStore == nil {
+			pms.cacheStore = cache.NewMemoryStore()
+		}
+		lk, err := server.NewLastKnown(pms.cacheStore, "cav-stale/", maxAge, pms.cacheKey)
+		if err != nil {
+			log.Printf("cav: failed to create store of last known patients: %s", err)
+		}
+		pms.lastKnown = lk
+	}
+	log.Printf("cav: serving stale results when unavailable for up to: %v", maxAge)
+}
+
+func (pms *PMSService) getLastKnown() *server.LastKnown {
+	pms.lastKnownMu.RLock()
+	defer pms.lastKnownMu.RUnlock()
+	return pms.lastKnown
 }
 
 // NewPMSService creates a new (thread-safe) PMS Service with the specified timeout
@@ -63,6 +112,18 @@ func NewPMSService(username string, password string, timeout time.Duration, fake
 	}
 }
 
+// SetFakeData sets the synthetic patients, clinics and documents served in fake mode, before serving requests
+func (pms *PMSService) SetFakeData(d *fake.Data) {
+	pms.fakeData = d
+}
+
+func (pms *PMSService) getFakeData() *fake.Data {
+	if pms.fakeData != nil {
+		return pms.fakeData
+	}
+	return fake.Default()
+}
+
 // ResolveIdentifier provides an identifier/value resolution service for CAV CRNs
 func (pms *PMSService) ResolveIdentifier(ctx context.Context, id *apiv1.Identifier) (proto.Message, error) {
 	if id.GetSystem() != identifiers.CardiffAndValeCRN {
@@ -72,18 +133,80 @@ func (pms *PMSService) ResolveIdentifier(ctx context.Context, id *apiv1.Identifi
 	return pms.FetchPatient(ctx, id.GetValue())
 }
 
+// GetPatient returns the patient with the CAV CRN specified
+func (pms *PMSService) GetPatient(ctx context.Context, id *apiv1.Identifier) (*apiv1.Patient, error) {
+	if id.GetSystem() != identifiers.CardiffAndValeCRN {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported system: expected '%s' got: '%s'", identifiers.CardiffAndValeCRN, id.GetSystem())
+	}
+	return pms.FetchPatient(ctx, id.GetValue())
+}
+
+// GetPatients returns the patients with the CAV CRNs specified
+func (pms *PMSService) GetPatients(ctx context.Context, r *apiv1.PatientsRequest) (*apiv1.PatientsResponse, error) {
+	return patients.Lookup(ctx, r.GetIdentifiers(), pms.GetPatient)
+}
+
+// SearchPatient is not supported by the CAV PMS; use the EMPI to search for patients
+func (pms *PMSService) SearchPatient(r *apiv1.PatientSearchRequest, s apiv1.PatientDirectory_SearchPatientServer) error {
+	return status.Errorf(codes.Unimplemented, "cav: patient search not supported")
+}
+
+// Invalidate removes the last known patients with the identifiers specified, such as when a patient administration
+// feed reports that a patient has changed or has been merged
+func (pms *PMSService) Invalidate(ids ...*apiv1.Identifier) {
+	if lk := pms.getLastKnown(); lk != nil {
+		for _, id := range ids {
+			if id.GetSystem() == identifiers.CardiffAndValeCRN {
+				lk.Delete(id.GetValue())
+			}
+		}
+	}
+}
+
+// Refresh records the patient specified, such as one received from a patient administration feed, as the last
+// known patient for each of its CRNs
+func (pms *PMSService) Refresh(pt *apiv1.Patient) {
+	if lk := pms.getLastKnown(); lk != nil {
+		if crns, ok := pt.GetIdentifiersForSystem(identifiers.CardiffAndValeCRN); ok {
+			for _, crn := range crns {
+				lk.Set(crn.GetValue(), pt)
+			}
+		}
+	}
+}
+
 // FetchPatient fetches patient data from the CAV PAS (PMS)
-// This query returns multiple rows for a single patient because of the address history
+// If the PMS is unavailable, the last known result is returned, flagged as stale, if serving stale results is turned on.
 func (pms *PMSService) FetchPatient(ctx context.Context, crn string) (*apiv1.Patient, error) {
+	pt, err := pms.fetchPatient(ctx, crn)
+	lk := pms.getLastKnown()
+	if lk == nil || pms.fake {
+		return pt, err
+	}
+	if err == nil {
+		lk.Set(crn, pt)
+		return pt, nil
+	}
+	if server.Unavailable(err) {
+		stale := new(apiv1.Patient)
+		if fetched, found := lk.Get(crn, stale); found {
+			log.Printf("cav: serving stale result for %s fetched %s: %s", crn, fetched.Format(time.RFC3339), err)
+			server.SetStale(ctx, fetched)
+			return stale, nil
+		}
+	}
+	return nil, err
+}
+
+// fetchPatient fetches patient data from the CAV PAS (PMS)
+// This query returns multiple rows for a single patient because of the address history
+func (pms *PMSService) fetchPatient(ctx context.Context, crn string) (*apiv1.Patient, error) {
 	if pms.fake {
-		if crn != "A999998" {
+		pt, found := pms.getFakeData().Patient(&apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: crn})
+		if !found {
 			return nil, status.Errorf(codes.NotFound, "No patient found with identifier %s", crn)
 		}
-		result, err := (&empi.App{Fake: true}).ResolveIdentifier(ctx, &apiv1.Identifier{Value: crn})
-		if err != nil {
-			return nil, err
-		}
-		return result.(*apiv1.Patient), nil
+		return pt, nil
 	}
 
 	ctx, cancelFunc := context.WithTimeout(ctx, pms.timeout)
@@ -109,6 +232,13 @@ func (pms *PMSService) FetchPatient(ctx context.Context, crn string) (*apiv1.Pat
 
 // PatientsForClinics returns the patients scheduled for the specified clinics on the specified dates
 func (pms *PMSService) PatientsForClinics(ctx context.Context, date time.Time, clinics []*apiv1.Identifier) ([]*apiv1.Patient, error) {
+	if pms.fake {
+		var result []*apiv1.Patient
+		for _, clinic := range clinics {
+			result = append(result, pms.getFakeData().PatientsForClinic(clinic, date)...)
+		}
+		return result, nil
+	}
 	ctx, cancelFunc := context.WithTimeout(ctx, pms.timeout)
 	defer cancelFunc()
 	token, err := pms.authenticationToken(ctx)
@@ -172,6 +302,14 @@ func (pms *PMSService) PublishDocument(ctx context.Context, r *apiv1.PublishDocu
 	} else {
 		uid = d.GetId().GetSystem() + "|" + d.GetId().GetValue()
 	}
+	if pms.fake {
+		n, err := pms.getFakeData().AddDocument(d)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("cav: published fake document '%s' for %s", uid, cavID.GetValue())
+		return &apiv1.PublishDocumentResponse{Id: &apiv1.Identifier{System: identifiers.CardiffAndValeDocID, Value: strconv.Itoa(n)}}, nil
+	}
 	ctx, cancelFunc := context.WithTimeout(ctx, pms.timeout)
 	defer cancelFunc()
 	docID, err := performReceiveFileByCRN(ctx, cavID.GetValue(), uid, "GENERAL LETTER", d.GetTitle(), d.GetData().GetData())
@@ -283,15 +421,18 @@ func performGetData(ctx context.Context, xmlData string, result interface{}) err
 		"XmlDataBlockIn": []string{xmlData},
 	}
 	endpointURL := "http://cav-wcp02.cardiffandvale.wales.nhs.uk/PmsInterface/WebService/PMSInterfaceWebService.asmx/GetData"
-	return performRequest(ctx, endpointURL, data.Encode(), result)
+	return resilience.Get(BackendName).Do(ctx, true, func(ctx context.Context) error {
+		return performRequest(ctx, endpointURL, data.Encode(), result)
+	})
 }
 
 // this uses a SOAP call, because the HTTP POST failed to work with base64 encoding for some reason
 func performReceiveFileByCRN(ctx context.Context, crn string, uid string, key string, source string, pdfData []byte) (string, error) {
 	service := soap.NewPMSInterfaceWebServiceSoap("http://cav-wcp02.cardiffandvale.wales.nhs.uk/PmsInterface/WebService/PMSInterfaceWebService.asmx", false, nil)
+	service.SetBackend(resilience.Get(BackendName))
 	fileType := ".pdf"
 	data := []byte(base64.StdEncoding.EncodeToString(pdfData))
-	response, err := service.ReceiveFileByCrn(&soap.ReceiveFileByCrn{
+	response, err := service.ReceiveFileByCrnContext(ctx, &soap.ReceiveFileByCrn{
 		BfsId:       uid, // unfortunately, this must be 15 digits or less
 		Crn:         crn,
 		Key:         key,
@@ -350,6 +491,9 @@ func performRequest(ctx context.Context, endpointURL string, post string, result
 	if resp.StatusCode != 200 {
 		log.Printf("cav: received error response: %+v", resp)
 		log.Printf("body: %v", string(body))
+		if resp.StatusCode < 500 {
+			return resilience.Permanent(errors.New("remote service error"))
+		}
 		return errors.New("remote service error")
 	}
 	return xml.Unmarshal(body, result)
@@ -3,13 +3,17 @@ package soap
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/xml"
+	"fmt"
 	"io/ioutil"
 	"math/rand"
 	"net"
 	"net/http"
 	"time"
+
+	"github.com/wardle/concierge/resilience"
 )
 
 // against "unused imports"
@@ -138,6 +142,11 @@ func NewPMSInterfaceWebServiceSoapWithTLSConfig(url string, tlsCfg *tls.Config,
 	}
 }
 
+// SetBackend sets the circuit breaker used for calls to the service
+func (service *PMSInterfaceWebServiceSoap) SetBackend(backend *resilience.Backend) {
+	service.client.SetBackend(backend)
+}
+
 func (service *PMSInterfaceWebServiceSoap) AddHeader(header interface{}) {
 	service.client.AddHeader(header)
 }
@@ -148,8 +157,12 @@ func (service *PMSInterfaceWebServiceSoap) SetHeader(header interface{}) {
 }
 
 func (service *PMSInterfaceWebServiceSoap) GetData(request *GetData) (*GetDataResponse, error) {
+	return service.GetDataContext(context.Background(), request)
+}
+
+func (service *PMSInterfaceWebServiceSoap) GetDataContext(ctx context.Context, request *GetData) (*GetDataResponse, error) {
 	response := new(GetDataResponse)
-	err := service.client.Call("http://localhost/PMSInterfaceWebService/GetData", request, response)
+	err := service.client.CallContext(ctx, "http://localhost/PMSInterfaceWebService/GetData", request, response)
 	if err != nil {
 		return nil, err
 	}
@@ -158,8 +171,12 @@ func (service *PMSInterfaceWebServiceSoap) GetData(request *GetData) (*GetDataRe
 }
 
 func (service *PMSInterfaceWebServiceSoap) GetData2(request *GetData2) (*GetData2Response, error) {
+	return service.GetData2Context(context.Background(), request)
+}
+
+func (service *PMSInterfaceWebServiceSoap) GetData2Context(ctx context.Context, request *GetData2) (*GetData2Response, error) {
 	response := new(GetData2Response)
-	err := service.client.Call("http://localhost/PMSInterfaceWebService/GetData2", request, response)
+	err := service.client.CallContext(ctx, "http://localhost/PMSInterfaceWebService/GetData2", request, response)
 	if err != nil {
 		return nil, err
 	}
@@ -168,8 +185,12 @@ func (service *PMSInterfaceWebServiceSoap) GetData2(request *GetData2) (*GetData
 }
 
 func (service *PMSInterfaceWebServiceSoap) ReceiveFile(request *ReceiveFile) (*ReceiveFileResponse, error) {
+	return service.ReceiveFileContext(context.Background(), request)
+}
+
+func (service *PMSInterfaceWebServiceSoap) ReceiveFileContext(ctx context.Context, request *ReceiveFile) (*ReceiveFileResponse, error) {
 	response := new(ReceiveFileResponse)
-	err := service.client.Call("http://localhost/PMSInterfaceWebService/ReceiveFile", request, response)
+	err := service.client.CallContext(ctx, "http://localhost/PMSInterfaceWebService/ReceiveFile", request, response)
 	if err != nil {
 		return nil, err
 	}
@@ -178,8 +199,12 @@ func (service *PMSInterfaceWebServiceSoap) ReceiveFile(request *ReceiveFile) (*R
 }
 
 func (service *PMSInterfaceWebServiceSoap) ReceiveFileByCrn(request *ReceiveFileByCrn) (*ReceiveFileByCrnResponse, error) {
+	return service.ReceiveFileByCrnContext(context.Background(), request)
+}
+
+func (service *PMSInterfaceWebServiceSoap) ReceiveFileByCrnContext(ctx context.Context, request *ReceiveFileByCrn) (*ReceiveFileByCrnResponse, error) {
 	response := new(ReceiveFileByCrnResponse)
-	err := service.client.Call("http://localhost/PMSInterfaceWebService/ReceiveFileByCrn", request, response)
+	err := service.client.CallContext(ctx, "http://localhost/PMSInterfaceWebService/ReceiveFileByCrn", request, response)
 	if err != nil {
 		return nil, err
 	}
@@ -188,8 +213,12 @@ func (service *PMSInterfaceWebServiceSoap) ReceiveFileByCrn(request *ReceiveFile
 }
 
 func (service *PMSInterfaceWebServiceSoap) RetrieveFile(request *RetrieveFile) (*RetrieveFileResponse, error) {
+	return service.RetrieveFileContext(context.Background(), request)
+}
+
+func (service *PMSInterfaceWebServiceSoap) RetrieveFileContext(ctx context.Context, request *RetrieveFile) (*RetrieveFileResponse, error) {
 	response := new(RetrieveFileResponse)
-	err := service.client.Call("http://localhost/PMSInterfaceWebService/RetrieveFile", request, response)
+	err := service.client.CallContext(ctx, "http://localhost/PMSInterfaceWebService/RetrieveFile", request, response)
 	if err != nil {
 		return nil, err
 	}
@@ -283,6 +312,7 @@ type SOAPClient struct {
 	tlsCfg  *tls.Config
 	auth    *BasicAuth
 	headers []interface{}
+	backend *resilience.Backend // optional circuit breaker for calls
 }
 
 // **********
@@ -397,7 +427,27 @@ func (s *SOAPClient) AddHeader(header interface{}) {
 	s.headers = append(s.headers, header)
 }
 
+// SetBackend sets the circuit breaker used for calls made by this client.
+// Calls are not retried, as SOAP actions may not be idempotent.
+func (s *SOAPClient) SetBackend(backend *resilience.Backend) {
+	s.backend = backend
+}
+
 func (s *SOAPClient) Call(soapAction string, request, response interface{}) error {
+	return s.CallContext(context.Background(), soapAction, request, response)
+}
+
+// CallContext calls the SOAP action, which is abandoned if the context is cancelled
+func (s *SOAPClient) CallContext(ctx context.Context, soapAction string, request, response interface{}) error {
+	if s.backend == nil {
+		return s.call(ctx, soapAction, request, response)
+	}
+	return s.backend.Do(ctx, false, func(ctx context.Context) error {
+		return s.call(ctx, soapAction, request, response)
+	})
+}
+
+func (s *SOAPClient) call(ctx context.Context, soapAction string, request, response interface{}) error {
 	envelope := SOAPEnvelope{}
 
 	if s.headers != nil && len(s.headers) > 0 {
@@ -419,7 +469,7 @@ func (s *SOAPClient) Call(soapAction string, request, response interface{}) erro
 	if err := encoder.Flush(); err != nil {
 		return err
 	}
-	req, err := http.NewRequest("POST", s.url, buffer)
+	req, err := http.NewRequestWithContext(ctx, "POST", s.url, buffer)
 	if err != nil {
 		return err
 	}
@@ -448,19 +498,21 @@ func (s *SOAPClient) Call(soapAction string, request, response interface{}) erro
 	if err != nil {
 		return err
 	}
-	if len(rawbody) == 0 {
+	if len(rawbody) == 0 && res.StatusCode < 300 {
 		return nil
 	}
 	respEnvelope := new(SOAPEnvelope)
 	respEnvelope.Body = SOAPBody{Content: response}
 	err = xml.Unmarshal(rawbody, respEnvelope)
-	if err != nil {
-		return err
-	}
 	fault := respEnvelope.Body.Fault
 	if fault != nil {
-		return fault
+		return resilience.Permanent(fault) // the service is responding, so do not trip the circuit breaker
 	}
-
-	return nil
+	if res.StatusCode >= 500 {
+		return fmt.Errorf("soap: server error: %s", res.Status)
+	}
+	if res.StatusCode >= 300 {
+		return resilience.Permanent(fmt.Errorf("soap: request rejected: %s", res.Status))
+	}
+	return err
 }
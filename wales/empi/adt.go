@@ -0,0 +1,205 @@
+package empi
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/hl7"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// adtEvents are the ADT trigger events that are parsed as patient events
+var adtEvents = map[string]string{
+	"A01": "admit",
+	"A03": "discharge",
+	"A08": "update patient information",
+	"A28": "add person information",
+	"A31": "update person information",
+	"A40": "merge patient",
+}
+
+// fieldTypes are the data types of the fields mapped to a patient, so that they can be converted from ER7 to the
+// XML encoding parsed for responses from the EMPI. Other fields are converted as text.
+var fieldTypes = map[string]string{
+	"PID.3": "CX", "PID.5": "XPN", "PID.7": "TS", "PID.9": "XPN", "PID.10": "CE", "PID.11": "XAD",
+	"PID.13": "XTN", "PID.14": "XTN", "PID.15": "CE", "PID.16": "CE", "PID.29": "TS",
+	"PD1.3": "XON", "PD1.4": "XCN",
+	"NK1.2": "XPN", "NK1.3": "CE", "NK1.4": "XAD", "NK1.5": "XTN", "NK1.6": "XTN", "NK1.7": "CE",
+	"MRG.1": "CX",
+}
+
+// componentTypes are the data types of the components with subcomponents that are mapped to a patient
+var componentTypes = map[string]string{
+	"CX.4": "HD", "XPN.1": "FN", "XPN.12": "TS", "XPN.13": "TS", "XAD.1": "SAD",
+}
+
+// telecomUses are descriptions of telecommunication use codes (XTN.2, HL7 table 0201), used to describe
+// telephone numbers as the EMPI does
+var telecomUses = map[string]string{
+	"PRN": "Home", "WPN": "Work", "ORN": "Other", "VHN": "Holiday", "EMR": "Emergency", "NET": "Email",
+}
+
+// ParseEvent parses a HL7 v2 ADT message, such as from a patient administration system or EMPI feed, as a patient
+// event, using the same mapping of patient demographics as for responses from the EMPI.
+func ParseEvent(msg *hl7.Message) (*apiv1.PatientEvent, error) {
+	msgType, event := msg.Type()
+	if msgType != "ADT" {
+		return nil, status.Errorf(codes.Unimplemented, "unsupported message type: %s", msgType)
+	}
+	if _, ok := adtEvents[event]; !ok {
+		return nil, status.Errorf(codes.Unimplemented, "unsupported ADT event: %s", event)
+	}
+	b, err := toXML(msg, "PID", "PD1", "NK1", "MRG")
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid message: %s", err)
+	}
+	var qr struct {
+		queryResponse
+		MRG []struct {
+			MRG1 []struct {
+				CX1 struct {
+					Text string `xml:",chardata"`
+				} `xml:"CX.1"`
+				CX4 struct {
+					HD1 struct {
+						Text string `xml:",chardata"`
+					} `xml:"HD.1"`
+				} `xml:"CX.4"`
+			} `xml:"MRG.1"`
+		} `xml:"MRG"`
+	}
+	if err := xml.Unmarshal(b, &qr); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid message: %s", err)
+	}
+	pt, err := qr.toPatient()
+	if err != nil {
+		return nil, err
+	}
+	if pt == nil || len(pt.GetIdentifiers()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "message %s has no identified patient", msg.ControlID())
+	}
+	e := &apiv1.PatientEvent{
+		EventType:        event,
+		Source:           msg.Source(),
+		MessageControlId: msg.ControlID(),
+		Received:         ptypes.TimestampNow(),
+		Patient:          pt,
+	}
+	for _, mrg := range qr.MRG {
+		for _, id := range mrg.MRG1 {
+			if id := toIdentifier(id.CX4.HD1.Text, id.CX1.Text); id != nil {
+				e.Merged = append(e.Merged, id)
+			}
+		}
+	}
+	if event == "A40" && len(e.Merged) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "merge %s has no merged patient identifier (MRG-1)", msg.ControlID())
+	}
+	return e, nil
+}
+
+// toXML converts the segments specified of a message to the XML encoding of HL7 v2
+func toXML(msg *hl7.Message, segments ...string) ([]byte, error) {
+	d := msg.Delimiters
+	var buf bytes.Buffer
+	buf.WriteString("<ADT>")
+	for _, s := range msg.Segments {
+		if !contains(segments, s.Name()) {
+			continue
+		}
+		buf.WriteString("<" + s.Name() + ">")
+		for i := 1; i < len(s); i++ {
+			name := s.Name() + "." + strconv.Itoa(i)
+			for _, rep := range strings.Split(s[i], string(d.Repetition)) {
+				if isEmpty(rep) {
+					continue
+				}
+				dataType, ok := fieldTypes[name]
+				if !ok {
+					if err := writeElement(&buf, name, d.Decode(rep)); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				longName := ""
+				if dataType == "XTN" {
+					longName = telecomUses[d.Decode(component(rep, d.Component, 2))]
+					if d.Decode(component(rep, d.Component, 3)) == "CP" {
+						longName = "Mobile"
+					}
+				}
+				buf.WriteString(startElement(name, longName))
+				for j, c := range strings.Split(rep, string(d.Component)) {
+					if isEmpty(c) {
+						continue
+					}
+					cname := dataType + "." + strconv.Itoa(j+1)
+					subType, ok := componentTypes[cname]
+					if !ok {
+						if err := writeElement(&buf, cname, d.Decode(c)); err != nil {
+							return nil, err
+						}
+						continue
+					}
+					buf.WriteString("<" + cname + ">")
+					for k, sc := range strings.Split(c, string(d.Subcomponent)) {
+						if !isEmpty(sc) {
+							if err := writeElement(&buf, subType+"."+strconv.Itoa(k+1), d.Decode(sc)); err != nil {
+								return nil, err
+							}
+						}
+					}
+					buf.WriteString("</" + cname + ">")
+				}
+				buf.WriteString("</" + name + ">")
+			}
+		}
+		buf.WriteString("</" + s.Name() + ">")
+	}
+	buf.WriteString("</ADT>")
+	return buf.Bytes(), nil
+}
+
+func startElement(name string, longName string) string {
+	if longName == "" {
+		return "<" + name + ">"
+	}
+	return "<" + name + " LongName=\"" + longName + "\">"
+}
+
+func writeElement(buf *bytes.Buffer, name string, value string) error {
+	buf.WriteString("<" + name + ">")
+	if err := xml.EscapeText(buf, []byte(value)); err != nil {
+		return err
+	}
+	buf.WriteString("</" + name + ">")
+	return nil
+}
+
+// component returns the (still encoded) component specified of a field, numbered from one
+func component(field string, sep byte, n int) string {
+	components := strings.Split(field, string(sep))
+	if n > len(components) {
+		return ""
+	}
+	return components[n-1]
+}
+
+// isEmpty returns whether a value is empty, including the explicit null value ("")
+func isEmpty(s string) bool {
+	return s == "" || s == `""`
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package empi
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/hl7"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func readMessage(t *testing.T, filename string) *hl7.Message {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := hl7.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestParseEvent(t *testing.T) {
+	e, err := ParseEvent(readMessage(t, "testdata/adt/a08.hl7"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt := e.GetPatient()
+	if e.GetEventType() != "A08" || e.GetSource() != "EMPI|NHSWALES" || e.GetMessageControlId() != "MSG00001" {
+		t.Fatalf("incorrect event: %v", e)
+	}
+	if pt.GetLastname() != "TESTPATIENT" || pt.GetFirstnames() != "MARY ELIZABETH" || pt.GetTitle() != "MRS" || pt.GetGender() != apiv1.Gender_FEMALE || pt.GetBirthDate() == nil {
+		t.Fatalf("incorrect patient: %v", pt)
+	}
+	if ids, ok := pt.GetIdentifiersForSystem(identifiers.CardiffAndValeCRN); !ok || ids[0].GetValue() != "X999123" {
+		t.Fatalf("incorrect identifiers: %v", pt.GetIdentifiers())
+	}
+	if len(pt.GetNames()) != 3 || pt.GetNames()[1].GetUse() != apiv1.HumanName_OLD || pt.GetNames()[1].GetSuffices()[0] != "JNR" || pt.GetNames()[2].GetUse() != apiv1.HumanName_NICKNAME {
+		t.Fatalf("incorrect names: %v", pt.GetNames())
+	}
+	if pt.GetAddresses()[0].GetPostcode() != "CF99 1AA" || pt.GetSurgery() != "W99999" || pt.GetGeneralPractitioner() != "G9999998" {
+		t.Fatalf("incorrect address or general practice: %v", pt)
+	}
+	if len(pt.GetTelephones()) != 2 || pt.GetTelephones()[0].GetDescription() != "Home" || pt.GetTelephones()[1].GetDescription() != "Mobile" || pt.GetEmails()[0] != "mary@example.com" {
+		t.Fatalf("incorrect telephones or emails: %v %v", pt.GetTelephones(), pt.GetEmails())
+	}
+	if pt.GetEthnicGroup().GetValue() != "A" || pt.GetLanguage().GetValue() != "cy" || pt.GetMaritalStatus().GetValue() != "M" || pt.GetNhsNumberVerification().GetValue() != "01" {
+		t.Fatalf("incorrect demographics: %v", pt)
+	}
+	if len(pt.GetContacts()) != 1 || pt.GetContacts()[0].GetRelationship().GetValue() != "SPO" || pt.GetContacts()[0].GetTelephones()[0].GetDescription() != "Mobile" {
+		t.Fatalf("incorrect contacts: %v", pt.GetContacts())
+	}
+
+	e, err = ParseEvent(readMessage(t, "testdata/adt/a40.hl7"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.GetMerged()) != 1 || e.GetMerged()[0].GetSystem() != identifiers.CardiffAndValeCRN || e.GetMerged()[0].GetValue() != "X999456" {
+		t.Fatalf("incorrect merged identifiers: %v", e.GetMerged())
+	}
+
+	msg := readMessage(t, "testdata/adt/a40.hl7")
+	msg.Segments = msg.Segments[:3] // without MRG
+	if _, err := ParseEvent(msg); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid merge, got: %v", err)
+	}
+	msg.Segments[0][9] = "ADT^A60"
+	if _, err := ParseEvent(msg); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected unsupported event, got: %v", err)
+	}
+}
+
+func TestRefreshAndInvalidate(t *testing.T) {
+	app := &App{}
+	app.Reconfigure("", "T", 1, time.Hour)
+	e, err := ParseEvent(readMessage(t, "testdata/adt/a08.hl7"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.Refresh(e.GetPatient())
+	if app.ItemCount() != 2 {
+		t.Fatalf("expected patient cached by NHS number and CRN, got %d items", app.ItemCount())
+	}
+	pt, err := app.GetEMPIRequest(context.Background(), &apiv1.Identifier{System: identifiers.NHSNumber, Value: "9990000123"})
+	if err != nil || pt.GetLastname() != "TESTPATIENT" {
+		t.Fatalf("refreshed patient not served from cache: %v %v", pt, err)
+	}
+	app.Invalidate(&apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "X999123"})
+	if app.ItemCount() != 1 {
+		t.Fatalf("expected one cached item after invalidation, got %d", app.ItemCount())
+	}
+}
@@ -3,6 +3,12 @@ package empi
 import (
 	"context"
 	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/wardle/concierge/apiv1"
 	"github.com/wardle/concierge/identifiers"
@@ -22,172 +28,253 @@ func init() {
 			return fmt.Errorf("expected namespace: %s. got: %s. error:%w", empiNamespaceURI, empiID.System, identifiers.ErrNoMapper)
 		}
 		auth := lookupFromEmpiOrgCode(empiID.Value)
-		if auth == AuthorityUnknown {
+		if auth.ToODSIdentifier().GetValue() == "" {
 			return fmt.Errorf("unable to map %s|%s to namespace %s", empiID.System, empiID.Value, identifiers.ODSSiteCode)
 		}
 		return f(auth.ToODSIdentifier())
 	})
+	if err := RegisterAuthorities(DefaultAuthorities); err != nil {
+		panic(err)
+	}
 }
 
-// Authority represents the different authorities that issue identifiers
-// These ultimately represent identifiers within the "system" https://fhir.nhs.uk/Id/ods-organization-code
-// These are currently hard-coded, but this could easily be switched to a more modular extension registration
-// approach based on runtime configuration
-type Authority int
+// dateLayout is the layout of the dates from and to which authorities are in effect
+const dateLayout = "2006-01-02"
 
-// List of authority codes for different organisations in Wales
-const (
-	AuthorityUnknown = iota
-	AuthorityNHS
-	AuthorityEMPI
-	AuthorityABH
-	AuthorityABMU
-	AuthorityBCUCentral
-	AuthorityBCUMaelor
-	AuthorityBCUWest
-	AuthorityCT
-	AuthorityCV
-	AuthorityHD
-	AuthorityPowys
-	lastAuthority
+// Authority is an organisation that issues identifiers known to the EMPI, such as a health board issuing
+// case record numbers. Authorities may be in effect only for a period, such as when health boards are
+// reorganised, and so the same EMPI organisation code or URI may be used by different authorities over time.
+// Authorities are loaded from configuration, e.g.
+//   empi-authorities:
+//     - name: Cardiff and Vale
+//       empi-org-code: "140"
+//       uri: https://fhir.cardiff.wales.nhs.uk/Id/pas-identifier
+//       ods-code: RWMBV
+//       type-code: PI
+//       pattern: '^[A-Z]\d{6}[A-Z]?$'
+//       valid-from: 2009-10-01
+type Authority struct {
+	Name        string
+	EmpiOrgCode string `mapstructure:"empi-org-code"` // internal (proprietary) code given to the authority by the EMPI
+	URI         string // system of identifiers issued by the authority, if known
+	ODSCode     string `mapstructure:"ods-code"`  // ODS organisation code
+	TypeCode    string `mapstructure:"type-code"` // HL7 identifier type code (table 0203), e.g. NH or PI
+	Pattern     string // regular expression that identifiers issued by the authority must match, if any
+	ValidFrom   string `mapstructure:"valid-from"` // date (YYYY-MM-DD) from which the authority is in effect, if limited
+	ValidTo     string `mapstructure:"valid-to"`   // date (YYYY-MM-DD) from which the authority is no longer in effect, if limited
+
+	pattern  *regexp.Regexp
+	from, to time.Time
+}
+
+// DefaultAuthorities are the authorities used unless configured otherwise
+var DefaultAuthorities = []Authority{
+	{Name: "NHS number", EmpiOrgCode: "NHS", URI: identifiers.NHSNumber, ODSCode: "NHS", TypeCode: "NH"},
+	// internal EMPI identifier - this authority provides only ephemeral identifiers. type unknown - TODO: check this
+	{Name: "EMPI", EmpiOrgCode: "100", URI: identifiers.CymruEmpiURI, TypeCode: "PE"},
+	{Name: "Aneurin Bevan", EmpiOrgCode: "139", URI: identifiers.AneurinBevanCRN, ODSCode: "RVFAR", TypeCode: "PI"},
+	// Bridgend moved from Abertawe Bro Morgannwg to Cwm Taf Morgannwg in April 2019
+	{Name: "Abertawe Bro Morgannwg", EmpiOrgCode: "108", URI: identifiers.SwanseaBayCRN, ODSCode: "RYMC7", TypeCode: "PI", ValidTo: "2019-04-01"},
+	{Name: "Swansea Bay", EmpiOrgCode: "108", URI: identifiers.SwanseaBayCRN, ODSCode: "RYMC7", TypeCode: "PI", ValidFrom: "2019-04-01"},
+	{Name: "Betsi Cadwaladr Central", EmpiOrgCode: "109", URI: identifiers.BetsiCentralCRN, TypeCode: "PI"},
+	{Name: "Betsi Cadwaladr Maelor", EmpiOrgCode: "110", URI: identifiers.BetsiMaelorCRN, TypeCode: "PI"},
+	{Name: "Betsi Cadwaladr West", EmpiOrgCode: "111", URI: identifiers.BetsiWestCRN, TypeCode: "PI"},
+	{Name: "Cwm Taf", EmpiOrgCode: "126", URI: identifiers.CwmTafCRN, ODSCode: "RYLB3", TypeCode: "PI", ValidTo: "2019-04-01"},
+	{Name: "Cwm Taf Morgannwg", EmpiOrgCode: "126", URI: identifiers.CwmTafCRN, ODSCode: "RYLB3", TypeCode: "PI", ValidFrom: "2019-04-01"},
+	{Name: "Cardiff and Vale", EmpiOrgCode: "140", URI: identifiers.CardiffAndValeCRN, ODSCode: "RWMBV", TypeCode: "PI"},
+	{Name: "Hywel Dda", EmpiOrgCode: "149", URI: identifiers.HywelDdaCRN, TypeCode: "PI"},
+	{Name: "Powys", EmpiOrgCode: "170", TypeCode: "PI"}, // don't think powys has a PAS!
+}
+
+// authorityIndex indexes authorities by a code, giving the authorities using that code, most recent first
+type authorityIndex map[string][]*Authority
+
+var (
+	authoritiesMu sync.RWMutex
+	empiOrgLookup authorityIndex // authorities by EMPI organisation code
+	uriLookup     authorityIndex // authorities by URI
 )
 
-// ValidateIdentifier applies the authorities' formatting rules to validate and sanitise
-// the identifier provided.
-// Returns whether the identifier is valid and a sanitised version of that identifier.
-func (a Authority) ValidateIdentifier(id string) (bool, string) {
-	switch a {
-	case AuthorityNHS:
-		return ValidateNHSNumber(id)
+// RegisterAuthorities replaces the authorities known to the EMPI. Authorities using the same EMPI organisation
+// code, or the same URI, must be in effect for periods that do not overlap.
+func RegisterAuthorities(authorities []Authority) error {
+	byEmpiOrg, byURI := make(authorityIndex), make(authorityIndex)
+	for i := range authorities {
+		a := authorities[i]
+		if err := a.parse(); err != nil {
+			return err
+		}
+		if err := byEmpiOrg.add(a.EmpiOrgCode, &a); err != nil {
+			return err
+		}
+		if a.URI != "" {
+			if err := byURI.add(a.URI, &a); err != nil {
+				return err
+			}
+		}
 	}
-	return true, id
+	authoritiesMu.Lock()
+	defer authoritiesMu.Unlock()
+	empiOrgLookup, uriLookup = byEmpiOrg, byURI
+	log.Printf("empi: registered %d authorities", len(authorities))
+	return nil
 }
 
-func (a Authority) empiOrganisationCode() string {
-	if a > lastAuthority {
-		return ""
+// parse validates an authority, parsing its pattern and period of effect
+func (a *Authority) parse() error {
+	if a.EmpiOrgCode == "" {
+		return fmt.Errorf("empi: authority '%s' has no EMPI organisation code", a.Name)
+	}
+	var err error
+	if a.Pattern != "" {
+		if a.pattern, err = regexp.Compile(a.Pattern); err != nil {
+			return fmt.Errorf("empi: authority '%s' has invalid pattern: %w", a.Name, err)
+		}
+	}
+	if a.ValidFrom != "" {
+		if a.from, err = time.Parse(dateLayout, a.ValidFrom); err != nil {
+			return fmt.Errorf("empi: authority '%s' has invalid valid-from date: %w", a.Name, err)
+		}
 	}
-	return empiOrgCodes[a]
+	if a.ValidTo != "" {
+		if a.to, err = time.Parse(dateLayout, a.ValidTo); err != nil {
+			return fmt.Errorf("empi: authority '%s' has invalid valid-to date: %w", a.Name, err)
+		}
+		if !a.to.After(a.from) {
+			return fmt.Errorf("empi: authority '%s' is never in effect: %s to %s", a.Name, a.ValidFrom, a.ValidTo)
+		}
+	}
+	return nil
 }
 
-func (a Authority) odsHospitalCode() string {
-	if a > lastAuthority {
-		return ""
+// add adds an authority using the code specified, checking that it is not in effect at the same time as another
+func (idx authorityIndex) add(code string, a *Authority) error {
+	for _, other := range idx[code] {
+		if a.overlaps(other) {
+			return fmt.Errorf("empi: authorities '%s' and '%s' both use '%s' at the same time", other.Name, a.Name, code)
+		}
 	}
-	return hospitalCodes[a]
+	as := append(idx[code], a)
+	sort.Slice(as, func(i, j int) bool { return as[i].from.After(as[j].from) })
+	idx[code] = as
+	return nil
 }
-func (a Authority) typeCode() string {
-	if a > lastAuthority {
-		return ""
+
+func (a *Authority) overlaps(other *Authority) bool {
+	return (a.to.IsZero() || other.from.Before(a.to)) && (other.to.IsZero() || a.from.Before(other.to))
+}
+
+// EffectiveAt returns whether the authority is in effect at the time specified
+func (a *Authority) EffectiveAt(t time.Time) bool {
+	return a != nil && !t.Before(a.from) && (a.to.IsZero() || t.Before(a.to))
+}
+
+// lookup returns the authority using the code specified that is in effect at the time specified or, if none,
+// the authority that was most recently in effect, or nil if the code is unknown
+func (idx authorityIndex) lookup(code string, t time.Time) *Authority {
+	as := idx[code]
+	for _, a := range as {
+		if a.EffectiveAt(t) {
+			return a
+		}
+	}
+	for _, a := range as {
+		if a.from.Before(t) {
+			return a
+		}
 	}
-	return authorityTypes[a]
+	return nil
 }
 
-// ToODSIdentifier converts the authority into a proper Identifier based on ODS code
-// TODO: once using the new ODS microservice, check that it shouldn't be ODS site code
-// TODO: plan migration to new ODS coding system (ANANA)
-func (a Authority) ToODSIdentifier() *apiv1.Identifier {
-	return &apiv1.Identifier{
-		System: identifiers.ODSCode,
-		Value:  a.odsHospitalCode(),
+func lookupFromEmpiOrgCode(code string) *Authority {
+	authoritiesMu.RLock()
+	defer authoritiesMu.RUnlock()
+	return empiOrgLookup.lookup(code, time.Now())
+}
+
+func lookupFromURI(uri string) *Authority {
+	authoritiesMu.RLock()
+	defer authoritiesMu.RUnlock()
+	return uriLookup.lookup(uri, time.Now())
+}
+
+// Systems returns the systems of the patient identifiers issued by the registered authorities, such as NHS numbers
+// and case record numbers (identifier types NH and PI), in order
+func Systems() []string {
+	authoritiesMu.RLock()
+	defer authoritiesMu.RUnlock()
+	result := make([]string, 0, len(uriLookup))
+	for uri, as := range uriLookup {
+		for _, a := range as {
+			if a.TypeCode == "NH" || a.TypeCode == "PI" {
+				result = append(result, uri)
+				break
+			}
+		}
 	}
+	sort.Strings(result)
+	return result
 }
 
-// ToURI returns the URI for this authority
-func (a Authority) ToURI() string {
-	if a > lastAuthority {
-		return ""
+// String returns the name of the authority
+func (a *Authority) String() string {
+	if a == nil {
+		return "unknown"
 	}
-	return uris[a]
-}
-
-// empiOrgCodes are the internal (proprietary) codes given to authorities within the Welsh EMPI
-var empiOrgCodes = [...]string{
-	"",
-	"NHS", // NHS number
-	"100", // internal EMPI identifier - this authority provides on ephemeral identifiers
-	"139", // Aneurin Bevan (AB)
-	"108", // Abertawe Bro Morgannwg (ABM)
-	"109", // Betsi Cadwalader Central (BCUCentral)
-	"110", // BCUMaelor
-	"111", // BCUWest
-	"126", // Cwm Taf (CT)
-	"140", // Cardiff and Vale (CAV)
-	"149", // Hywel Dda (HD)
-	"170", // Powys
-}
-
-var uris = [...]string{
-	"",
-	identifiers.NHSNumber,
-	identifiers.CymruEmpiURI,
-	identifiers.AneurinBevanCRN,
-	identifiers.SwanseaBayCRN,
-	identifiers.BetsiCentralCRN,
-	identifiers.BetsiMaelorCRN,
-	identifiers.BetsiWestCRN,
-	identifiers.CwmTafCRN,
-	identifiers.CardiffAndValeCRN,
-	identifiers.HywelDdaCRN,
-	"", // don't thnk powys has a PAS!
-}
-
-// hospitalCodes provide ODS organisation codes
-var hospitalCodes = [...]string{
-	"",
-	"NHS",
-	"",
-	"RVFAR", // Royal Gwent
-	"RYMC7", // Morriston
-	"",
-	"",
-	"",
-	"RYLB3", // Prince Charles Hospital
-	"RWMBV", // UHW
-	"",
-	"",
-}
-var empiOrgLookup = make(map[string]Authority)
-var hospitalLookup = make(map[string]Authority)
-var uriLookup = make(map[string]Authority)
+	return a.Name
+}
 
-func init() {
-	for i, code := range empiOrgCodes {
-		empiOrgLookup[code] = Authority(i)
+// ValidateIdentifier applies the authorities' formatting rules to validate and sanitise
+// the identifier provided.
+// Returns whether the identifier is valid and a sanitised version of that identifier.
+func (a *Authority) ValidateIdentifier(id string) (bool, string) {
+	if a == nil {
+		return true, id
 	}
-	for i, code := range hospitalCodes {
-		hospitalLookup[code] = Authority(i)
+	if a.URI == identifiers.NHSNumber {
+		var valid bool
+		if valid, id = ValidateNHSNumber(id); !valid {
+			return false, id
+		}
+	}
+	if a.pattern != nil && !a.pattern.MatchString(strings.ToUpper(id)) {
+		return false, id
 	}
-	for i, uri := range uris {
-		uriLookup[uri] = Authority(i)
+	return true, id
+}
+
+func (a *Authority) empiOrganisationCode() string {
+	if a == nil {
+		return ""
 	}
+	return a.EmpiOrgCode
 }
 
-var authorityTypes = [...]string{
-	"",
-	"NH",
-	"PE", // unknown - TODO: check this
-	"PI",
-	"PI",
-	"PI",
-	"PI",
-	"PI",
-	"PI",
-	"PI",
-	"PI",
-	"PI",
+func (a *Authority) typeCode() string {
+	if a == nil {
+		return ""
+	}
+	return a.TypeCode
 }
 
-func lookupFromEmpiOrgCode(identifier string) Authority {
-	if a, ok := empiOrgLookup[identifier]; ok {
-		return a
+// ToODSIdentifier converts the authority into a proper Identifier based on ODS code
+// TODO: once using the new ODS microservice, check that it shouldn't be ODS site code
+// TODO: plan migration to new ODS coding system (ANANA)
+func (a *Authority) ToODSIdentifier() *apiv1.Identifier {
+	code := ""
+	if a != nil {
+		code = a.ODSCode
+	}
+	return &apiv1.Identifier{
+		System: identifiers.ODSCode,
+		Value:  code,
 	}
-	return AuthorityUnknown
 }
 
-func lookupFromOdsHospital(identifier string) Authority {
-	if a, ok := hospitalLookup[identifier]; ok {
-		return a
+// ToURI returns the URI for this authority
+func (a *Authority) ToURI() string {
+	if a == nil {
+		return ""
 	}
-	return AuthorityUnknown
+	return a.URI
 }
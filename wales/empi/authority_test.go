@@ -0,0 +1,72 @@
+package empi
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wardle/concierge/identifiers"
+)
+
+func TestAuthorities(t *testing.T) {
+	defer RegisterAuthorities(DefaultAuthorities)
+	if a := lookupFromEmpiOrgCode("140"); a.ToURI() != identifiers.CardiffAndValeCRN || a.ToODSIdentifier().GetValue() != "RWMBV" {
+		t.Fatalf("incorrect default authority for 140: %+v", a)
+	}
+	if a := lookupFromURI(identifiers.SwanseaBayCRN); a.String() != "Swansea Bay" {
+		t.Fatalf("expected authority now in effect, got: %s", a)
+	}
+	if a := empiOrgLookup.lookup("108", time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)); a.String() != "Abertawe Bro Morgannwg" {
+		t.Fatalf("expected authority in effect in 2015, got: %s", a)
+	}
+	if lookupFromEmpiOrgCode("999") != nil || lookupFromURI("") != nil {
+		t.Fatalf("unknown authorities found")
+	}
+	systems := strings.Join(Systems(), " ")
+	if !strings.Contains(systems, identifiers.NHSNumber) || !strings.Contains(systems, identifiers.HywelDdaCRN) || strings.Contains(systems, identifiers.CymruEmpiURI) {
+		t.Fatalf("incorrect systems of patient identifiers: %s", systems)
+	}
+
+	err := RegisterAuthorities([]Authority{
+		{Name: "Old", EmpiOrgCode: "200", URI: "https://test/old", ValidTo: "2020-01-01"},
+		{Name: "New", EmpiOrgCode: "200", URI: "https://test/new", ValidFrom: "2019-06-01"},
+	})
+	if err == nil {
+		t.Fatalf("authorities using the same code at the same time not rejected")
+	}
+	for _, a := range []Authority{
+		{Name: "No code"},
+		{Name: "Bad pattern", EmpiOrgCode: "200", Pattern: "["},
+		{Name: "Bad date", EmpiOrgCode: "200", ValidFrom: "01/01/2020"},
+		{Name: "Never", EmpiOrgCode: "200", ValidFrom: "2020-01-01", ValidTo: "2020-01-01"},
+	} {
+		if err := RegisterAuthorities([]Authority{a}); err == nil {
+			t.Errorf("invalid authority '%s' not rejected", a.Name)
+		}
+	}
+
+	err = RegisterAuthorities([]Authority{
+		{Name: "Expired", EmpiOrgCode: "200", URI: "https://test/crn", Pattern: `^X\d{6}$`, ValidTo: "2019-04-01"},
+		{Name: "Future", EmpiOrgCode: "201", URI: "https://test/future", ValidFrom: "2999-01-01"},
+		{Name: "NHS", EmpiOrgCode: "NHS", URI: identifiers.NHSNumber},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := lookupFromURI("https://test/crn")
+	if a.String() != "Expired" || a.EffectiveAt(time.Now()) {
+		t.Fatalf("expected authority no longer in effect, got: %s", a)
+	}
+	if lookupFromURI("https://test/future") != nil {
+		t.Fatalf("authority not yet in effect found")
+	}
+	if valid, _ := a.ValidateIdentifier("x123456"); !valid {
+		t.Errorf("valid identifier reported as invalid")
+	}
+	if valid, _ := a.ValidateIdentifier("X12345"); valid {
+		t.Errorf("invalid identifier reported as valid")
+	}
+	if valid, _ := lookupFromEmpiOrgCode("NHS").ValidateIdentifier("1234567890"); valid {
+		t.Errorf("invalid NHS number reported as valid")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/xml"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -13,6 +14,8 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -20,23 +23,103 @@ import (
 
 	"net/url"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
 	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/cache"
+	"github.com/wardle/concierge/fake"
 	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/patients"
+	"github.com/wardle/concierge/resilience"
 	"github.com/wardle/concierge/server"
-
-	"github.com/patrickmn/go-cache"
 )
 
+// BackendName is the name of the EMPI backend for retries and circuit breaking, and for health checks
+const BackendName = "empi"
+
 // App represents the EMPI application
 type App struct {
 	EndpointURL    string       // override URL for the specified endpoint
 	ProcessingID   string       // processing ID to use; their definitions are: P production, U testing, T development
 	Cache          *cache.Cache // may be nil if not caching
+	CacheStore     cache.Store  // store used for the cache; an in-memory store is used if nil
+	CacheKey       []byte       // key to encrypt cached patients, or nil
 	Fake           bool
+	FakeData       *fake.Data // synthetic patients served in fake mode; default data are used if nil
 	TimeoutSeconds int
+
+	mu            sync.RWMutex      // protects configuration when reconfigured at runtime
+	cacheDuration time.Duration     // duration used to create cache when reconfigured
+	lastKnown     *server.LastKnown // last known patients, served if the EMPI is unavailable; nil if turned off
+}
+
+// SetStaleMaxAge sets how long the last known result for a patient may be served, flagged as stale, when the
+// EMPI is unavailable. A duration of zero turns off serving stale results.
+func (app *App) SetStaleMaxAge(maxAge time.Duration) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	switch {
+	case maxAge == 0:
+		app.lastKnown = nil
+	case app.lastKnown == nil || app.lastKnown.MaxAge() != maxAge:
+		if app.CacheStore == nil {
+			app.CacheStore = cache.NewMemoryStore()
+		}
+		lk, err := server.NewLastKnown(app.CacheStore, "empi-stale/", maxAge, app.CacheKey)
+		if err != nil {
+			log.Printf("empi: failed to create store of last known patients: %s", err)
+		}
+		app.lastKnown = lk
+	}
+	log.Printf("empi: serving stale results when unavailable for up to: %v", maxAge)
+}
+
+func (app *App) getLastKnown() *server.LastKnown {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+	return app.lastKnown
+}
+
+// Reconfigure updates the configuration of a running EMPI application.
+// The cache is replaced if the cache duration changes, with a duration of zero turning off caching.
+func (app *App) Reconfigure(endpointURL string, processingID string, timeoutSeconds int, cacheDuration time.Duration) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	app.EndpointURL = endpointURL
+	app.ProcessingID = processingID
+	app.TimeoutSeconds = timeoutSeconds
+	switch {
+	case cacheDuration == 0:
+		app.Cache = nil
+	case app.Cache == nil || app.cacheDuration != cacheDuration:
+		if app.CacheStore == nil {
+			app.CacheStore = cache.NewMemoryStore()
+		}
+		c, err := cache.New(app.CacheStore, "empi/", cacheDuration, app.CacheKey)
+		if err != nil {
+			log.Printf("empi: failed to create cache: %s", err)
+		}
+		app.Cache = c
+	}
+	app.cacheDuration = cacheDuration
+	log.Printf("empi: reconfigured: cache:%v timeout:%ds endpoint:%s", cacheDuration, timeoutSeconds, endpointURL)
+}
+
+// fakeData returns the synthetic data served in fake mode
+func (app *App) fakeData() *fake.Data {
+	if app.FakeData != nil {
+		return app.FakeData
+	}
+	return fake.Default()
+}
+
+// config returns a consistent snapshot of the current runtime configuration
+func (app *App) config() (endpointURL string, processingID string, timeoutSeconds int, c *cache.Cache) {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+	return app.EndpointURL, app.ProcessingID, app.TimeoutSeconds, app.Cache
 }
 
 // ResolveIdentifier provides an identifier/value resolution service
@@ -44,22 +127,54 @@ func (app *App) ResolveIdentifier(ctx context.Context, id *apiv1.Identifier) (pr
 	return app.GetEMPIRequest(ctx, id)
 }
 
+// GetPatient returns the patient with the identifier specified, such as a NHS number or a case record number
+// issued by a health board
+func (app *App) GetPatient(ctx context.Context, id *apiv1.Identifier) (*apiv1.Patient, error) {
+	return app.GetEMPIRequest(ctx, id)
+}
+
+// GetPatients returns the patients with the identifiers specified
+func (app *App) GetPatients(ctx context.Context, r *apiv1.PatientsRequest) (*apiv1.PatientsResponse, error) {
+	return patients.Lookup(ctx, r.GetIdentifiers(), app.GetPatient)
+}
+
+// RegisterServer registers this server
+func (app *App) RegisterServer(s *grpc.Server) {
+	if app.Fake {
+		log.Printf("empi: running in fake mode")
+	}
+	apiv1.RegisterPatientDirectoryServer(s, app)
+}
+
+// RegisterHTTPProxy registers this as a reverse HTTP proxy
+func (app *App) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	return apiv1.RegisterPatientDirectoryHandlerFromEndpoint(ctx, mux, endpoint, opts)
+}
+
 // Close closes any linked resources
-func (app *App) Close() {}
+func (app *App) Close() error { return nil }
+
+// CheckHealth reports whether the EMPI is available
+func (app *App) CheckHealth(ctx context.Context) error {
+	if app.Fake {
+		return nil
+	}
+	if state := resilience.Get(BackendName).State(); state == resilience.Open {
+		return fmt.Errorf("empi unavailable: circuit breaker %s", state)
+	}
+	return nil
+}
 
 // GetEMPIRequest fetches a patient matching the identifier specified
 func (app *App) GetEMPIRequest(ctx context.Context, req *apiv1.Identifier) (*apiv1.Patient, error) {
 	ucd := server.GetContextData(ctx)
-	authority, ok := uriLookup[req.System]
-	if !ok {
+	authority := lookupFromURI(req.System)
+	if authority == nil {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid authority: %s", req.System)
 	}
 	empiCode := authority.empiOrganisationCode()
-	log.Printf("empi: request from '%s|%s' for %s/%s - mapped to authority:%d (%s)", ucd.GetAuthenticatedUser().GetSystem(), ucd.GetAuthenticatedUser().GetValue(), req.System, req.Value, authority, empiCode)
+	log.Printf("empi: request from '%s|%s' for %s/%s - mapped to authority:%s (%s)", ucd.GetAuthenticatedUser().GetSystem(), ucd.GetAuthenticatedUser().GetValue(), req.System, req.Value, authority, empiCode)
 
-	if empiCode == "" {
-		return nil, status.Errorf(codes.InvalidArgument, "unsupported authority: %s (%d)", req.System, authority)
-	}
 	return app.GetInternalEMPIRequest(ctx, &apiv1.Identifier{
 		System: authority.empiOrganisationCode(),
 		Value:  req.Value,
@@ -76,29 +191,44 @@ func (app *App) GetInternalEMPIRequest(ctx context.Context, req *apiv1.Identifie
 		return pt, nil
 	}
 	authority := lookupFromEmpiOrgCode(req.System)
-	if authority == AuthorityUnknown {
+	if authority == nil {
 		log.Printf("empi: unsupported authority: %s", req.System)
 		return nil, status.Errorf(codes.InvalidArgument, "unsupported authority: %s", req.System)
 	}
+	if !authority.EffectiveAt(time.Now()) {
+		return nil, status.Errorf(codes.InvalidArgument, "authority %s (%s) is not in effect", req.System, authority)
+	}
 	var valid bool
 	if valid, req.Value = authority.ValidateIdentifier(req.Value); !valid {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid %s number: %s", req.System, req.Value)
 	}
 	if app.Fake {
 		log.Printf("empi: returning fake result for %s/%s", req.System, req.Value)
-		return performFake(authority, req.Value)
+		return performFake(app.fakeData(), app.FakeData == nil, authority, req.Value)
 	}
-	timeout := app.TimeoutSeconds
+	endpointURL, processingID, timeout, _ := app.config()
 	if timeout == 0 {
 		timeout = 1
 	}
-	ctx, cancelFunc := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
-	pt, err := performRequest(ctx, app.EndpointURL, app.ProcessingID, authority, req.Value)
-	cancelFunc()
+	err := resilience.Get(BackendName).Do(ctx, true, func(ctx context.Context) error {
+		ctx, cancelFunc := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancelFunc()
+		var err error
+		pt, err = performRequest(ctx, endpointURL, processingID, authority, req.Value)
+		return err
+	})
 	if err != nil {
 		if urlError, ok := err.(*url.Error); ok {
 			if urlError.Timeout() {
-				return nil, status.Errorf(codes.DeadlineExceeded, "NHS Wales' EMPI service did not respond within deadline (%d sec)", app.TimeoutSeconds)
+				err = status.Errorf(codes.DeadlineExceeded, "NHS Wales' EMPI service did not respond within deadline (%d sec)", timeout)
+			}
+		}
+		if lk := app.getLastKnown(); lk != nil && server.Unavailable(err) {
+			stale := new(apiv1.Patient)
+			if fetched, found := lk.Get(key, stale); found {
+				log.Printf("empi: serving stale result for %s/%s fetched %s: %s", req.System, req.Value, fetched.Format(time.RFC3339), err)
+				server.SetStale(ctx, fetched)
+				return stale, nil
 			}
 		}
 		return nil, err
@@ -107,83 +237,123 @@ func (app *App) GetInternalEMPIRequest(ctx context.Context, req *apiv1.Identifie
 		return nil, status.Errorf(codes.NotFound, "patient %s/%s not found", req.System, req.Value)
 	}
 	log.Printf("empi: response for %s: %s", req.Value, protojson.MarshalOptions{}.Format(pt))
+	app.setCache(key, pt)
+	if lk := app.getLastKnown(); lk != nil {
+		lk.Set(key, pt)
+	}
 	return pt, nil
 }
 
 func (app *App) getCache(key string) (*apiv1.Patient, bool) {
-	if app.Cache == nil {
+	_, _, _, c := app.config()
+	if c == nil {
 		return nil, false
 	}
-	if o, found := app.Cache.Get(key); found {
-		return o.(*apiv1.Patient), true
+	pt := new(apiv1.Patient)
+	found, err := c.Get(key, pt)
+	if err != nil {
+		log.Printf("empi: failed to read cache for %s: %s", key, err)
 	}
-	return nil, false
+	if !found {
+		return nil, false
+	}
+	return pt, true
 }
 
 func (app *App) setCache(key string, value *apiv1.Patient) {
-	if app.Cache == nil {
+	_, _, _, c := app.config()
+	if c == nil {
 		return
 	}
-	app.Cache.Set(key, value, cache.DefaultExpiration)
+	if err := c.Set(key, value); err != nil {
+		log.Printf("empi: failed to cache %s: %s", key, err)
+	}
 }
 
-func performFake(authority Authority, identifier string) (*apiv1.Patient, error) {
-	dob, err := ptypes.TimestampProto(time.Date(1960, 01, 01, 00, 00, 00, 0, time.UTC))
-	if err != nil {
-		return nil, err
+// ItemCount returns the number of patients currently cached
+func (app *App) ItemCount() int {
+	_, _, _, c := app.config()
+	if c == nil {
+		return 0
 	}
-	return &apiv1.Patient{
-		Lastname:   "DUMMY",
-		Firstnames: "ALBERT",
-		Title:      "DR",
-		Gender:     apiv1.Gender_MALE,
-		BirthDate:  dob,
-		//		Deceased:            &apiv1.Patient_DeceasedDate{DeceasedDate: dob},
-		Surgery:             "W95010",
-		GeneralPractitioner: "G9342400",
-		Identifiers: []*apiv1.Identifier{
-			{
-				System: authority.empiOrganisationCode(),
-				Value:  identifier,
-			},
-			{
-				System: "103",
-				Value:  "M1147907",
-			},
-			{
-				System: identifiers.CardiffAndValeCRN,
-				Value:  "X234567",
-			},
-			{
-				System: identifiers.NHSNumber,
-				Value:  "1111111111",
-			},
-		},
-
-		Addresses: []*apiv1.Address{
-			{
-				Address1: "59 Robins Hill",
-				Address2: "Brackla",
-				Address3: "Bridgend",
-				Postcode: "CF31 2PJ",
-				Country:  "WALES",
-			},
-		},
-		Telephones: []*apiv1.Telephone{
-			{
-				Number:      "02920 747747",
-				Description: "Home",
-			},
-			{
-				Number:      "02920 711711",
-				Description: "Mobile",
-			},
-		},
-		Emails: []string{"test@test.com", "wibble@test.com"},
-	}, nil
+	return c.ItemCount()
+}
+
+// Flush removes all patients from the cache, including last known patients
+func (app *App) Flush() {
+	_, _, _, c := app.config()
+	if c != nil {
+		c.Flush()
+	}
+	if lk := app.getLastKnown(); lk != nil {
+		lk.Flush()
+	}
+}
+
+// Invalidate removes any cached patient with the identifiers specified, including last known patients, such as
+// when a patient administration system reports that a patient has changed
+func (app *App) Invalidate(ids ...*apiv1.Identifier) {
+	_, _, _, c := app.config()
+	lk := app.getLastKnown()
+	for _, id := range ids {
+		key, ok := cacheKey(id)
+		if !ok {
+			continue
+		}
+		if c != nil {
+			if err := c.Delete(key); err != nil {
+				log.Printf("empi: failed to remove %s from cache: %s", key, err)
+			}
+		}
+		if lk != nil {
+			lk.Delete(key)
+		}
+	}
+}
+
+// Refresh caches the patient specified, such as one received from an EMPI feed, for each of its identifiers
+// issued by an authority known to the EMPI
+func (app *App) Refresh(pt *apiv1.Patient) {
+	lk := app.getLastKnown()
+	for _, id := range pt.GetIdentifiers() {
+		if key, ok := cacheKey(id); ok {
+			app.setCache(key, pt)
+			if lk != nil {
+				lk.Set(key, pt)
+			}
+		}
+	}
+}
+
+// cacheKey returns the key used to cache the patient with the identifier specified. This is hashed in the store if
+// the cache is encrypted, so that the store does not reveal the identifier.
+func cacheKey(id *apiv1.Identifier) (string, bool) {
+	authority := lookupFromURI(id.GetSystem())
+	if authority == nil {
+		return "", false
+	}
+	return authority.empiOrganisationCode() + "/" + id.GetValue(), true
+}
+
+// performFake returns the synthetic patient with the identifier issued by the authority specified. If using the
+// default data, a patient is returned for any identifier, as no other patients can be configured.
+func performFake(data *fake.Data, defaults bool, authority *Authority, identifier string) (*apiv1.Patient, error) {
+	system := authority.ToURI()
+	if system == "" {
+		system = authority.empiOrganisationCode()
+	}
+	id := &apiv1.Identifier{System: system, Value: identifier}
+	pt, found := data.Patient(id)
+	if !found && defaults {
+		return fake.AnyPatient(id), nil
+	}
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "patient %s/%s not found", authority.empiOrganisationCode(), identifier)
+	}
+	return pt, nil
 }
 
-func performRequest(context context.Context, endpointURL string, processingID string, authority Authority, identifier string) (*apiv1.Patient, error) {
+func performRequest(context context.Context, endpointURL string, processingID string, authority *Authority, identifier string) (*apiv1.Patient, error) {
 	start := time.Now()
 	data, err := NewIdentifierRequest(strings.ToUpper(identifier), authority, "221", "100", processingID)
 	if err != nil {
@@ -205,6 +375,9 @@ func performRequest(context context.Context, endpointURL string, processingID st
 		return nil, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return nil, fmt.Errorf("empi: server error: %s", resp.Status)
+	}
 	var e envelope
 	log.Printf("empi: response (%s): %v", time.Since(start), string(body))
 	err = xml.Unmarshal(body, &e)
@@ -231,7 +404,7 @@ type IdentifierRequest struct {
 // NewIdentifierRequest returns a correctly formatted XML request to search by an identifier, such as NHS number
 // sender : 221 (PatientCare)
 // receiver: 100 (NHS Wales EMPI)
-func NewIdentifierRequest(identifier string, authority Authority, sender string, receiver string, processingID string) ([]byte, error) {
+func NewIdentifierRequest(identifier string, authority *Authority, sender string, receiver string, processingID string) ([]byte, error) {
 	layout := "20060102150405" // YYYYMMDDHHMMSS
 	now := time.Now().Format(layout)
 	data := IdentifierRequest{
@@ -260,14 +433,19 @@ func NewIdentifierRequest(identifier string, authority Authority, sender string,
 
 // ToPatient creates a "Patient" from the XML returned from the EMPI service
 func (e *envelope) ToPatient() (*apiv1.Patient, error) {
+	return e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.toPatient()
+}
+
+// toPatient creates a "Patient" from the patient in a query response
+func (qr *queryResponse) toPatient() (*apiv1.Patient, error) {
 	pt := new(apiv1.Patient)
-	pt.Lastname = e.surname()
-	pt.Firstnames = e.firstnames()
+	pt.Lastname = qr.surname()
+	pt.Firstnames = qr.firstnames()
 	if pt.Lastname == "" && pt.Firstnames == "" {
 		return nil, nil
 	}
-	pt.Title = e.title()
-	switch e.gender() {
+	pt.Title = qr.title()
+	switch qr.gender() {
 	case "M":
 		pt.Gender = apiv1.Gender_MALE
 	case "F":
@@ -275,29 +453,37 @@ func (e *envelope) ToPatient() (*apiv1.Patient, error) {
 	default:
 		pt.Gender = apiv1.Gender_UNKNOWN
 	}
-	pt.BirthDate = e.dateBirth()
-	if dd := e.dateDeath(); dd != nil {
+	pt.BirthDate = qr.dateBirth()
+	if dd := qr.dateDeath(); dd != nil {
 		pt.Deceased = &apiv1.Patient_DeceasedDate{DeceasedDate: dd}
 	}
-	pt.Identifiers = e.identifiers()
-	pt.Addresses = e.addresses()
-	pt.Surgery = e.surgery()
-	pt.GeneralPractitioner = e.generalPractitioner()
-	pt.Telephones = e.telephones()
-	pt.Emails = e.emails()
+	pt.Identifiers = qr.identifiers()
+	pt.Addresses = qr.addresses()
+	pt.Surgery = qr.surgery()
+	pt.GeneralPractitioner = qr.generalPractitioner()
+	pt.Telephones = qr.telephones()
+	pt.Emails = qr.emails()
+	pt.Names = qr.names()
+	if len(qr.PID.PID10) > 0 {
+		pt.EthnicGroup = qr.PID.PID10[0].identifier(identifiers.CareConnectEthnicCategory)
+	}
+	pt.Language = qr.PID.PID15.identifier(identifiers.HL7v2Language)
+	pt.MaritalStatus = qr.PID.PID16.identifier(identifiers.HL7v2MaritalStatus)
+	pt.NhsNumberVerification = qr.nhsNumberVerification()
+	pt.Contacts = qr.contacts()
 	return pt, nil
 }
 
-func (e *envelope) surname() string {
-	names := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID5
+func (qr *queryResponse) surname() string {
+	names := qr.PID.PID5
 	if len(names) > 0 {
 		return names[0].XPN1.FN1.Text
 	}
 	return ""
 }
 
-func (e *envelope) firstnames() string {
-	names := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID5
+func (qr *queryResponse) firstnames() string {
+	names := qr.PID.PID5
 	var sb strings.Builder
 	if len(names) > 0 {
 		sb.WriteString(names[0].XPN2.Text) // given name - XPN.2
@@ -307,20 +493,20 @@ func (e *envelope) firstnames() string {
 	return strings.TrimSpace(sb.String())
 }
 
-func (e *envelope) title() string {
-	names := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID5
+func (qr *queryResponse) title() string {
+	names := qr.PID.PID5
 	if len(names) > 0 {
 		return names[0].XPN5.Text
 	}
 	return ""
 }
 
-func (e *envelope) gender() string {
-	return e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID8.Text
+func (qr *queryResponse) gender() string {
+	return qr.PID.PID8.Text
 }
 
-func (e *envelope) dateBirth() *timestamp.Timestamp {
-	dob := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID7.TS1.Text
+func (qr *queryResponse) dateBirth() *timestamp.Timestamp {
+	dob := qr.PID.PID7.TS1.Text
 	if len(dob) > 0 {
 		d, err := parseDate(dob)
 		if err == nil {
@@ -330,8 +516,8 @@ func (e *envelope) dateBirth() *timestamp.Timestamp {
 	return nil
 }
 
-func (e *envelope) dateDeath() *timestamp.Timestamp {
-	dod := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID29.TS1.Text
+func (qr *queryResponse) dateDeath() *timestamp.Timestamp {
+	dod := qr.PID.PID29.TS1.Text
 	if len(dod) > 0 {
 		d, err := parseDate(dod)
 		if err == nil {
@@ -341,37 +527,47 @@ func (e *envelope) dateDeath() *timestamp.Timestamp {
 	return nil
 }
 
-func (e *envelope) surgery() string {
-	return e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PD1.PD13.XON3.Text
+func (qr *queryResponse) surgery() string {
+	return qr.PD1.PD13.XON3.Text
 }
 
-func (e *envelope) generalPractitioner() string {
-	return e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PD1.PD14.XCN1.Text
+func (qr *queryResponse) generalPractitioner() string {
+	return qr.PD1.PD14.XCN1.Text
 }
 
-func (e *envelope) identifiers() []*apiv1.Identifier {
+func (qr *queryResponse) identifiers() []*apiv1.Identifier {
 	result := make([]*apiv1.Identifier, 0)
-	ids := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID3
+	ids := qr.PID.PID3
 	for _, id := range ids {
-		authority := id.CX4.HD1.Text
-		identifier := id.CX1.Text
-		if authority != "" && identifier != "" {
-			system := authority
-			if a := lookupFromEmpiOrgCode(system); a.ToURI() != "" {
-				system = a.ToURI()
-			}
-			result = append(result, &apiv1.Identifier{
-				System: system,
-				Value:  identifier,
-			})
+		if identifier := toIdentifier(id.CX4.HD1.Text, id.CX1.Text); identifier != nil {
+			result = append(result, identifier)
 		}
 	}
 	return result
 }
 
-func (e *envelope) addresses() []*apiv1.Address {
+// toIdentifier returns an identifier issued by the assigning authority specified, using the URI for the
+// authority if it is known, or nil if either is missing
+func toIdentifier(authority string, identifier string) *apiv1.Identifier {
+	if authority == "" || identifier == "" {
+		return nil
+	}
+	system := authority
+	if a := lookupFromEmpiOrgCode(system); a.ToURI() != "" {
+		system = a.ToURI()
+	}
+	return &apiv1.Identifier{
+		System: system,
+		Value:  identifier,
+	}
+}
+
+func (qr *queryResponse) addresses() []*apiv1.Address {
+	return toAddresses(qr.PID.PID11)
+}
+
+func toAddresses(addresses []xad) []*apiv1.Address {
 	result := make([]*apiv1.Address, 0)
-	addresses := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID11
 	for _, address := range addresses {
 		dateFrom, _ := parseDate(address.XAD13.Text)
 		dateTo, _ := parseDate(address.XAD14.Text)
@@ -390,26 +586,21 @@ func (e *envelope) addresses() []*apiv1.Address {
 	return result
 }
 
-func (e *envelope) telephones() []*apiv1.Telephone {
+func (qr *queryResponse) telephones() []*apiv1.Telephone {
+	return toTelephones(qr.PID.PID13, qr.PID.PID14)
+}
+
+func toTelephones(numbers ...[]xtn) []*apiv1.Telephone {
 	result := make([]*apiv1.Telephone, 0)
-	pid13 := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID13
-	for _, telephone := range pid13 {
-		num := telephone.XTN1.Text
-		if num != "" {
-			result = append(result, &apiv1.Telephone{
-				Number:      num,
-				Description: telephone.LongName,
-			})
-		}
-	}
-	pid14 := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID14
-	for _, telephone := range pid14 {
-		num := telephone.XTN1.Text
-		if num != "" {
-			result = append(result, &apiv1.Telephone{
-				Number:      num,
-				Description: telephone.LongName,
-			})
+	for _, telephones := range numbers {
+		for _, telephone := range telephones {
+			num := telephone.XTN1.Text
+			if num != "" {
+				result = append(result, &apiv1.Telephone{
+					Number:      num,
+					Description: telephone.LongName,
+				})
+			}
 		}
 	}
 	return result
@@ -418,25 +609,132 @@ func (e *envelope) telephones() []*apiv1.Telephone {
 // sanity check for emails
 var rxEmail = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
 
-func (e *envelope) emails() []string {
+func (qr *queryResponse) emails() []string {
 	result := make([]string, 0)
-	pid13 := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID13
-	for _, telephone := range pid13 {
-		email := telephone.XTN4.Text
-		if email != "" && len(email) < 255 && rxEmail.MatchString(email) {
-			result = append(result, email)
+	for _, telephones := range [][]xtn{qr.PID.PID13, qr.PID.PID14} {
+		for _, telephone := range telephones {
+			email := telephone.XTN4.Text
+			if email != "" && len(email) < 255 && rxEmail.MatchString(email) {
+				result = append(result, email)
+			}
 		}
 	}
-	pid14 := e.Body.InvokePatientDemographicsQueryResponse.RSPK21.RSPK21QUERYRESPONSE.PID.PID14
-	for _, telephone := range pid14 {
-		email := telephone.XTN4.Text
-		if email != "" && len(email) < 255 && rxEmail.MatchString(email) {
-			result = append(result, email)
+	return result
+}
+
+func (qr *queryResponse) names() []*apiv1.HumanName {
+	result := make([]*apiv1.HumanName, 0)
+	for _, name := range qr.PID.PID5 {
+		if hn := name.toHumanName(apiv1.HumanName_OFFICIAL); hn != nil {
+			result = append(result, hn)
+		}
+	}
+	for _, name := range qr.PID.PID9 {
+		if hn := name.toHumanName(apiv1.HumanName_NICKNAME); hn != nil {
+			result = append(result, hn)
+		}
+	}
+	return result
+}
+
+// nhsNumberVerification returns the NHS number verification status (PID-32), such as "01" for a number that is
+// present and verified
+func (qr *queryResponse) nhsNumberVerification() *apiv1.Identifier {
+	for _, status := range qr.PID.PID32 {
+		if code := strings.TrimSpace(status.Text); code != "" {
+			return &apiv1.Identifier{System: identifiers.NHSNumberVerificationStatus, Value: code}
+		}
+	}
+	return nil
+}
+
+// contacts returns the patient's contacts, including next of kin, from the NK1 segments
+func (qr *queryResponse) contacts() []*apiv1.Contact {
+	result := make([]*apiv1.Contact, 0)
+	for _, nk1 := range qr.NK1 {
+		contact := &apiv1.Contact{
+			Relationship: nk1.NK13.identifier(identifiers.HL7v2Relationship),
+			Role:         nk1.NK17.identifier(identifiers.HL7v2ContactRole),
+			Addresses:    toAddresses(nk1.NK14),
+			Telephones:   toTelephones(nk1.NK15, nk1.NK16),
+		}
+		if len(nk1.NK12) > 0 {
+			contact.Name = nk1.NK12[0].toHumanName(apiv1.HumanName_USUAL)
+		}
+		start, _ := parseDate(nk1.NK18.Text)
+		end, _ := parseDate(nk1.NK19.Text)
+		if start != nil || end != nil {
+			contact.Period = &apiv1.Period{Start: start, End: end}
+		}
+		if contact.Name != nil || len(contact.Addresses) > 0 || len(contact.Telephones) > 0 {
+			result = append(result, contact)
 		}
 	}
 	return result
 }
 
+// nameUses maps HL7 v2 name types (table 0200) to the use of a name
+var nameUses = map[string]apiv1.HumanName_Use{
+	"L":  apiv1.HumanName_OFFICIAL,  // legal
+	"B":  apiv1.HumanName_OFFICIAL,  // birth
+	"D":  apiv1.HumanName_USUAL,     // display
+	"A":  apiv1.HumanName_NICKNAME,  // alias
+	"N":  apiv1.HumanName_NICKNAME,  // nickname
+	"M":  apiv1.HumanName_MAIDEN,    // maiden
+	"NB": apiv1.HumanName_TEMPORARY, // newborn
+	"T":  apiv1.HumanName_TEMPORARY, // temporary
+	"S":  apiv1.HumanName_ANONYMOUS, // coded pseudo-name
+	"U":  apiv1.HumanName_UNKNOWN,   // unspecified
+}
+
+// toHumanName converts an extended person name, using the use specified if the name has no type.
+// A name that is no longer in use, because its period has ended, is an old name.
+func (name xpn) toHumanName(use apiv1.HumanName_Use) *apiv1.HumanName {
+	family := strings.TrimSpace(name.XPN1.FN1.Text)
+	given := strings.TrimSpace(name.XPN2.Text + " " + name.XPN3.Text)
+	if family == "" && given == "" {
+		return nil
+	}
+	hn := &apiv1.HumanName{Family: family, Given: given}
+	if u, ok := nameUses[strings.TrimSpace(name.XPN7.Text)]; ok {
+		use = u
+	}
+	if prefix := strings.TrimSpace(name.XPN5.Text); prefix != "" {
+		hn.Prefixes = []string{prefix}
+	}
+	if suffix := strings.TrimSpace(name.XPN4.Text); suffix != "" {
+		hn.Suffices = []string{suffix}
+	}
+	start, _ := parseDate(name.XPN12.TS1.Text)
+	end, _ := parseDate(name.XPN13.TS1.Text)
+	if start != nil || end != nil {
+		hn.Period = &apiv1.Period{Start: start, End: end}
+	}
+	if end != nil {
+		if t, err := ptypes.Timestamp(end); err == nil && t.Before(time.Now()) {
+			use = apiv1.HumanName_OLD
+		}
+	}
+	hn.Use = use
+	return hn
+}
+
+// identifier returns the coded element as an identifier in the coding system specified, unless the element
+// names its own coding system as a URI. The code may be given as the text of the element, rather than as CE.1.
+func (c ce) identifier(system string) *apiv1.Identifier {
+	code := strings.TrimSpace(c.CE1.Text)
+	if code == "" && c.CE1.Text == "" {
+		code = strings.TrimSpace(c.Text)
+	}
+	if code == "" {
+		return nil
+	}
+	if cs := strings.TrimSpace(c.CE3.Text); strings.Contains(cs, "://") {
+		system = cs
+	}
+	return &apiv1.Identifier{System: system, Value: code}
+}
+
 func parseDate(d string) (*timestamp.Timestamp, error) {
 	layout := "20060102" // reference date is : Mon Jan 2 15:04:05 MST 2006
 	if len(d) > 8 {
@@ -782,316 +1080,352 @@ type envelope struct {
 						} `xml:"QIP.2"`
 					} `xml:"QPD.3"`
 				} `xml:"QPD"`
-				RSPK21QUERYRESPONSE struct {
-					Text string `xml:",chardata"`
-					PID  struct {
-						Text string `xml:",chardata"`
-						PID1 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-						} `xml:"PID.1"`
-						PID3 []struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							CX1      struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"CX.1"`
-							CX4 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-								HD1      struct {
-									Text     string `xml:",chardata"`
-									Type     string `xml:"Type,attr"`
-									Table    string `xml:"Table,attr"`
-									LongName string `xml:"LongName,attr"`
-								} `xml:"HD.1"`
-							} `xml:"CX.4"`
-							CX5 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"CX.5"`
-						} `xml:"PID.3"`
-						PID5 []struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							XPN1     struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-								FN1      struct {
-									Text     string `xml:",chardata"`
-									Type     string `xml:"Type,attr"`
-									LongName string `xml:"LongName,attr"`
-								} `xml:"FN.1"`
-							} `xml:"XPN.1"`
-							XPN2 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XPN.2"`
-							XPN3 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XPN.3"`
-							XPN5 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XPN.5"`
-							XPN7 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XPN.7"`
-						} `xml:"PID.5"`
-						PID7 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							TS1      struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"TS.1"`
-						} `xml:"PID.7"`
-						PID8 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							Table    string `xml:"Table,attr"`
-							LongName string `xml:"LongName,attr"`
-						} `xml:"PID.8"`
-						PID9 []struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							XPN7     struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XPN.7"`
-						} `xml:"PID.9"`
-						PID11 []struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							XAD1     struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-								SAD1     struct {
-									Text     string `xml:",chardata"`
-									Type     string `xml:"Type,attr"`
-									LongName string `xml:"LongName,attr"`
-								} `xml:"SAD.1"`
-							} `xml:"XAD.1"`
-							XAD2 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XAD.2"`
-							XAD3 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XAD.3"`
-							XAD4 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XAD.4"`
-							XAD5 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XAD.5"`
-							XAD7 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XAD.7"`
-							XAD13 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XAD.13"`
-							XAD14 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XAD.14"`
-						} `xml:"PID.11"`
-						PID13 []struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							XTN1     struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XTN.1"`
-							XTN2 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XTN.2"`
-							XTN4 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XTN.4"`
-						} `xml:"PID.13"`
-						PID14 []struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							XTN1     struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XTN.1"`
-							XTN2 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								Table    string `xml:"Table,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XTN.2"`
-							XTN4 struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XTN.4"`
-						} `xml:"PID.14"`
-						PID15 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							Table    string `xml:"Table,attr"`
-							LongName string `xml:"LongName,attr"`
-							CE1      struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"CE.1"`
-						} `xml:"PID.15"`
-						PID16 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							Table    string `xml:"Table,attr"`
-							LongName string `xml:"LongName,attr"`
-							CE1      struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"CE.1"`
-						} `xml:"PID.16"`
-						PID17 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							Table    string `xml:"Table,attr"`
-							LongName string `xml:"LongName,attr"`
-							CE1      struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"CE.1"`
-						} `xml:"PID.17"`
-						PID22 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							Table    string `xml:"Table,attr"`
-							LongName string `xml:"LongName,attr"`
-							CE1      struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"CE.1"`
-						} `xml:"PID.22"`
-						PID24 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							Table    string `xml:"Table,attr"`
-							LongName string `xml:"LongName,attr"`
-						} `xml:"PID.24"`
-						PID28 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							Table    string `xml:"Table,attr"`
-							LongName string `xml:"LongName,attr"`
-							CE1      struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"CE.1"`
-						} `xml:"PID.28"`
-						PID29 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							TS1      struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"TS.1"`
-						} `xml:"PID.29"`
-					} `xml:"PID"`
-					PD1 struct {
-						Text string `xml:",chardata"`
-						PD13 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							XON3     struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XON.3"`
-						} `xml:"PD1.3"`
-						PD14 struct {
-							Text     string `xml:",chardata"`
-							Item     string `xml:"Item,attr"`
-							Type     string `xml:"Type,attr"`
-							LongName string `xml:"LongName,attr"`
-							XCN1     struct {
-								Text     string `xml:",chardata"`
-								Type     string `xml:"Type,attr"`
-								LongName string `xml:"LongName,attr"`
-							} `xml:"XCN.1"`
-						} `xml:"PD1.4"`
-					} `xml:"PD1"`
-				} `xml:"RSP_K21.QUERY_RESPONSE"`
+				RSPK21QUERYRESPONSE queryResponse `xml:"RSP_K21.QUERY_RESPONSE"`
 			} `xml:"RSP_K21"`
 		} `xml:"InvokePatientDemographicsQueryResponse"`
 	} `xml:"Body"`
 }
+
+// queryResponse is the patient in a response to a query, with PID, PD1 and NK1 (next of kin) segments
+type queryResponse struct {
+	Text string `xml:",chardata"`
+	PID  struct {
+		Text string `xml:",chardata"`
+		PID1 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+		} `xml:"PID.1"`
+		PID3 []struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+			CX1      struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"CX.1"`
+			CX4 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				Table    string `xml:"Table,attr"`
+				LongName string `xml:"LongName,attr"`
+				HD1      struct {
+					Text     string `xml:",chardata"`
+					Type     string `xml:"Type,attr"`
+					Table    string `xml:"Table,attr"`
+					LongName string `xml:"LongName,attr"`
+				} `xml:"HD.1"`
+			} `xml:"CX.4"`
+			CX5 struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				Table    string `xml:"Table,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"CX.5"`
+		} `xml:"PID.3"`
+		PID5 []xpn `xml:"PID.5"`
+		PID7 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+			TS1      struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"TS.1"`
+		} `xml:"PID.7"`
+		PID8 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			Table    string `xml:"Table,attr"`
+			LongName string `xml:"LongName,attr"`
+		} `xml:"PID.8"`
+		PID9  []xpn `xml:"PID.9"`
+		PID10 []ce  `xml:"PID.10"`
+		PID11 []xad `xml:"PID.11"`
+		PID13 []xtn `xml:"PID.13"`
+		PID14 []xtn `xml:"PID.14"`
+		PID15 ce    `xml:"PID.15"`
+		PID16 ce    `xml:"PID.16"`
+		PID17 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			Table    string `xml:"Table,attr"`
+			LongName string `xml:"LongName,attr"`
+			CE1      struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"CE.1"`
+		} `xml:"PID.17"`
+		PID22 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			Table    string `xml:"Table,attr"`
+			LongName string `xml:"LongName,attr"`
+			CE1      struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"CE.1"`
+		} `xml:"PID.22"`
+		PID24 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			Table    string `xml:"Table,attr"`
+			LongName string `xml:"LongName,attr"`
+		} `xml:"PID.24"`
+		PID28 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			Table    string `xml:"Table,attr"`
+			LongName string `xml:"LongName,attr"`
+			CE1      struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"CE.1"`
+		} `xml:"PID.28"`
+		PID29 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+			TS1      struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"TS.1"`
+		} `xml:"PID.29"`
+		PID32 []struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			Table    string `xml:"Table,attr"`
+			LongName string `xml:"LongName,attr"`
+		} `xml:"PID.32"`
+	} `xml:"PID"`
+	PD1 struct {
+		Text string `xml:",chardata"`
+		PD13 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+			XON3     struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XON.3"`
+		} `xml:"PD1.3"`
+		PD14 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+			XCN1     struct {
+				Text     string `xml:",chardata"`
+				Type     string `xml:"Type,attr"`
+				LongName string `xml:"LongName,attr"`
+			} `xml:"XCN.1"`
+		} `xml:"PD1.4"`
+	} `xml:"PD1"`
+	NK1 []struct {
+		Text string `xml:",chardata"`
+		NK12 []xpn  `xml:"NK1.2"`
+		NK13 ce     `xml:"NK1.3"`
+		NK14 []xad  `xml:"NK1.4"`
+		NK15 []xtn  `xml:"NK1.5"`
+		NK16 []xtn  `xml:"NK1.6"`
+		NK17 ce     `xml:"NK1.7"`
+		NK18 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+		} `xml:"NK1.8"`
+		NK19 struct {
+			Text     string `xml:",chardata"`
+			Item     string `xml:"Item,attr"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+		} `xml:"NK1.9"`
+	} `xml:"NK1"`
+}
+
+// xpn is an extended person name (XPN), used for patient names, aliases and the names of contacts
+type xpn struct {
+	Text     string `xml:",chardata"`
+	Item     string `xml:"Item,attr"`
+	Type     string `xml:"Type,attr"`
+	LongName string `xml:"LongName,attr"`
+	XPN1     struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		LongName string `xml:"LongName,attr"`
+		FN1      struct {
+			Text     string `xml:",chardata"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+		} `xml:"FN.1"`
+	} `xml:"XPN.1"`
+	XPN2 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"XPN.2"`
+	XPN3 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"XPN.3"`
+	XPN4 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"XPN.4"`
+	XPN5 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"XPN.5"`
+	XPN7 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		Table    string `xml:"Table,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"XPN.7"`
+	XPN12 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		LongName string `xml:"LongName,attr"`
+		TS1      struct {
+			Text     string `xml:",chardata"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+		} `xml:"TS.1"`
+	} `xml:"XPN.12"`
+	XPN13 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		LongName string `xml:"LongName,attr"`
+		TS1      struct {
+			Text     string `xml:",chardata"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+		} `xml:"TS.1"`
+	} `xml:"XPN.13"`
+}
+
+// xad is an extended address (XAD)
+type xad struct {
+	Text     string `xml:",chardata"`
+	Item     string `xml:"Item,attr"`
+	Type     string `xml:"Type,attr"`
+	LongName string `xml:"LongName,attr"`
+	XAD1     struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		LongName string `xml:"LongName,attr"`
+		SAD1     struct {
+			Text     string `xml:",chardata"`
+			Type     string `xml:"Type,attr"`
+			LongName string `xml:"LongName,attr"`
+		} `xml:"SAD.1"`
+	} `xml:"XAD.1"`
+	XAD2 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"XAD.2"`
+	XAD3 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"XAD.3"`
+	XAD4 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"XAD.4"`
+	XAD5 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"XAD.5"`
+	XAD7 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		Table    string `xml:"Table,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"XAD.7"`
+	XAD13 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		Table    string `xml:"Table,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"XAD.13"`
+	XAD14 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		Table    string `xml:"Table,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"XAD.14"`
+}
+
+// xtn is an extended telecommunication number (XTN), for telephone numbers and emails
+type xtn struct {
+	Text     string `xml:",chardata"`
+	Item     string `xml:"Item,attr"`
+	Type     string `xml:"Type,attr"`
+	LongName string `xml:"LongName,attr"`
+	XTN1     struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"XTN.1"`
+	XTN2 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		Table    string `xml:"Table,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"XTN.2"`
+	XTN4 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"XTN.4"`
+}
+
+// ce is a coded element (CE), with an identifier, text and the name of the coding system
+type ce struct {
+	Text     string `xml:",chardata"`
+	Item     string `xml:"Item,attr"`
+	Type     string `xml:"Type,attr"`
+	Table    string `xml:"Table,attr"`
+	LongName string `xml:"LongName,attr"`
+	CE1      struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"CE.1"`
+	CE2 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"CE.2"`
+	CE3 struct {
+		Text     string `xml:",chardata"`
+		Type     string `xml:"Type,attr"`
+		LongName string `xml:"LongName,attr"`
+	} `xml:"CE.3"`
+}
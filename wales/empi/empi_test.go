@@ -0,0 +1,64 @@
+package empi
+
+import (
+	"encoding/xml"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// TestParseResponses parses each anonymised EMPI response in testdata/responses and compares the resulting
+// patient with the corresponding golden file. Run with -update to regenerate the golden files.
+func TestParseResponses(t *testing.T) {
+	filenames, err := filepath.Glob("testdata/responses/*.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filenames) == 0 {
+		t.Fatal("no sample responses")
+	}
+	for _, filename := range filenames {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var e envelope
+		if err := xml.Unmarshal(data, &e); err != nil {
+			t.Fatalf("%s: %s", filename, err)
+		}
+		pt, err := e.ToPatient()
+		if err != nil {
+			t.Fatalf("%s: %s", filename, err)
+		}
+		golden := strings.TrimSuffix(filename, ".xml") + ".golden.json"
+		if *update {
+			b, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(pt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(golden, append(b, '\n'), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		b, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := new(apiv1.Patient)
+		if err := protojson.Unmarshal(b, expected); err != nil {
+			t.Fatalf("%s: %s", golden, err)
+		}
+		if !proto.Equal(expected, pt) {
+			t.Errorf("%s: patient does not match %s\ngot: %s", filename, golden, protojson.Format(pt))
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package empi
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/uuid"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/resilience"
+	"github.com/wardle/concierge/server"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxCandidates is the maximum number of candidates requested from the EMPI for a demographic search
+const maxCandidates = 20
+
+// Candidate is a patient found by a demographic search, with the EMPI's confidence in the match
+type Candidate struct {
+	Patient *apiv1.Patient
+	Score   float64 // candidate confidence (QRI.1), or zero if not given
+}
+
+// SearchPatient searches for patients by demographics, streaming candidates in order of decreasing match score
+func (app *App) SearchPatient(r *apiv1.PatientSearchRequest, s apiv1.PatientDirectory_SearchPatientServer) error {
+	candidates, err := app.SearchPatients(s.Context(), r)
+	if err != nil {
+		return err
+	}
+	for _, c := range candidates {
+		if err := s.Send(c.Patient); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AccessChecked reports that searches omit candidates the caller may not access, so that the patient directory
+// need not check them again
+func (app *App) AccessChecked() bool {
+	return true
+}
+
+// SearchPatients performs a demographic search (QBP^Q22) by name, date of birth, gender and postcode, returning
+// candidates in order of decreasing match score. A surname and at least one other criterion are required.
+// Candidates that the caller may not access, such as patients with whom the caller has no relationship, are
+// omitted. The search criteria are not logged, as these identify the patient sought.
+func (app *App) SearchPatients(ctx context.Context, r *apiv1.PatientSearchRequest) ([]*Candidate, error) {
+	if strings.TrimSpace(r.GetLastName()) == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "patient search requires a last name")
+	}
+	if r.GetFirstName() == "" && r.GetBirthDate() == nil && r.GetGender() == apiv1.Gender_UNKNOWN && r.GetPostcode() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "patient search requires a first name, date of birth, gender or postcode as well as a last name")
+	}
+	ucd := server.GetContextData(ctx)
+	user := ucd.GetAuthenticatedUser().GetSystem() + "|" + ucd.GetAuthenticatedUser().GetValue()
+	candidates, err := app.search(ctx, r)
+	if err != nil {
+		log.Printf("empi: search from '%s' failed: %s", user, err)
+		return nil, err
+	}
+	permitted := candidates[:0]
+	for _, c := range candidates {
+		err := identifiers.CheckAccess(ctx, c.Patient)
+		if status.Code(err) == codes.PermissionDenied {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		permitted = append(permitted, c)
+	}
+	log.Printf("empi: search from '%s': %d candidates (%d withheld)", user, len(permitted), len(candidates)-len(permitted))
+	return permitted, nil
+}
+
+// search performs a demographic search, returning all candidates
+func (app *App) search(ctx context.Context, r *apiv1.PatientSearchRequest) ([]*Candidate, error) {
+	if app.Fake {
+		log.Printf("empi: returning fake result for search")
+		params := make(map[string]string)
+		for _, p := range demographicParameters(r) {
+			params[p.Field] = p.Value
+		}
+		return NewSimulator(app.fakeData().Patients()...).findByDemographics(params), nil
+	}
+	endpointURL, processingID, timeout, _ := app.config()
+	if timeout == 0 {
+		timeout = 1
+	}
+	var candidates []*Candidate
+	err := resilience.Get(BackendName).Do(ctx, true, func(ctx context.Context) error {
+		ctx, cancelFunc := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancelFunc()
+		var err error
+		candidates, err = performSearch(ctx, endpointURL, processingID, r)
+		return err
+	})
+	if err != nil {
+		if urlError, ok := err.(*url.Error); ok && urlError.Timeout() {
+			err = status.Errorf(codes.DeadlineExceeded, "NHS Wales' EMPI service did not respond within deadline (%d sec)", timeout)
+		}
+		return nil, err
+	}
+	return candidates, nil
+}
+
+func performSearch(ctx context.Context, endpointURL string, processingID string, r *apiv1.PatientSearchRequest) ([]*Candidate, error) {
+	start := time.Now()
+	data, err := NewDemographicsRequest(r, "221", "100", processingID)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpointURL, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-type", "text/xml; charset=\"utf-8\"")
+	req.Header.Set("SOAPAction", "http://apps.wales.nhs.uk/mpi/InvokePatientDemographicsQuery")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 500 {
+		return nil, fmt.Errorf("empi: server error: %s", resp.Status)
+	}
+	log.Printf("empi: search response (%s): %d bytes", time.Since(start), len(body))
+	return parseSearchResponse(body)
+}
+
+// parseSearchResponse parses the candidates from a RSP^K22 response, in order of decreasing match score
+func parseSearchResponse(body []byte) ([]*Candidate, error) {
+	var e searchEnvelope
+	if err := xml.Unmarshal(body, &e); err != nil {
+		return nil, err
+	}
+	var responses []candidateResponse
+	for _, rsp := range []*searchResponse{&e.Body.Response.RSPK21, &e.Body.Response.RSPK22} {
+		if qs := rsp.QAK.QAK2; qs == "AE" || qs == "AR" {
+			return nil, status.Errorf(codes.InvalidArgument, "empi: search rejected (%s)", qs)
+		}
+		responses = append(responses, rsp.QueryResponsesK21...)
+		responses = append(responses, rsp.QueryResponsesK22...)
+	}
+	result := make([]*Candidate, 0, len(responses))
+	for i := range responses {
+		qr := &responses[i]
+		pt, err := qr.toPatient()
+		if err != nil {
+			return nil, err
+		}
+		if pt == nil {
+			continue
+		}
+		score, _ := strconv.ParseFloat(strings.TrimSpace(qr.QRI.QRI1), 64)
+		result = append(result, &Candidate{Patient: pt, Score: score})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Score > result[j].Score })
+	return result, nil
+}
+
+// QueryParameter is a demographic field (QIP.1) and value (QIP.2) for a query
+type QueryParameter struct {
+	Field string
+	Value string
+}
+
+// DemographicsRequest is used to populate the template to make the XML request for a demographic search
+type DemographicsRequest struct {
+	Parameters           []QueryParameter
+	SendingApplication   string
+	SendingFacility      string
+	ReceivingApplication string
+	ReceivingFacility    string
+	DateTime             string
+	MessageControlID     string //for MSH.10 -  a UUID
+	ProcessingID         string //for MSH.11 - P/U/T production/testing/development
+	Limit                int    //for RCP.2 - maximum number of candidates
+}
+
+// NewDemographicsRequest returns a correctly formatted XML request (QBP^Q22) to search by demographics
+// sender : 221 (PatientCare)
+// receiver: 100 (NHS Wales EMPI)
+func NewDemographicsRequest(r *apiv1.PatientSearchRequest, sender string, receiver string, processingID string) ([]byte, error) {
+	if r.GetBirthDate() != nil {
+		if _, err := ptypes.Timestamp(r.GetBirthDate()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid date of birth: %s", err)
+		}
+	}
+	data := DemographicsRequest{
+		Parameters:           demographicParameters(r),
+		SendingApplication:   sender,
+		SendingFacility:      sender,
+		ReceivingApplication: receiver,
+		ReceivingFacility:    receiver,
+		DateTime:             time.Now().Format("20060102150405"),
+		MessageControlID:     uuid.New().String(),
+		ProcessingID:         processingID,
+		Limit:                maxCandidates,
+	}
+	t, err := template.New("demographics-request").Funcs(template.FuncMap{"xml": escapeXML}).Parse(demographicsRequestTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// demographicParameters returns the query parameters for a demographic search
+func demographicParameters(r *apiv1.PatientSearchRequest) []QueryParameter {
+	var params []QueryParameter
+	add := func(field string, value string) {
+		if value = strings.ToUpper(strings.TrimSpace(value)); value != "" {
+			params = append(params, QueryParameter{Field: field, Value: value})
+		}
+	}
+	add("@PID.5.1.1", r.GetLastName())
+	add("@PID.5.2", r.GetFirstName())
+	add("@PID.7.1", formatTimestamp(r.GetBirthDate()))
+	switch r.GetGender() {
+	case apiv1.Gender_MALE:
+		add("@PID.8", "M")
+	case apiv1.Gender_FEMALE:
+		add("@PID.8", "F")
+	}
+	add("@PID.11.5", r.GetPostcode())
+	return params
+}
+
+func escapeXML(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
+
+var demographicsRequestTemplate = `
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns:mpi="http://apps.wales.nhs.uk/mpi/" xmlns="urn:hl7-org:v2xml">
+<soapenv:Header/>
+<soapenv:Body>
+   <mpi:InvokePatientDemographicsQuery>
+	  <QBP_Q21>
+		 <MSH>
+			<MSH.1>|</MSH.1>
+			<MSH.2>^~\&amp;</MSH.2>
+			<MSH.3><HD.1>{{.SendingApplication}}</HD.1></MSH.3>
+			<MSH.4><HD.1>{{.SendingFacility}}</HD.1></MSH.4>
+			<MSH.5><HD.1>{{.ReceivingApplication}}</HD.1></MSH.5>
+			<MSH.6><HD.1>{{.ReceivingFacility}}</HD.1></MSH.6>
+			<MSH.7><TS.1>{{.DateTime}}</TS.1></MSH.7>
+			<MSH.9>
+			   <MSG.1>QBP</MSG.1>
+			   <MSG.2>Q22</MSG.2>
+			   <MSG.3>QBP_Q21</MSG.3>
+			</MSH.9>
+			<MSH.10>{{.MessageControlID}}</MSH.10>
+			<MSH.11><PT.1>{{.ProcessingID}}</PT.1></MSH.11>
+			<MSH.12><VID.1>2.5</VID.1></MSH.12>
+			<MSH.17>GBR</MSH.17>
+		 </MSH>
+		 <QPD>
+			<QPD.1><CE.1>IHE PDQ Query</CE.1></QPD.1>
+			<QPD.2>PatientQuery</QPD.2>{{range .Parameters}}
+			<QPD.3>
+			   <QIP.1>{{.Field}}</QIP.1>
+			   <QIP.2>{{xml .Value}}</QIP.2>
+			</QPD.3>{{end}}
+		 </QPD>
+		 <RCP>
+			<RCP.1>I</RCP.1>
+			<RCP.2><CQ.1>{{.Limit}}</CQ.1></RCP.2>
+		 </RCP>
+	  </QBP_Q21>
+   </mpi:InvokePatientDemographicsQuery>
+</soapenv:Body>
+</soapenv:Envelope>
+`
+
+// searchEnvelope is the response to a demographic search, which may contain multiple candidate patients.
+// The response may be structured as RSP_K21 or RSP_K22.
+type searchEnvelope struct {
+	XMLName xml.Name `xml:"Envelope"`
+	Body    struct {
+		Response struct {
+			RSPK21 searchResponse `xml:"RSP_K21"`
+			RSPK22 searchResponse `xml:"RSP_K22"`
+		} `xml:"InvokePatientDemographicsQueryResponse"`
+	} `xml:"Body"`
+}
+
+type searchResponse struct {
+	QAK struct {
+		QAK2 string `xml:"QAK.2"` // query response status: OK, NF, AE or AR
+	} `xml:"QAK"`
+	QueryResponsesK21 []candidateResponse `xml:"RSP_K21.QUERY_RESPONSE"`
+	QueryResponsesK22 []candidateResponse `xml:"RSP_K22.QUERY_RESPONSE"`
+}
+
+// candidateResponse is a candidate patient in a query response, with the query response instance (QRI) segment
+type candidateResponse struct {
+	queryResponse
+	QRI struct {
+		QRI1 string `xml:"QRI.1"` // candidate confidence
+	} `xml:"QRI"`
+}
@@ -0,0 +1,135 @@
+package empi
+
+import (
+	"context"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const sampleSearchResponse = `<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+<soap:Body>
+<InvokePatientDemographicsQueryResponse xmlns="http://apps.wales.nhs.uk/mpi/">
+<RSP_K22 xmlns="urn:hl7-org:v2xml">
+  <QAK><QAK.1>PatientQuery</QAK.1><QAK.2>OK</QAK.2></QAK>
+  <RSP_K22.QUERY_RESPONSE>
+    <PID>
+      <PID.3><CX.1>1234567890</CX.1><CX.4><HD.1>NHS</HD.1></CX.4></PID.3>
+      <PID.5><XPN.1><FN.1>SMITH</FN.1></XPN.1><XPN.2>JOHN</XPN.2></PID.5>
+      <PID.7><TS.1>19700101</TS.1></PID.7>
+      <PID.8>M</PID.8>
+    </PID>
+    <QRI><QRI.1>72</QRI.1></QRI>
+  </RSP_K22.QUERY_RESPONSE>
+  <RSP_K22.QUERY_RESPONSE>
+    <PID>
+      <PID.3><CX.1>9876543210</CX.1><CX.4><HD.1>NHS</HD.1></CX.4></PID.3>
+      <PID.5><XPN.1><FN.1>SMITH</FN.1></XPN.1><XPN.2>JON</XPN.2></PID.5>
+      <PID.7><TS.1>19700101</TS.1></PID.7>
+      <PID.8>M</PID.8>
+      <PID.11><XAD.1><SAD.1>1 Station Road</SAD.1></XAD.1><XAD.5>CF14 4XW</XAD.5></PID.11>
+    </PID>
+    <QRI><QRI.1>95</QRI.1></QRI>
+  </RSP_K22.QUERY_RESPONSE>
+</RSP_K22>
+</InvokePatientDemographicsQueryResponse>
+</soap:Body>
+</soap:Envelope>`
+
+func TestParseSearchResponse(t *testing.T) {
+	candidates, err := parseSearchResponse([]byte(sampleSearchResponse))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("expected 2 candidates, got %d", len(candidates))
+	}
+	best := candidates[0]
+	if best.Score != 95 || best.Patient.GetFirstnames() != "JON" || best.Patient.GetAddresses()[0].GetPostcode() != "CF14 4XW" {
+		t.Fatalf("candidates not ordered by score: %+v", best)
+	}
+	if ids, ok := best.Patient.GetIdentifiersForSystem(identifiers.NHSNumber); !ok || ids[0].GetValue() != "9876543210" {
+		t.Fatalf("NHS number not parsed: %v", best.Patient.GetIdentifiers())
+	}
+	if candidates[1].Score != 72 || candidates[1].Patient.GetLastname() != "SMITH" {
+		t.Fatalf("incorrect second candidate: %+v", candidates[1])
+	}
+	rejected := strings.Replace(sampleSearchResponse, "<QAK.2>OK</QAK.2>", "<QAK.2>AE</QAK.2>", 1)
+	if _, err := parseSearchResponse([]byte(rejected)); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected rejected search to be invalid, got: %v", err)
+	}
+}
+
+func TestDemographicsRequest(t *testing.T) {
+	dob, _ := ptypes.TimestampProto(time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC))
+	data, err := NewDemographicsRequest(&apiv1.PatientSearchRequest{
+		LastName:  "o'brien & co",
+		BirthDate: dob,
+		Gender:    apiv1.Gender_FEMALE,
+		Postcode:  "cf14 4xw",
+	}, "221", "100", "T")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var request struct {
+		Parameters []struct {
+			Field string `xml:"QIP.1"`
+			Value string `xml:"QIP.2"`
+		} `xml:"Body>InvokePatientDemographicsQuery>QBP_Q21>QPD>QPD.3"`
+	}
+	if err := xml.Unmarshal(data, &request); err != nil {
+		t.Fatalf("invalid request: %s\n%s", err, data)
+	}
+	expected := map[string]string{"@PID.5.1.1": "O'BRIEN & CO", "@PID.7.1": "19700101", "@PID.8": "F", "@PID.11.5": "CF14 4XW"}
+	if len(request.Parameters) != len(expected) {
+		t.Fatalf("expected %d query parameters, got: %+v", len(expected), request.Parameters)
+	}
+	for _, p := range request.Parameters {
+		if expected[p.Field] != p.Value {
+			t.Errorf("query parameter %s: expected '%s', got '%s'", p.Field, expected[p.Field], p.Value)
+		}
+	}
+}
+
+func TestSearchRequiresCriteria(t *testing.T) {
+	app := &App{Fake: true}
+	for _, r := range []*apiv1.PatientSearchRequest{{}, {LastName: "SMITH"}, {FirstName: "JOHN", Postcode: "CF14 4XW"}} {
+		if _, err := app.SearchPatients(context.Background(), r); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("search %v: expected invalid argument, got: %v", r, err)
+		}
+	}
+	candidates, err := app.SearchPatients(context.Background(), &apiv1.PatientSearchRequest{LastName: "DUMMY", FirstName: "BETTY", Postcode: "CF31 2PJ"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 2 || candidates[0].Patient.GetFirstnames() != "BETTY" || candidates[0].Score <= candidates[1].Score {
+		t.Fatalf("incorrect candidates from fake search: %v", candidates)
+	}
+}
+
+func TestSearchWithholdsRestrictedPatients(t *testing.T) {
+	app := &App{Fake: true}
+	r := &apiv1.PatientSearchRequest{LastName: "DUMMY", FirstName: "BETTY", Postcode: "CF31 2PJ"}
+	candidates, err := app.SearchPatients(context.Background(), r)
+	if err != nil || len(candidates) != 2 {
+		t.Fatalf("expected two candidates, got %v (%v)", candidates, err)
+	}
+	restricted := candidates[0].Patient.GetIdentifiers()[0]
+	identifiers.RegisterAccessPolicy("test-restricted", identifiers.RecordPolicy(restricted))
+	t.Cleanup(func() { identifiers.UnregisterAccessPolicy("test-restricted") })
+	candidates, err = app.SearchPatients(context.Background(), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 1 || candidates[0].Patient.GetFirstnames() == "BETTY" {
+		t.Fatalf("restricted patient not withheld from search: %v", candidates)
+	}
+}
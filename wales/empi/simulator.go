@@ -0,0 +1,395 @@
+package empi
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/google/uuid"
+	"github.com/wardle/concierge/apiv1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Simulator is a HTTP server that speaks the NHS Wales EMPI's InvokePatientDemographicsQuery SOAP contract,
+// serving synthetic patients, so that the request templates and response parsing can be exercised without
+// access to the NHS Wales network. Latency, faults and not-found responses can be injected.
+// Point App.EndpointURL at a running simulator to use it.
+type Simulator struct {
+	Latency      time.Duration // delay before each response
+	FaultRate    float64       // proportion of requests, 0-1, that fail with a SOAP fault
+	NotFoundRate float64       // proportion of requests, 0-1, that find no patient
+
+	mu       sync.RWMutex
+	patients []*apiv1.Patient
+	rand     *rand.Rand
+}
+
+var _ http.Handler = (*Simulator)(nil)
+
+// NewSimulator creates a simulator serving the patients specified
+func NewSimulator(patients ...*apiv1.Patient) *Simulator {
+	return &Simulator{
+		patients: patients,
+		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// LoadFixtures adds the synthetic patients in the specified directory, one patient per file (*.json) in the
+// JSON representation of apiv1.Patient. Identifiers use namespace URIs, such as https://fhir.nhs.uk/Id/nhs-number.
+func (sim *Simulator) LoadFixtures(dir string) error {
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	patients := make([]*apiv1.Patient, 0, len(filenames))
+	for _, filename := range filenames {
+		b, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		pt := new(apiv1.Patient)
+		if err := protojson.Unmarshal(b, pt); err != nil {
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+		patients = append(patients, pt)
+	}
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+	sim.patients = append(sim.patients, patients...)
+	log.Printf("empi: simulator: loaded %d patients from %s", len(patients), dir)
+	return nil
+}
+
+// ServeHTTP handles a SOAP request for a patient demographics query
+func (sim *Simulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if action := strings.Trim(r.Header.Get("SOAPAction"), `"`); action != "http://apps.wales.nhs.uk/mpi/InvokePatientDemographicsQuery" {
+		sim.fault(w, http.StatusBadRequest, "soap:Client", fmt.Sprintf("unsupported SOAP action: '%s'", action))
+		return
+	}
+	var req simulatorRequest
+	if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+		sim.fault(w, http.StatusBadRequest, "soap:Client", fmt.Sprintf("invalid request: %s", err))
+		return
+	}
+	time.Sleep(sim.Latency)
+	if sim.chance(sim.FaultRate) {
+		sim.fault(w, http.StatusInternalServerError, "soap:Server", "simulated fault")
+		return
+	}
+	q := &req.Body.Query.QBP
+	params := make(map[string]string)
+	for _, p := range q.QPD.QPD3 {
+		params[p.QIP1] = strings.TrimSpace(p.QIP2)
+	}
+	var candidates []*Candidate
+	if !sim.chance(sim.NotFoundRate) {
+		if id, found := params["@PID.3.1"]; found {
+			candidates = sim.findByIdentifier(params["@PID.3.4"], id)
+		} else {
+			candidates = sim.findByDemographics(params)
+		}
+	}
+	if limit := q.RCP.RCP2.CQ1; limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	log.Printf("empi: simulator: query: %d candidates", len(candidates))
+	var buf bytes.Buffer
+	if err := simulatorResponse.Execute(&buf, newSimulatorResponse(q.MSH.MSH10, candidates)); err != nil {
+		sim.fault(w, http.StatusInternalServerError, "soap:Server", err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+func (sim *Simulator) chance(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+	return sim.rand.Float64() < p
+}
+
+func (sim *Simulator) fault(w http.ResponseWriter, code int, faultCode string, faultString string) {
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(code)
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><soap:Fault><faultcode>%s</faultcode><faultstring>%s</faultstring></soap:Fault></soap:Body></soap:Envelope>`,
+		faultCode, escapeXML(faultString))
+}
+
+// findByIdentifier returns the patient with the identifier issued by the authority (an EMPI organisation code)
+func (sim *Simulator) findByIdentifier(authority string, value string) []*Candidate {
+	system := authority
+	if uri := lookupFromEmpiOrgCode(authority).ToURI(); uri != "" {
+		system = uri
+	}
+	sim.mu.RLock()
+	defer sim.mu.RUnlock()
+	for _, pt := range sim.patients {
+		for _, id := range pt.GetIdentifiers() {
+			if id.GetSystem() == system && strings.EqualFold(id.GetValue(), value) {
+				return []*Candidate{{Patient: pt, Score: 100}}
+			}
+		}
+	}
+	return nil
+}
+
+// findByDemographics returns patients with the surname specified, scored by the proportion of the other
+// demographic criteria that match
+func (sim *Simulator) findByDemographics(params map[string]string) []*Candidate {
+	surname := params["@PID.5.1.1"]
+	if surname == "" {
+		return nil
+	}
+	sim.mu.RLock()
+	defer sim.mu.RUnlock()
+	var result []*Candidate
+	for _, pt := range sim.patients {
+		if !strings.EqualFold(pt.GetLastname(), surname) {
+			continue
+		}
+		criteria, matches := 1, 1
+		match := func(param string, value string) {
+			if v := params[param]; v != "" {
+				criteria++
+				if strings.EqualFold(strings.ReplaceAll(v, " ", ""), strings.ReplaceAll(value, " ", "")) {
+					matches++
+				}
+			}
+		}
+		firstname := strings.Fields(pt.GetFirstnames())
+		if len(firstname) > 0 {
+			match("@PID.5.2", firstname[0])
+		} else {
+			match("@PID.5.2", "")
+		}
+		match("@PID.7.1", formatTimestamp(pt.GetBirthDate()))
+		match("@PID.8", genderCode(pt.GetGender()))
+		postcode := ""
+		if len(pt.GetAddresses()) > 0 {
+			postcode = pt.GetAddresses()[0].GetPostcode()
+		}
+		match("@PID.11.5", postcode)
+		result = append(result, &Candidate{Patient: pt, Score: float64(100 * matches / criteria)})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Score > result[j].Score })
+	return result
+}
+
+func formatTimestamp(ts *timestamp.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return ""
+	}
+	return t.Format("20060102")
+}
+
+func genderCode(g apiv1.Gender) string {
+	switch g {
+	case apiv1.Gender_MALE:
+		return "M"
+	case apiv1.Gender_FEMALE:
+		return "F"
+	}
+	return "U"
+}
+
+// simulatorRequest is the part of a QBP^Q22 request used by the simulator
+type simulatorRequest struct {
+	Body struct {
+		Query struct {
+			QBP struct {
+				MSH struct {
+					MSH10 string `xml:"MSH.10"`
+				} `xml:"MSH"`
+				QPD struct {
+					QPD3 []struct {
+						QIP1 string `xml:"QIP.1"`
+						QIP2 string `xml:"QIP.2"`
+					} `xml:"QPD.3"`
+				} `xml:"QPD"`
+				RCP struct {
+					RCP2 struct {
+						CQ1 int `xml:"CQ.1"`
+					} `xml:"RCP.2"`
+				} `xml:"RCP"`
+			} `xml:"QBP_Q21"`
+		} `xml:"InvokePatientDemographicsQuery"`
+	} `xml:"Body"`
+}
+
+// simulated data for the response template, with values already escaped for XML
+type simulatedResponse struct {
+	DateTime         string
+	MessageControlID string
+	RequestID        string
+	Status           string // query response status: OK or NF
+	Patients         []simulatedPatient
+}
+
+type simulatedPatient struct {
+	Score       float64
+	Identifiers []simulatedIdentifier
+	Surname     string
+	Given       string
+	Further     string
+	Title       string
+	BirthDate   string
+	Gender      string
+	DeathDate   string
+	Addresses   []simulatedAddress
+	Telephones  []simulatedTelephone
+	Emails      []string
+	Surgery     string
+	GP          string
+}
+
+type simulatedIdentifier struct {
+	Value     string
+	Authority string
+	Type      string
+}
+
+type simulatedAddress struct {
+	Address1, Address2, Address3, Country, Postcode, From, To string
+}
+
+type simulatedTelephone struct {
+	Number, Description string
+}
+
+func newSimulatorResponse(requestID string, candidates []*Candidate) *simulatedResponse {
+	result := &simulatedResponse{
+		DateTime:         time.Now().Format("20060102150405"),
+		MessageControlID: uuid.New().String(),
+		RequestID:        escapeXML(requestID),
+		Status:           "OK",
+	}
+	if len(candidates) == 0 {
+		result.Status = "NF"
+	}
+	for _, c := range candidates {
+		pt := c.Patient
+		sp := simulatedPatient{
+			Score:     c.Score,
+			Surname:   escapeXML(strings.ToUpper(pt.GetLastname())),
+			Title:     escapeXML(strings.ToUpper(pt.GetTitle())),
+			BirthDate: formatTimestamp(pt.GetBirthDate()),
+			Gender:    genderCode(pt.GetGender()),
+			DeathDate: formatTimestamp(pt.GetDeceasedDate()),
+			Surgery:   escapeXML(pt.GetSurgery()),
+			GP:        escapeXML(pt.GetGeneralPractitioner()),
+		}
+		if names := strings.SplitN(strings.TrimSpace(strings.ToUpper(pt.GetFirstnames())), " ", 2); len(names) > 0 {
+			sp.Given = escapeXML(names[0])
+			if len(names) > 1 {
+				sp.Further = escapeXML(names[1])
+			}
+		}
+		for _, id := range pt.GetIdentifiers() {
+			authority, code := lookupFromURI(id.GetSystem()), id.GetSystem()
+			if authority != nil {
+				code = authority.empiOrganisationCode()
+			}
+			sp.Identifiers = append(sp.Identifiers, simulatedIdentifier{Value: escapeXML(id.GetValue()), Authority: escapeXML(code), Type: authority.typeCode()})
+		}
+		for _, a := range pt.GetAddresses() {
+			sp.Addresses = append(sp.Addresses, simulatedAddress{
+				Address1: escapeXML(a.GetAddress1()),
+				Address2: escapeXML(a.GetAddress2()),
+				Address3: escapeXML(a.GetAddress3()),
+				Country:  escapeXML(a.GetCountry()),
+				Postcode: escapeXML(a.GetPostcode()),
+				From:     formatTimestamp(a.GetPeriod().GetStart()),
+				To:       formatTimestamp(a.GetPeriod().GetEnd()),
+			})
+		}
+		for _, t := range pt.GetTelephones() {
+			sp.Telephones = append(sp.Telephones, simulatedTelephone{Number: escapeXML(t.GetNumber()), Description: escapeXML(t.GetDescription())})
+		}
+		for _, e := range pt.GetEmails() {
+			sp.Emails = append(sp.Emails, escapeXML(e))
+		}
+		result.Patients = append(result.Patients, sp)
+	}
+	return result
+}
+
+var simulatorResponse = template.Must(template.New("simulator-response").Parse(`<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:xsd="http://www.w3.org/2001/XMLSchema">
+<soap:Body>
+<InvokePatientDemographicsQueryResponse xmlns="http://apps.wales.nhs.uk/mpi/">
+<RSP_K21 xmlns="urn:hl7-org:v2xml">
+  <MSH>
+    <MSH.1>|</MSH.1>
+    <MSH.2>^~\&amp;</MSH.2>
+    <MSH.3><HD.1>100</HD.1></MSH.3>
+    <MSH.4><HD.1>100</HD.1></MSH.4>
+    <MSH.5><HD.1>221</HD.1></MSH.5>
+    <MSH.6><HD.1>221</HD.1></MSH.6>
+    <MSH.7><TS.1>{{.DateTime}}</TS.1></MSH.7>
+    <MSH.9><MSG.1>RSP</MSG.1><MSG.2>K22</MSG.2><MSG.3>RSP_K21</MSG.3></MSH.9>
+    <MSH.10>{{.MessageControlID}}</MSH.10>
+    <MSH.11><PT.1>T</PT.1></MSH.11>
+    <MSH.12><VID.1>2.5</VID.1></MSH.12>
+    <MSH.17>GBR</MSH.17>
+  </MSH>
+  <MSA><MSA.1>AA</MSA.1><MSA.2>{{.RequestID}}</MSA.2></MSA>
+  <QAK><QAK.1>PatientQuery</QAK.1><QAK.2>{{.Status}}</QAK.2></QAK>
+{{- range .Patients}}
+  <RSP_K21.QUERY_RESPONSE>
+    <PID>
+      <PID.1>1</PID.1>
+{{- range .Identifiers}}
+      <PID.3><CX.1>{{.Value}}</CX.1><CX.4><HD.1>{{.Authority}}</HD.1></CX.4><CX.5>{{.Type}}</CX.5></PID.3>
+{{- end}}
+      <PID.5><XPN.1><FN.1>{{.Surname}}</FN.1></XPN.1><XPN.2>{{.Given}}</XPN.2><XPN.3>{{.Further}}</XPN.3><XPN.5>{{.Title}}</XPN.5><XPN.7>L</XPN.7></PID.5>
+      <PID.7><TS.1>{{.BirthDate}}</TS.1></PID.7>
+      <PID.8>{{.Gender}}</PID.8>
+{{- range .Addresses}}
+      <PID.11><XAD.1><SAD.1>{{.Address1}}</SAD.1></XAD.1><XAD.2>{{.Address2}}</XAD.2><XAD.3>{{.Address3}}</XAD.3><XAD.4>{{.Country}}</XAD.4><XAD.5>{{.Postcode}}</XAD.5><XAD.7>H</XAD.7><XAD.13>{{.From}}</XAD.13><XAD.14>{{.To}}</XAD.14></PID.11>
+{{- end}}
+{{- range .Telephones}}
+      <PID.13 LongName="{{.Description}}"><XTN.1>{{.Number}}</XTN.1></PID.13>
+{{- end}}
+{{- range .Emails}}
+      <PID.13><XTN.2>NET</XTN.2><XTN.4>{{.}}</XTN.4></PID.13>
+{{- end}}
+{{- if .DeathDate}}
+      <PID.29><TS.1>{{.DeathDate}}</TS.1></PID.29>
+{{- end}}
+    </PID>
+    <PD1>
+      <PD1.3><XON.3>{{.Surgery}}</XON.3></PD1.3>
+      <PD1.4><XCN.1>{{.GP}}</XCN.1></PD1.4>
+    </PD1>
+    <QRI><QRI.1>{{.Score}}</QRI.1></QRI>
+  </RSP_K21.QUERY_RESPONSE>
+{{- end}}
+</RSP_K21>
+</InvokePatientDemographicsQueryResponse>
+</soap:Body>
+</soap:Envelope>
+`))
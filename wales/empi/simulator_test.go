@@ -0,0 +1,95 @@
+package empi
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/resilience"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSimulator(t *testing.T) {
+	sim := NewSimulator()
+	if err := sim.LoadFixtures("testdata/patients"); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(sim)
+	defer srv.Close()
+	resilience.Configure(BackendName, resilience.Policy{MaxAttempts: 1, FailureThreshold: 1000, OpenTimeout: time.Second})
+	defer resilience.Configure(BackendName, resilience.DefaultPolicy)
+	app := &App{EndpointURL: srv.URL, ProcessingID: "T", TimeoutSeconds: 5}
+	ctx := context.Background()
+
+	// lookup by identifier, round-tripping the request template and response parser
+	pt, err := app.GetEMPIRequest(ctx, &apiv1.Identifier{System: identifiers.NHSNumber, Value: "9990000034"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pt.GetLastname() != "O'BRIEN" || pt.GetFirstnames() != "SIAN" || pt.GetGender() != apiv1.Gender_FEMALE || pt.GetDeceasedDate() == nil || pt.GetSurgery() != "W95623" {
+		t.Fatalf("incorrect patient: %v", pt)
+	}
+	if ids, ok := pt.GetIdentifiersForSystem(identifiers.AneurinBevanCRN); !ok || ids[0].GetValue() != "A123456" {
+		t.Fatalf("missing hospital identifier: %v", pt.GetIdentifiers())
+	}
+	pt, err = app.GetEMPIRequest(ctx, &apiv1.Identifier{System: identifiers.CardiffAndValeCRN, Value: "X100001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pt.GetFirstnames() != "JOHN DAVID" || len(pt.GetTelephones()) != 1 || len(pt.GetEmails()) != 1 || pt.GetAddresses()[0].GetPeriod().GetStart() == nil {
+		t.Fatalf("incorrect patient: %v", pt)
+	}
+	if _, err := app.GetEMPIRequest(ctx, &apiv1.Identifier{System: identifiers.NHSNumber, Value: "9990000042"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+
+	// demographic search
+	dob, _ := ptypes.TimestampProto(time.Date(1972, 3, 15, 0, 0, 0, 0, time.UTC))
+	candidates, err := app.SearchPatients(ctx, &apiv1.PatientSearchRequest{LastName: "smith", BirthDate: dob, Gender: apiv1.Gender_FEMALE, Postcode: "CF5 2AA"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 2 || candidates[0].Patient.GetFirstnames() != "JANE" || candidates[0].Score != 100 || candidates[1].Score >= 100 {
+		t.Fatalf("incorrect candidates: %v", candidates)
+	}
+
+	// injected not-found responses and faults
+	sim.NotFoundRate = 1
+	if _, err := app.GetEMPIRequest(ctx, &apiv1.Identifier{System: identifiers.NHSNumber, Value: "9990000034"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+	sim.NotFoundRate, sim.FaultRate = 0, 1
+	if _, err := app.GetEMPIRequest(ctx, &apiv1.Identifier{System: identifiers.NHSNumber, Value: "9990000034"}); err == nil {
+		t.Fatal("expected simulated fault")
+	}
+}
+
+func TestCacheResponses(t *testing.T) {
+	sim := NewSimulator()
+	if err := sim.LoadFixtures("testdata/patients"); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(sim)
+	defer srv.Close()
+	resilience.Configure(BackendName, resilience.Policy{MaxAttempts: 1, FailureThreshold: 1000, OpenTimeout: time.Second})
+	defer resilience.Configure(BackendName, resilience.DefaultPolicy)
+	app := &App{}
+	app.Reconfigure(srv.URL, "T", 5, time.Hour)
+	ctx := context.Background()
+	id := &apiv1.Identifier{System: identifiers.NHSNumber, Value: "9990000034"}
+	if _, err := app.GetEMPIRequest(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if app.ItemCount() != 1 {
+		t.Fatalf("expected response to be cached, got %d items", app.ItemCount())
+	}
+	sim.FaultRate = 1
+	if pt, err := app.GetEMPIRequest(ctx, id); err != nil || pt.GetLastname() != "O'BRIEN" {
+		t.Fatalf("cached patient not served: %v %v", pt, err)
+	}
+}
@@ -2,13 +2,17 @@ package nadex
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/wardle/concierge/apiv1"
+	"github.com/wardle/concierge/fake"
 	"github.com/wardle/concierge/identifiers"
+	"github.com/wardle/concierge/resilience"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -19,6 +23,9 @@ import (
 	ldap "gopkg.in/ldap.v3"
 )
 
+// BackendName is the name of the NADEX directory backend for retries and circuit breaking, and for health checks
+const BackendName = "nadex"
+
 const (
 	krbConfig = `[libdefaults]
 default_real = CYMRU.NHS.UK
@@ -45,13 +52,33 @@ type App struct {
 	Username string
 	Password string
 	Fake     bool
+	FakeData *fake.Data // synthetic practitioners served in fake mode; default data are used if nil
+
+	mu sync.RWMutex // protects credentials when changed at runtime
+}
+
+// SetCredentials changes the fallback credentials used for directory lookups.
+// This is safe to call while the application is serving requests.
+func (app *App) SetCredentials(username string, password string) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	app.Username = username
+	app.Password = password
+	log.Printf("nadex: updated credentials for directory lookups (username: '%s')", username)
+}
+
+// credentials returns the current fallback credentials for directory lookups
+func (app *App) credentials() (username string, password string) {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+	return app.Username, app.Password
 }
 
 var _ apiv1.PractitionerDirectoryServer = (*App)(nil)
 
 // RegisterServer registers this server
 func (app *App) RegisterServer(s *grpc.Server) {
-	if app.Username == "" || app.Password == "" {
+	if username, password := app.credentials(); username == "" || password == "" {
 		log.Printf("nadex: warning! no credentials provided for NADEX lookup. ")
 	}
 	if app.Fake {
@@ -68,6 +95,20 @@ func (app *App) RegisterHTTPProxy(ctx context.Context, mux *runtime.ServeMux, en
 // Close closes any linked resources
 func (app *App) Close() error { return nil }
 
+// CheckHealth reports whether the application is configured to perform directory lookups
+func (app *App) CheckHealth(ctx context.Context) error {
+	if app.Fake {
+		return nil
+	}
+	if state := resilience.Get(BackendName).State(); state == resilience.Open {
+		return fmt.Errorf("directory unavailable: circuit breaker %s", state)
+	}
+	if username, password := app.credentials(); username == "" || password == "" {
+		return errors.New("no credentials configured for directory lookups")
+	}
+	return nil
+}
+
 // SearchPractitioner permits a search for a practitioner
 // this currently only supports search by username!
 // TODO: implement search by name
@@ -111,63 +152,17 @@ func (app *App) GetPractitioner(ctx context.Context, r *apiv1.Identifier) (*apiv
 		BaseDN:   "OU=Users,DC=cymru,DC=nhs,DC=uk",
 		Security: auth.SecurityNone,
 	}
-	if app.Username == "" {
+	username, password := app.credentials()
+	if username == "" {
 		return nil, fmt.Errorf("nadex: no credentials provided for directory lookup")
 	}
 	// for the moment, we use the fallback username/password configured - TODO: use user who is making request's own credentials
-	auth, err := auth.Authenticate(config, app.Username, app.Password)
-	if err != nil {
-		return nil, err
-	}
-	if auth == false {
-		log.Printf("nadex: failed to login for user %s", app.Username)
-		return nil, status.Errorf(codes.Unavailable, "failed to login for user %s", app.Username)
-	}
-	conn, err := config.Connect()
-	if err != nil {
-		return nil, err
-	}
-	defer conn.Conn.Close()
-	// perform bind
-	upn, err := config.UPN(app.Username)
-	if err != nil {
-		return nil, err
-	}
-	success, err := conn.Bind(upn, app.Password)
-	if err != nil {
-		return nil, err
-	}
-	if !success {
-		return nil, status.Errorf(codes.Unauthenticated, "failed to login for user %s", app.Username)
-	}
-	// search for a user
-	searchRequest := ldap.NewSearchRequest(
-		"dc=cymru,dc=nhs,dc=uk", // The base dn to search
-		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
-		fmt.Sprintf("(&(objectClass=User)(sAMAccountName=%s))", r.Value), // The filter to apply
-		// A list attributes to retrieve
-		[]string{
-			"sAMAccountName",       // username
-			"displayNamePrintable", // full name including title
-			"sn",                   // surname
-			"givenName",            // given names
-			"mail",                 // email
-			"title",                // job title, not name prefix
-			"photo",
-			"physicalDeliveryOfficeName",
-			"postalAddress", "streetAddress",
-			"l",  // l=city
-			"st", // state/province
-			"postalCode", "telephoneNumber",
-			"mobile",
-			"company",
-			"department",
-			"wWWHomePage",
-			"postOfficeBox", // appears to be used for professional registration e.g. GMC: 4624000
-		},
-		nil,
-	)
-	sr, err := conn.Conn.Search(searchRequest)
+	var sr *ldap.SearchResult
+	err := resilience.Get(BackendName).Do(ctx, true, func(ctx context.Context) error {
+		var err error
+		sr, err = search(config, username, password, r.Value)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -224,33 +219,96 @@ func (app *App) GetPractitioner(ctx context.Context, r *apiv1.Identifier) (*apiv
 	return user, nil
 }
 
-// GetFakePractitioner returns a fake practitioner, useful in testing without a live backend service
-func (app *App) GetFakePractitioner(ctx context.Context, r *apiv1.Identifier) (*apiv1.Practitioner, error) {
-	p := &apiv1.Practitioner{
-		Active: true,
-		Emails: []string{"wibble@wobble.org"},
-		Names: []*apiv1.HumanName{
-			{Given: "Fred", Family: "Flintstone", Prefixes: []string{"Mr"}},
-		},
-		Roles: []*apiv1.PractitionerRole{
-			{Role: &apiv1.Role{JobTitle: "Consultant Neurologist"}},
-		},
-		Identifiers: []*apiv1.Identifier{
-			{System: identifiers.CymruUserID, Value: r.GetValue()},
-			{System: identifiers.GMCNumber, Value: "4624000"},
+// search searches the directory for the specified user, binding using the credentials given
+func search(config *auth.Config, username string, password string, value string) (*ldap.SearchResult, error) {
+	auth, err := auth.Authenticate(config, username, password)
+	if err != nil {
+		return nil, err
+	}
+	if auth == false {
+		log.Printf("nadex: failed to login for user %s", username)
+		// not retried, to avoid locking the account
+		return nil, resilience.Permanent(status.Errorf(codes.Unavailable, "failed to login for user %s", username))
+	}
+	conn, err := config.Connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Conn.Close()
+	// perform bind
+	upn, err := config.UPN(username)
+	if err != nil {
+		return nil, err
+	}
+	success, err := conn.Bind(upn, password)
+	if err != nil {
+		return nil, err
+	}
+	if !success {
+		return nil, status.Errorf(codes.Unauthenticated, "failed to login for user %s", username)
+	}
+	// search for a user
+	searchRequest := ldap.NewSearchRequest(
+		"dc=cymru,dc=nhs,dc=uk", // The base dn to search
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		fmt.Sprintf("(&(objectClass=User)(sAMAccountName=%s))", value), // The filter to apply
+		// A list attributes to retrieve
+		[]string{
+			"sAMAccountName",       // username
+			"displayNamePrintable", // full name including title
+			"sn",                   // surname
+			"givenName",            // given names
+			"mail",                 // email
+			"title",                // job title, not name prefix
+			"photo",
+			"physicalDeliveryOfficeName",
+			"postalAddress", "streetAddress",
+			"l",  // l=city
+			"st", // state/province
+			"postalCode", "telephoneNumber",
+			"mobile",
+			"company",
+			"department",
+			"wWWHomePage",
+			"postOfficeBox", // appears to be used for professional registration e.g. GMC: 4624000
 		},
+		nil,
+	)
+	return conn.Conn.Search(searchRequest)
+}
+
+// GetFakePractitioner returns a synthetic practitioner, useful in testing without a live backend service
+func (app *App) GetFakePractitioner(ctx context.Context, r *apiv1.Identifier) (*apiv1.Practitioner, error) {
+	p, found := app.fakeData().Practitioner(r)
+	if !found && app.FakeData == nil {
+		p, found = fake.AnyPractitioner(r), true
+	}
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "practitioner %s|%s not found", r.GetSystem(), r.GetValue())
 	}
 	log.Printf("nadex: returning fake practitioner: %+v", p)
 	return p, nil
 }
 
+// fakeData returns the synthetic data served in fake mode
+func (app *App) fakeData() *fake.Data {
+	if app.FakeData != nil {
+		return app.FakeData
+	}
+	return fake.Default()
+}
+
 // Authenticate authenticates a user against the NHS Wales' directory service
 func (app *App) Authenticate(id *apiv1.Identifier, credential string) (bool, error) {
 	if id.GetSystem() != identifiers.CymruUserID {
 		return false, fmt.Errorf("nadex: unsupported uri: %s", id.GetSystem())
 	}
 	if app.Fake {
-		return credential == "password", nil
+		if app.FakeData == nil { // any user may log in using the default data
+			return credential == "password", nil
+		}
+		_, found := app.FakeData.Practitioner(id)
+		return found && credential == "password", nil
 	}
 	cfg, err := config.NewConfigFromString(krbConfig)
 	if err != nil {